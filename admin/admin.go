@@ -0,0 +1,204 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cache"
+)
+
+// 返回管理接口的处理器，可以挂载在任意路径前缀下（使用http.StripPrefix）：
+//
+//	GET    /groups                        列出所有命名空间
+//	GET    /groups/{group}/stats          获取命名空间的统计信息
+//	GET    /groups/{group}/hotkeys?n=N    获取最多N个热点key，默认为10
+//	GET    /groups/{group}/keys/{key}     获取key对应的数据，未命中时加载，key不存在时返回404；带有?peek=1时只查找缓存
+//	PUT    /groups/{group}/keys/{key}     写入key对应的数据，请求体为数据
+//	DELETE /groups/{group}/keys/{key}     从缓存中移除key
+//	POST   /groups/{group}/clear          清空命名空间的缓存
+//	POST   /groups/{group}/resize?bytes=N 调整命名空间的缓存容量
+func NewHandler() http.Handler {
+	return http.HandlerFunc(serveHTTP)
+}
+
+// 根据路径与请求方法分发请求
+func serveHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 4)
+	if parts[0] != "groups" {
+		http.NotFound(w, r)
+		return
+	}
+
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		listGroups(w, r)
+		return
+	}
+
+	g := cache.GetGroup(parts[1])
+	if g == nil {
+		http.Error(w, "no such group: "+parts[1], http.StatusNotFound)
+		return
+	}
+
+	var handler func(w http.ResponseWriter, r *http.Request, g *cache.Group)
+	var method string
+	switch {
+	case len(parts) == 3 && parts[2] == "stats":
+		handler, method = stats, http.MethodGet
+	case len(parts) == 3 && parts[2] == "hotkeys":
+		handler, method = hotKeys, http.MethodGet
+	case len(parts) == 3 && parts[2] == "clear":
+		handler, method = clear, http.MethodPost
+	case len(parts) == 3 && parts[2] == "resize":
+		handler, method = resize, http.MethodPost
+	case len(parts) == 4 && parts[2] == "keys" && parts[3] != "":
+		method = r.Method
+		switch r.Method {
+		case http.MethodGet:
+			handler = getKey
+		case http.MethodPut:
+			handler = setKey
+		case http.MethodDelete:
+			handler = removeKey
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	handler(w, r, g)
+}
+
+// 列出所有命名空间
+func listGroups(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, cache.GroupNames())
+}
+
+// 获取命名空间的统计信息
+func stats(w http.ResponseWriter, r *http.Request, g *cache.Group) {
+	writeJSON(w, g.Stats())
+}
+
+// 获取热点key
+func hotKeys(w http.ResponseWriter, r *http.Request, g *cache.Group) {
+	n := 10
+	if s := r.URL.Query().Get("n"); s != "" {
+		var err error
+		if n, err = strconv.Atoi(s); err != nil || n <= 0 {
+			http.Error(w, "n must be a positive integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	keys := g.HottestKeys(n)
+	if keys == nil {
+		http.Error(w, "hot key tracking is not enabled", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, keys)
+}
+
+// 获取key对应的数据
+func getKey(w http.ResponseWriter, r *http.Request, g *cache.Group) {
+	key := keyOf(r)
+
+	if r.URL.Query().Get("peek") == "1" {
+		value, ok := g.Peek(key)
+		if !ok {
+			http.Error(w, "not cached: "+key, http.StatusNotFound)
+			return
+		}
+		writeValue(w, value)
+		return
+	}
+
+	value, err := g.Get(r.Context(), key)
+	if errors.Is(err, cache.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeValue(w, value)
+}
+
+// 写入key对应的数据，开启写穿透或写回时同时写入Store
+func setKey(w http.ResponseWriter, r *http.Request, g *cache.Group) {
+	value, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := g.Set(r.Context(), keyOf(r), value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 从缓存中移除key
+func removeKey(w http.ResponseWriter, r *http.Request, g *cache.Group) {
+	if !g.Remove(keyOf(r)) {
+		http.Error(w, "not cached: "+keyOf(r), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 清空命名空间的缓存
+func clear(w http.ResponseWriter, r *http.Request, g *cache.Group) {
+	g.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 调整命名空间的缓存容量
+func resize(w http.ResponseWriter, r *http.Request, g *cache.Group) {
+	bytes, err := strconv.ParseInt(r.URL.Query().Get("bytes"), 10, 64)
+	if err != nil || bytes < 0 {
+		http.Error(w, "bytes must be a non-negative integer", http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, map[string]int{"evicted": g.Resize(bytes)})
+}
+
+// 获取路径中的key，key中可以包含"/"
+func keyOf(r *http.Request) string {
+	return strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 4)[3]
+}
+
+// 以JSON格式写入响应
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// 以原始字节写入数据
+func writeValue(w http.ResponseWriter, value cache.ByteView) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(value.ByteSlice())
+}
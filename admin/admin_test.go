@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cache"
+)
+
+var db = map[string]string{
+	"Tom":  "630",
+	"Jack": "589",
+	"Sam":  "567",
+}
+
+// 发送请求并返回状态码与响应体
+func do(t *testing.T, server *httptest.Server, method, path string) (int, string) {
+	req, err := http.NewRequest(method, server.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+
+	return res.StatusCode, string(body)
+}
+
+func TestHandler(t *testing.T) {
+	cache.NewGroup("scores-http-admin", 2<<10, cache.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		if v, ok := db[key]; ok {
+			return []byte(v), nil
+		}
+		return nil, cache.ErrNotFound
+	}))
+	server := httptest.NewServer(NewHandler())
+	defer server.Close()
+
+	cases := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{http.MethodGet, "/groups/scores-http-admin/keys/Tom?peek=1", http.StatusNotFound, ""},
+		{http.MethodGet, "/groups/scores-http-admin/keys/Tom", http.StatusOK, "630"},
+		{http.MethodGet, "/groups/scores-http-admin/keys/Tom?peek=1", http.StatusOK, "630"},
+		{http.MethodGet, "/groups/scores-http-admin/keys/Nobody", http.StatusNotFound, ""},
+		{http.MethodDelete, "/groups/scores-http-admin/keys/Tom", http.StatusNoContent, ""},
+		{http.MethodPut, "/groups/scores-http-admin/keys/Sam", http.StatusNoContent, ""},
+		{http.MethodGet, "/groups/scores-http-admin/keys/Sam?peek=1", http.StatusOK, ""},
+		{http.MethodDelete, "/groups/scores-http-admin/keys/Tom", http.StatusNotFound, ""},
+		{http.MethodPost, "/groups/scores-http-admin/resize?bytes=x", http.StatusBadRequest, ""},
+		{http.MethodPost, "/groups/scores-http-admin/resize?bytes=4096", http.StatusOK, "{\"evicted\":0}\n"},
+		{http.MethodPost, "/groups/scores-http-admin/clear", http.StatusNoContent, ""},
+		{http.MethodGet, "/groups/unknown/stats", http.StatusNotFound, ""},
+		{http.MethodGet, "/groups/scores-http-admin/hotkeys", http.StatusNotFound, ""},
+		{http.MethodGet, "/groups/scores-http-admin/clear", http.StatusMethodNotAllowed, ""},
+		{http.MethodGet, "/other", http.StatusNotFound, ""},
+	}
+	for _, tc := range cases {
+		status, body := do(t, server, tc.method, tc.path)
+		if status != tc.status || tc.body != "" && body != tc.body {
+			t.Fatalf("%s %s: expected %d %q but got %d %q", tc.method, tc.path, tc.status, tc.body, status, body)
+		}
+	}
+
+	_, body := do(t, server, http.MethodGet, "/groups")
+	var names []string
+	if err := json.Unmarshal([]byte(body), &names); err != nil || len(names) == 0 {
+		t.Fatalf("failed to list groups: %s, %v", body, err)
+	}
+
+	_, body = do(t, server, http.MethodGet, "/groups/scores-http-admin/stats")
+	var stats cache.Stats
+	if err := json.Unmarshal([]byte(body), &stats); err != nil || stats.Main.Adds != 2 || stats.Main.Misses != 2 {
+		t.Fatalf("unexpected stats %s, %v", body, err)
+	}
+}
+
+func TestHandler_GetterError(t *testing.T) {
+	cache.NewGroup("scores-http-admin-failed", 2<<10, cache.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return nil, errors.New("db unavailable")
+	}))
+	server := httptest.NewServer(NewHandler())
+	defer server.Close()
+
+	// 数据源的其他错误返回500
+	if status, _ := do(t, server, http.MethodGet, "/groups/scores-http-admin-failed/keys/Tom"); status != http.StatusInternalServerError {
+		t.Fatalf("expected %d but got %d", http.StatusInternalServerError, status)
+	}
+}
@@ -0,0 +1,364 @@
+package aof
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"cache/lru"
+)
+
+// 日志中记录的操作类型
+const (
+	opAdd    byte = 1
+	opRemove byte = 2
+	opClear  byte = 3
+)
+
+// 日志超过该大小（单位为字节）时自动压缩
+const defaultCompactThreshold = 64 << 20
+
+// 日志中单个key或value的最大字节数，避免损坏的日志导致分配过多内存
+const maxRecordBytes = 1 << 30
+
+// 打开日志时的可选配置
+type Option func(c *Cache)
+
+// 设置自动压缩的阈值（单位为字节），小于等于0时表示不自动压缩
+func WithCompactThreshold(bytes int64) Option {
+	return func(c *Cache) {
+		c.compactThreshold = bytes
+	}
+}
+
+// 每次写入日志后调用fsync，保证进程所在的机器掉电时也不会丢失写入，但会降低写入速度
+func WithFsync() Option {
+	return func(c *Cache) {
+		c.fsync = true
+	}
+}
+
+// 将lru.Cache的新增与删除操作记录到追加日志中的包装器，重启时通过重放日志恢复缓存。
+// 日志超过阈值时将当前的缓存保存为快照（path.snapshot），并将旧日志轮转为path.1后重新开始记录。
+// 只有通过包装器进行的操作才会被记录，淘汰与过期不会被记录，重放时由缓存自行处理
+type Cache struct {
+	cache *lru.Cache
+
+	// 日志文件的路径
+	path string
+
+	// 自动压缩的阈值
+	compactThreshold int64
+
+	// 每次写入后是否调用fsync
+	fsync bool
+
+	// 保护以下所有字段的互斥锁，保证日志中的操作顺序与应用到缓存的顺序一致
+	mu sync.Mutex
+
+	// 日志文件
+	file *os.File
+
+	// 日志文件的大小
+	size int64
+
+	// 复用的编码缓冲区
+	buf []byte
+}
+
+// 打开path处的日志，依次加载快照与重放日志恢复cache，decode将value的字节转换为lru.Value。
+// value需要实现encoding.BinaryMarshaler接口
+func Open(path string, cache *lru.Cache, decode func(data []byte) (lru.Value, error), opts ...Option) (*Cache, error) {
+	c := &Cache{
+		cache:            cache,
+		path:             path,
+		compactThreshold: defaultCompactThreshold,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if _, err := cache.LoadFromFile(c.snapshotPath(), decode); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	// 重放日志，末尾不完整的记录（例如写入时进程崩溃）被截断
+	size, err := c.replay(file, decode)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(size, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	c.file = file
+	c.size = size
+
+	return c, nil
+}
+
+// 获取被包装的cache
+func (c *Cache) Unwrap() *lru.Cache {
+	return c.cache
+}
+
+// 实现查找功能
+func (c *Cache) Get(key string) (lru.Value, bool) {
+	return c.cache.Get(key)
+}
+
+// 实现新增与修改功能，先写入日志再更新缓存
+func (c *Cache) Add(key string, value lru.Value) error {
+	return c.AddWithTTL(key, value, 0)
+}
+
+// 新增或修改条目，并设置其存活时间，ttl小于等于0时表示永不过期
+func (c *Cache) AddWithTTL(key string, value lru.Value, ttl time.Duration) error {
+	marshaler, ok := value.(encoding.BinaryMarshaler)
+	if !ok {
+		return fmt.Errorf("aof: value of %q does not implement encoding.BinaryMarshaler", key)
+	}
+	data, err := marshaler.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	var expire int64
+	if ttl > 0 {
+		expire = time.Now().Add(ttl).UnixNano()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b := append(c.buf[:0], opAdd)
+	b = appendBytes(b, []byte(key))
+	b = appendBytes(b, data)
+	b = binary.AppendVarint(b, expire)
+	if err := c.write(b); err != nil {
+		return err
+	}
+
+	c.cache.AddWithTTL(key, value, ttl)
+
+	return c.maybeCompact()
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b := append(c.buf[:0], opRemove)
+	b = appendBytes(b, []byte(key))
+	if err := c.write(b); err != nil {
+		return false, err
+	}
+
+	return c.cache.Remove(key), c.maybeCompact()
+}
+
+// 清空缓存，不调用回调函数
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.write(append(c.buf[:0], opClear)); err != nil {
+		return err
+	}
+	c.cache.Clear()
+
+	return nil
+}
+
+// 立即压缩日志
+func (c *Cache) Compact() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.compact()
+}
+
+// 关闭日志文件
+func (c *Cache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.file.Close()
+}
+
+// 快照文件的路径
+func (c *Cache) snapshotPath() string {
+	return c.path + ".snapshot"
+}
+
+// 将一条记录追加到日志，调用方需持有锁
+func (c *Cache) write(b []byte) error {
+	c.buf = b
+
+	n, err := c.file.Write(b)
+	c.size += int64(n)
+	if err != nil {
+		return err
+	}
+
+	if c.fsync {
+		return c.file.Sync()
+	}
+
+	return nil
+}
+
+// 日志超过阈值时压缩日志，调用方需持有锁
+func (c *Cache) maybeCompact() error {
+	if c.compactThreshold <= 0 || c.size < c.compactThreshold {
+		return nil
+	}
+
+	return c.compact()
+}
+
+// 将当前的缓存保存为快照，再将日志轮转为path.1并创建新的日志，调用方需持有锁。
+// 快照写入成功之后旧日志中的操作都已包含在快照中，因此轮转过程中崩溃也不会丢失数据
+func (c *Cache) compact() error {
+	if err := c.cache.SaveToFile(c.snapshotPath()); err != nil {
+		return err
+	}
+
+	if err := c.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(c.path, c.path+".1"); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(c.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	c.file = file
+	c.size = 0
+
+	return nil
+}
+
+// 重放日志中的所有操作，返回最后一条完整记录的结束位置
+func (c *Cache) replay(file *os.File, decode func(data []byte) (lru.Value, error)) (int64, error) {
+	r := &countingReader{r: bufio.NewReader(file)}
+
+	var offset int64
+	for {
+		op, err := r.ReadByte()
+		if err != nil {
+			// 读到文件末尾
+			return offset, nil
+		}
+
+		switch op {
+		case opAdd:
+			key, err := readBytes(r)
+			if err != nil {
+				return offset, nil
+			}
+			data, err := readBytes(r)
+			if err != nil {
+				return offset, nil
+			}
+			expire, err := binary.ReadVarint(r)
+			if err != nil {
+				return offset, nil
+			}
+
+			value, err := decode(data)
+			if err != nil {
+				return offset, err
+			}
+
+			if expire == 0 {
+				c.cache.Add(string(key), value)
+			} else if ttl := time.Until(time.Unix(0, expire)); ttl > 0 {
+				c.cache.AddWithTTL(string(key), value, ttl)
+			} else {
+				// 已过期的条目可能在此之前被加入过
+				c.cache.Remove(string(key))
+			}
+		case opRemove:
+			key, err := readBytes(r)
+			if err != nil {
+				return offset, nil
+			}
+			c.cache.Remove(string(key))
+		case opClear:
+			c.cache.Clear()
+		default:
+			return offset, fmt.Errorf("aof: unknown operation %d at offset %d", op, offset)
+		}
+
+		offset = r.n
+	}
+}
+
+// 追加带有长度前缀的字节序列
+func appendBytes(b []byte, data []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// 读取带有长度前缀的字节序列
+func readBytes(r *countingReader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxRecordBytes {
+		return nil, fmt.Errorf("aof: record too large")
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// 记录已读取字节数的Reader
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+// 实现io.Reader接口
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+
+	return n, err
+}
+
+// 实现io.ByteReader接口
+func (r *countingReader) ReadByte() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err == nil {
+		r.n++
+	}
+
+	return b, err
+}
@@ -0,0 +1,124 @@
+package aof
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"cache/lru"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+// 实现encoding.BinaryMarshaler接口
+func (s String) MarshalBinary() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// 将日志中的字节转换为String
+func decodeString(data []byte) (lru.Value, error) {
+	return String(data), nil
+}
+
+func TestCache_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.aof")
+
+	c, err := Open(path, lru.New(int64(0), nil), decodeString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Add("k1", String("v1"))
+	c.Add("k2", String("v2"))
+	c.AddWithTTL("k3", String("v3"), time.Hour)
+	c.AddWithTTL("k4", String("v4"), time.Nanosecond)
+	c.Remove("k2")
+	c.Add("k1", String("v1.1"))
+	c.Close()
+
+	restored, err := Open(path, lru.New(int64(0), nil), decodeString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+
+	expect := []string{"k1", "k3"}
+	if keys := restored.Unwrap().Keys(); !reflect.DeepEqual(expect, keys) {
+		t.Fatalf("expect keys equals to %s but got %s", expect, keys)
+	}
+	if v, ok := restored.Get("k1"); !ok || v.(String) != "v1.1" {
+		t.Fatalf("expected k1 to be v1.1 but got %v", v)
+	}
+}
+
+func TestCache_TruncatedTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.aof")
+
+	c, _ := Open(path, lru.New(int64(0), nil), decodeString)
+	c.Add("k1", String("v1"))
+	c.Add("k2", String("v2"))
+	c.Close()
+
+	// 模拟写入最后一条记录时进程崩溃
+	info, _ := os.Stat(path)
+	os.Truncate(path, info.Size()-1)
+
+	restored, err := Open(path, lru.New(int64(0), nil), decodeString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys := restored.Unwrap().Keys(); !reflect.DeepEqual([]string{"k1"}, keys) {
+		t.Fatalf("expected only k1 but got %s", keys)
+	}
+
+	// 不完整的记录被截断，之后的写入可以正常重放
+	restored.Add("k3", String("v3"))
+	restored.Close()
+
+	again, _ := Open(path, lru.New(int64(0), nil), decodeString)
+	defer again.Close()
+	if keys := again.Unwrap().Keys(); !reflect.DeepEqual([]string{"k3", "k1"}, keys) {
+		t.Fatalf("expected k3 and k1 but got %s", keys)
+	}
+}
+
+func TestCache_Compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.aof")
+
+	c, err := Open(path, lru.New(int64(0), nil), decodeString, WithCompactThreshold(64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		c.Add("k1", String("v1"))
+	}
+	c.Add("k2", String("v2"))
+	c.Close()
+
+	// 超过阈值后日志被压缩为快照，并轮转出path.1
+	if info, err := os.Stat(path); err != nil || info.Size() >= 64 {
+		t.Fatalf("expected compacted log but got %v, %v", info.Size(), err)
+	}
+	for _, name := range []string{path + ".snapshot", path + ".1"} {
+		if _, err := os.Stat(name); err != nil {
+			t.Fatalf("expected %s to exist: %v", name, err)
+		}
+	}
+
+	restored, err := Open(path, lru.New(int64(0), nil), decodeString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+
+	expect := []string{"k2", "k1"}
+	if keys := restored.Unwrap().Keys(); !reflect.DeepEqual(expect, keys) {
+		t.Fatalf("expect keys equals to %s but got %s", expect, keys)
+	}
+}
@@ -0,0 +1,249 @@
+package arc
+
+import (
+	"sync"
+
+	"cache/linkedlist"
+	"cache/lru"
+)
+
+// 与lru包共用Value接口，便于在不同淘汰策略之间切换
+type Value = lru.Value
+
+// 条目所在的链表
+type segment int
+
+const (
+	// 只被访问过一次的条目
+	t1 segment = iota
+	// 被访问过至少两次的条目
+	t2
+	// 最近从t1中淘汰的key（只保留key，不保留value）
+	b1
+	// 最近从t2中淘汰的key（只保留key，不保留value）
+	b2
+)
+
+// 链表节点
+type entry struct {
+	key   string
+	value Value
+
+	// 条目占用的字节数，幽灵条目也保留该值以便按字节调整目标大小
+	cost int64
+
+	// 条目所在的链表
+	segment segment
+}
+
+// ARC cache，在最近访问与访问频次之间自适应调整，可安全地被多个goroutine并发使用
+type Cache struct {
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 缓存的最大容量（单位为字节）
+	capacity int64
+
+	// t1的目标大小（单位为字节），随幽灵命中自适应调整
+	p int64
+
+	// 四个链表，越靠前越是最近访问的
+	lists [4]*linkedlist.List[entry]
+
+	// 四个链表各自占用的字节数
+	sizes [4]int64
+
+	// 存储key与链表节点映射关系的哈希表，包含幽灵条目
+	cache map[string]*linkedlist.Element[entry]
+
+	// 可选的回调函数，在发生缓存条目清除时被执行
+	// 回调在持有锁的情况下执行，因此不能在回调中再调用Cache的方法
+	OnEvicted func(key string, value Value)
+}
+
+// 实例化ARC cache
+func New(capacity int64, onEvicted func(string, Value)) *Cache {
+	c := &Cache{
+		capacity:  capacity,
+		cache:     make(map[string]*linkedlist.Element[entry]),
+		OnEvicted: onEvicted,
+	}
+	for i := range c.lists {
+		c.lists[i] = linkedlist.New[entry]()
+	}
+
+	return c
+}
+
+// 实现查找功能
+func (c *Cache) Get(key string) (value Value, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.cache[key]
+	if !ok {
+		return nil, false
+	}
+
+	keyValue := &element.Value
+	if keyValue.segment != t1 && keyValue.segment != t2 {
+		// 幽灵条目不保存value，视为未命中
+		return nil, false
+	}
+
+	// 命中的条目被访问了至少两次，移动到t2最前面
+	c.move(element, t2)
+
+	return keyValue.value, true
+}
+
+// 实现新增与修改功能
+func (c *Cache) Add(key string, value Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cost := int64(len(key)) + int64(value.Len())
+
+	element, ok := c.cache[key]
+	if !ok {
+		// 全新的key放入t1最前面
+		c.cache[key] = c.push(&linkedlist.Element[entry]{Value: entry{key: key, value: value, cost: cost}}, t1)
+		c.replace(false)
+		c.trimGhosts()
+		return
+	}
+
+	keyValue := &element.Value
+	ghostB2 := false
+
+	switch keyValue.segment {
+	case b1:
+		// 命中b1说明t1过小，增大t1的目标大小
+		c.p = min(c.capacity, c.p+max(ratio(c.sizes[b2], c.sizes[b1])*cost, cost))
+	case b2:
+		// 命中b2说明t2过小，减小t1的目标大小
+		c.p = max(0, c.p-max(ratio(c.sizes[b1], c.sizes[b2])*cost, cost))
+		ghostB2 = true
+	}
+
+	// 更新键值对，并移动到t2最前面
+	c.lists[keyValue.segment].Remove(element)
+	c.sizes[keyValue.segment] -= keyValue.cost
+	keyValue.value = value
+	keyValue.cost = cost
+	c.push(element, t2)
+
+	c.replace(ghostB2)
+	c.trimGhosts()
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.cache[key]
+	if !ok {
+		return false
+	}
+
+	keyValue := &element.Value
+	c.lists[keyValue.segment].Remove(element)
+	c.sizes[keyValue.segment] -= keyValue.cost
+	delete(c.cache, key)
+
+	// 幽灵条目不属于缓存内容，删除时不调用回调函数
+	if keyValue.segment != t1 && keyValue.segment != t2 {
+		return false
+	}
+
+	if c.OnEvicted != nil {
+		c.OnEvicted(key, keyValue.value)
+	}
+
+	return true
+}
+
+// 获取缓存的条目数量，不包含幽灵条目
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lists[t1].Len() + c.lists[t2].Len()
+}
+
+// 将条目插入指定链表的最前面，调用方需持有锁
+func (c *Cache) push(element *linkedlist.Element[entry], segment segment) *linkedlist.Element[entry] {
+	element.Value.segment = segment
+	c.sizes[segment] += element.Value.cost
+
+	return c.lists[segment].PushFrontElement(element)
+}
+
+// 将条目移动到指定链表的最前面，调用方需持有锁
+func (c *Cache) move(element *linkedlist.Element[entry], segment segment) {
+	keyValue := &element.Value
+	if keyValue.segment == segment {
+		c.lists[segment].MoveToFront(element)
+		return
+	}
+
+	c.lists[keyValue.segment].Remove(element)
+	c.sizes[keyValue.segment] -= keyValue.cost
+	c.push(element, segment)
+}
+
+// 当缓存大小超过容量时，从t1或t2中淘汰条目到对应的幽灵链表，调用方需持有锁
+func (c *Cache) replace(ghostB2 bool) {
+	for c.capacity != 0 && c.capacity < c.sizes[t1]+c.sizes[t2] {
+		from, to := t2, b2
+		if c.lists[t1].Len() > 0 && (c.lists[t2].Len() == 0 || c.sizes[t1] > c.p || (ghostB2 && c.sizes[t1] == c.p)) {
+			from, to = t1, b1
+		}
+
+		element := c.lists[from].Back()
+		keyValue := &element.Value
+		value := keyValue.value
+
+		// 幽灵条目只保留key与占用的字节数
+		keyValue.value = nil
+		c.move(element, to)
+
+		if c.OnEvicted != nil {
+			c.OnEvicted(keyValue.key, value)
+		}
+	}
+}
+
+// 限制幽灵链表的大小：t1+b1不超过容量，四个链表之和不超过两倍容量，调用方需持有锁
+func (c *Cache) trimGhosts() {
+	if c.capacity == 0 {
+		return
+	}
+
+	for c.lists[b1].Len() > 0 && c.sizes[t1]+c.sizes[b1] > c.capacity {
+		c.removeGhost(b1)
+	}
+
+	for c.lists[b2].Len() > 0 && c.sizes[t1]+c.sizes[t2]+c.sizes[b1]+c.sizes[b2] > 2*c.capacity {
+		c.removeGhost(b2)
+	}
+}
+
+// 删除幽灵链表中最久的条目，调用方需持有锁
+func (c *Cache) removeGhost(segment segment) {
+	element := c.lists[segment].Back()
+	keyValue := &element.Value
+	c.lists[segment].Remove(element)
+	c.sizes[segment] -= keyValue.cost
+	delete(c.cache, keyValue.key)
+}
+
+// 计算a/b，b为0时返回0
+func ratio(a, b int64) int64 {
+	if b == 0 {
+		return 0
+	}
+
+	return a / b
+}
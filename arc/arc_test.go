@@ -0,0 +1,106 @@
+package arc
+
+import (
+	"reflect"
+	"testing"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+func TestCache_Get(t *testing.T) {
+	arc := New(int64(0), nil)
+	arc.Add("peng", String("chang"))
+	if v, ok := arc.Get("peng"); !ok || string(v.(String)) != "chang" {
+		t.Fatalf("cache hit failed")
+	}
+
+	if _, ok := arc.Get("liu"); ok {
+		t.Fatalf("cache miss failed")
+	}
+}
+
+func TestOnEvicted(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	arc := New(int64(10), callback)
+	arc.Add("key1", String("123456"))
+	arc.Add("k2", String("k2"))
+	arc.Add("k3", String("k3"))
+	arc.Add("k4", String("k4"))
+
+	expect := []string{"key1", "k2"}
+
+	if !reflect.DeepEqual(expect, keys) {
+		t.Fatalf("Call OnEvicted failed, expect keys equals to %s but got %s", expect, keys)
+	}
+}
+
+func TestCache_FrequentKeysSurviveScan(t *testing.T) {
+	arc := New(int64(40), nil)
+
+	// k1与k2被访问两次，进入t2
+	for _, key := range []string{"k1", "k2"} {
+		arc.Add(key, String("vv"))
+		arc.Get(key)
+	}
+
+	// 只访问一次的扫描key只会在t1中相互淘汰
+	for _, key := range []string{"s1", "s2", "s3", "s4", "s5", "s6", "s7", "s8"} {
+		arc.Add(key, String("vv"))
+	}
+
+	for _, key := range []string{"k1", "k2"} {
+		if _, ok := arc.Get(key); !ok {
+			t.Fatalf("frequent key %s should survive the scan", key)
+		}
+	}
+}
+
+func TestCache_GhostHit(t *testing.T) {
+	arc := New(int64(8), nil)
+	arc.Add("k1", String("vv"))
+	arc.Add("k2", String("vv"))
+	arc.Get("k2")
+	arc.Add("k3", String("vv"))
+
+	// t1超过目标大小，k1被淘汰到b1
+	if _, ok := arc.Get("k1"); ok {
+		t.Fatalf("k1 should be evicted")
+	}
+	if arc.lists[b1].Len() != 1 {
+		t.Fatalf("k1 should be recorded in b1")
+	}
+
+	// 再次添加k1命中b1，t1的目标大小增大，k1进入t2
+	arc.Add("k1", String("vv"))
+	if arc.p == 0 {
+		t.Fatalf("ghost hit in b1 should increase p")
+	}
+	if element := arc.cache["k1"]; element.Value.segment != t2 {
+		t.Fatalf("k1 should be promoted to t2")
+	}
+	if arc.sizes[t1]+arc.sizes[t2] > arc.capacity {
+		t.Fatalf("cache size %d exceeds capacity %d", arc.sizes[t1]+arc.sizes[t2], arc.capacity)
+	}
+}
+
+func TestCache_Remove(t *testing.T) {
+	arc := New(int64(0), nil)
+	arc.Add("key1", String("value1"))
+	arc.Add("key2", String("value2"))
+	arc.Get("key1")
+
+	if !arc.Remove("key1") || arc.Remove("key1") {
+		t.Fatalf("Remove key1 failed")
+	}
+	if arc.Len() != 1 || arc.sizes[t1]+arc.sizes[t2] != int64(len("key2")+len("value2")) {
+		t.Fatalf("expected 1 entry of 10 bytes")
+	}
+}
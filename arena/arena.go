@@ -0,0 +1,315 @@
+package arena
+
+import (
+	"errors"
+	"math/bits"
+	"sync"
+)
+
+const (
+	// 默认的slab大小（单位为字节）
+	DefaultSlabSize = 1 << 20
+
+	// 最小的块大小（单位为字节），key与value一起占用的空间向上取整为该值的2的幂次倍
+	minChunk = 64
+)
+
+// key与value一起超过slab大小
+var ErrTooLarge = errors.New("arena: entry too large")
+
+// 实例化Cache时的可选配置
+type Option func(c *Cache)
+
+// 设置每个slab的大小（单位为字节），向上取整为2的幂次，不小于最小的块大小
+func WithSlabSize(bytes int) Option {
+	return func(c *Cache) {
+		c.slabSize = max(1<<bits.Len(uint(bytes-1)), minChunk)
+	}
+}
+
+// 索引中的节点，只包含整数，因此GC不需要扫描节点数组
+type node struct {
+	// 数据在slab中的位置
+	location uint64
+
+	// key与value的长度
+	keyLen, valueLen uint32
+
+	// 数据所在的块的大小等级
+	class uint8
+
+	// LRU链表中的前驱与后继节点的下标，为-1时表示没有，空闲节点通过next串联
+	prev, next int32
+}
+
+// 将key与value存储在少量大块字节切片（slab）中的LRU cache，value不再是单独的堆对象，
+// 节点中只保存数据在slab中的位置而不保存指针，缓存大量条目时可以显著减少GC需要扫描的对象。
+// slab按伙伴系统分配，释放的块与相邻的空闲块合并。可安全地被多个goroutine并发使用
+type Cache struct {
+	// 每个slab的大小
+	slabSize int
+
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 缓存的最大容量（单位为字节），决定了最多可以分配的slab数量
+	capacity int64
+
+	// 已分配的slab，按需分配直到达到容量
+	slabs [][]byte
+
+	// 每个大小等级的空闲块的位置
+	free []map[uint64]struct{}
+
+	// 已使用的块占用的字节数
+	size int64
+
+	// 存储key与节点下标映射关系的哈希表
+	index map[string]int32
+
+	// 节点数组
+	nodes []node
+
+	// 空闲节点链表的头节点，为-1时表示没有空闲节点
+	freeNodes int32
+
+	// LRU链表的头节点与尾节点，头节点是最近访问的
+	head, tail int32
+}
+
+// 实例化Cache，capacity为缓存的最大容量（单位为字节），至少可以分配一个slab
+func New(capacity int64, opts ...Option) *Cache {
+	c := &Cache{
+		slabSize:  DefaultSlabSize,
+		capacity:  capacity,
+		index:     make(map[string]int32),
+		freeNodes: -1,
+		head:      -1,
+		tail:      -1,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.free = make([]map[uint64]struct{}, bits.Len(uint(c.slabSize/minChunk)))
+	for i := range c.free {
+		c.free[i] = make(map[uint64]struct{})
+	}
+
+	return c
+}
+
+// 实现查找功能，返回数据的副本
+func (c *Cache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i, ok := c.index[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.moveToFront(i)
+	n := &c.nodes[i]
+	data := c.chunk(n.location, n.class)
+
+	return append([]byte(nil), data[n.keyLen:n.keyLen+n.valueLen]...), true
+}
+
+// 实现新增与修改功能，slab空间不足时按LRU顺序淘汰条目，key与value一起超过slab大小时返回ErrTooLarge
+func (c *Cache) Add(key string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	length := len(key) + len(value)
+	if length > c.slabSize {
+		return ErrTooLarge
+	}
+
+	if i, ok := c.index[key]; ok {
+		c.removeNode(i)
+	}
+
+	class := classOf(length)
+	location, ok := c.allocate(class)
+	for !ok && c.tail != -1 {
+		c.removeNode(c.tail)
+		location, ok = c.allocate(class)
+	}
+	if !ok {
+		return ErrTooLarge
+	}
+
+	data := c.chunk(location, class)
+	copy(data, key)
+	copy(data[len(key):], value)
+
+	i := c.newNode()
+	c.nodes[i] = node{location: location, keyLen: uint32(len(key)), valueLen: uint32(len(value)), class: class, prev: -1, next: -1}
+	c.pushFront(i)
+	c.index[key] = i
+	c.size += int64(chunkSize(class))
+
+	return nil
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i, ok := c.index[key]
+	if ok {
+		c.removeNode(i)
+	}
+
+	return ok
+}
+
+// 获取缓存的条目数量
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.index)
+}
+
+// 获取已使用的块占用的字节数，由于块大小向上取整，通常大于key与value的长度之和
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.size
+}
+
+// 从索引与LRU链表中删除节点并释放其数据所在的块，调用方需持有锁
+func (c *Cache) removeNode(i int32) {
+	n := c.nodes[i]
+	data := c.chunk(n.location, n.class)
+	delete(c.index, string(data[:n.keyLen]))
+
+	c.unlink(i)
+	c.release(n.location, n.class)
+	c.size -= int64(chunkSize(n.class))
+
+	c.nodes[i] = node{next: c.freeNodes}
+	c.freeNodes = i
+}
+
+// 获取一个空闲节点，没有空闲节点时扩展节点数组，调用方需持有锁
+func (c *Cache) newNode() int32 {
+	if i := c.freeNodes; i != -1 {
+		c.freeNodes = c.nodes[i].next
+		return i
+	}
+
+	c.nodes = append(c.nodes, node{})
+	return int32(len(c.nodes) - 1)
+}
+
+// 将节点插入LRU链表最前面，调用方需持有锁
+func (c *Cache) pushFront(i int32) {
+	n := &c.nodes[i]
+	n.prev = -1
+	n.next = c.head
+	if c.head != -1 {
+		c.nodes[c.head].prev = i
+	}
+	c.head = i
+	if c.tail == -1 {
+		c.tail = i
+	}
+}
+
+// 将节点从LRU链表中摘下，调用方需持有锁
+func (c *Cache) unlink(i int32) {
+	n := &c.nodes[i]
+	if n.prev != -1 {
+		c.nodes[n.prev].next = n.next
+	} else {
+		c.head = n.next
+	}
+	if n.next != -1 {
+		c.nodes[n.next].prev = n.prev
+	} else {
+		c.tail = n.prev
+	}
+}
+
+// 将节点移动到LRU链表最前面，调用方需持有锁
+func (c *Cache) moveToFront(i int32) {
+	if c.head == i {
+		return
+	}
+	c.unlink(i)
+	c.pushFront(i)
+}
+
+// 分配一个大小等级为class的块：从不小于class的最小空闲块中切分，
+// 没有空闲块且未达到容量时分配新的slab，调用方需持有锁
+func (c *Cache) allocate(class uint8) (uint64, bool) {
+	top := uint8(len(c.free) - 1)
+
+	from := class
+	for from <= top && len(c.free[from]) == 0 {
+		from++
+	}
+	if from > top {
+		if int64(len(c.slabs)+1)*int64(c.slabSize) > max(c.capacity, int64(c.slabSize)) {
+			return 0, false
+		}
+		c.slabs = append(c.slabs, make([]byte, c.slabSize))
+		c.free[top][uint64(len(c.slabs)-1)<<32] = struct{}{}
+		from = top
+	}
+
+	var location uint64
+	for location = range c.free[from] {
+		break
+	}
+	delete(c.free[from], location)
+
+	// 切分得到的后一半作为更小一级的空闲块
+	for from > class {
+		from--
+		c.free[from][location+uint64(chunkSize(from))] = struct{}{}
+	}
+
+	return location, true
+}
+
+// 释放块，并与同样空闲的伙伴块逐级合并，调用方需持有锁
+func (c *Cache) release(location uint64, class uint8) {
+	top := uint8(len(c.free) - 1)
+	for class < top {
+		buddy := location ^ uint64(chunkSize(class))
+		if _, ok := c.free[class][buddy]; !ok {
+			break
+		}
+		delete(c.free[class], buddy)
+		location = min(location, buddy)
+		class++
+	}
+
+	c.free[class][location] = struct{}{}
+}
+
+// 获取块对应的字节切片，调用方需持有锁
+func (c *Cache) chunk(location uint64, class uint8) []byte {
+	slab, offset := location>>32, location&(1<<32-1)
+	return c.slabs[slab][offset : offset+uint64(chunkSize(class))]
+}
+
+// 能够容纳length个字节的最小大小等级
+func classOf(length int) uint8 {
+	if length <= minChunk {
+		return 0
+	}
+
+	return uint8(bits.Len(uint(length-1)) - bits.Len(minChunk-1))
+}
+
+// 大小等级对应的块大小
+func chunkSize(class uint8) int {
+	return minChunk << class
+}
@@ -0,0 +1,91 @@
+package arena
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestCache_Get(t *testing.T) {
+	c := New(0)
+	if err := c.Add("peng", []byte("chang")); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := c.Get("peng"); !ok || string(v) != "chang" {
+		t.Fatalf("cache hit peng=chang failed")
+	}
+	if _, ok := c.Get("key2"); ok {
+		t.Fatalf("cache miss key2 failed")
+	}
+
+	// 修改返回的数据不会影响缓存中的数据
+	v, _ := c.Get("peng")
+	v[0] = 'x'
+	if v, _ := c.Get("peng"); string(v) != "chang" {
+		t.Fatalf("cached value should not be mutated")
+	}
+
+	// 修改已有的key
+	c.Add("peng", bytes.Repeat([]byte("v"), 100))
+	if v, _ := c.Get("peng"); len(v) != 100 || c.Len() != 1 || c.Size() != 128 {
+		t.Fatalf("expected updated value in a 128 byte chunk but got %d bytes, size %d", len(v), c.Size())
+	}
+
+	if !c.Remove("peng") || c.Remove("peng") || c.Len() != 0 || c.Size() != 0 {
+		t.Fatalf("Remove failed")
+	}
+}
+
+func TestCache_Evict(t *testing.T) {
+	// 容量只够一个slab，即4个256字节的块
+	c := New(1024, WithSlabSize(1024))
+	value := bytes.Repeat([]byte("v"), 200)
+	for i := 0; i < 4; i++ {
+		c.Add("k"+strconv.Itoa(i), value)
+	}
+	c.Get("k0")
+
+	c.Add("k4", value)
+	if _, ok := c.Get("k1"); ok || c.Len() != 4 {
+		t.Fatalf("expected k1 to be evicted but got %d entries", c.Len())
+	}
+	if _, ok := c.Get("k0"); !ok {
+		t.Fatalf("recently used k0 should be kept")
+	}
+
+	if err := c.Add("large", make([]byte, 1024)); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge but got %v", err)
+	}
+}
+
+func TestCache_Merge(t *testing.T) {
+	c := New(1024, WithSlabSize(1000))
+	for i := 0; i < 16; i++ {
+		c.Add("k"+strconv.Itoa(i), []byte("v"))
+	}
+	for i := 0; i < 16; i++ {
+		c.Remove("k" + strconv.Itoa(i))
+	}
+
+	// 释放的小块合并回整个slab，可以不淘汰任何条目地存入一个slab大小的条目
+	c.Add("small", []byte("v"))
+	c.Remove("small")
+	if err := c.Add("large", make([]byte, 1000)); err != nil || c.Len() != 1 || c.Size() != 1024 {
+		t.Fatalf("freed chunks should be merged, error = %v, size = %d", err, c.Size())
+	}
+}
+
+func BenchmarkCache_Add(b *testing.B) {
+	keys := make([]string, 1<<16)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	c := New(1 << 22)
+	value := make([]byte, 100)
+
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		c.Add(keys[i%len(keys)], value)
+	}
+}
@@ -0,0 +1,171 @@
+// benchmark生成Zipf、均匀与顺序扫描分布的key序列，并用它们驱动任意实现了cache.Cache的淘汰策略，
+// 统计吞吐量、内存分配与命中率，用于比较不同淘汰策略在相同负载下的表现
+package benchmark
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"cache"
+	"cache/lru"
+)
+
+// 生成key空间，第i个key为"key-i"
+func keySpace(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+
+	return keys
+}
+
+// 生成长度为ops、服从Zipf分布的key序列，key空间的大小为n，s大于1，越大越集中于少数key
+func Zipf(n, ops int, s float64, seed uint64) []string {
+	keys := keySpace(n)
+	zipf := rand.NewZipf(rand.New(rand.NewPCG(seed, seed)), s, 1, uint64(n-1))
+
+	stream := make([]string, ops)
+	for i := range stream {
+		stream[i] = keys[zipf.Uint64()]
+	}
+
+	return stream
+}
+
+// 生成长度为ops、在大小为n的key空间中均匀分布的key序列
+func Uniform(n, ops int, seed uint64) []string {
+	keys := keySpace(n)
+	r := rand.New(rand.NewPCG(seed, seed))
+
+	stream := make([]string, ops)
+	for i := range stream {
+		stream[i] = keys[r.IntN(n)]
+	}
+
+	return stream
+}
+
+// 生成长度为ops、按顺序反复扫描大小为n的key空间的key序列
+func Scan(n, ops int) []string {
+	keys := keySpace(n)
+
+	stream := make([]string, ops)
+	for i := range stream {
+		stream[i] = keys[i%n]
+	}
+
+	return stream
+}
+
+// 每隔every个请求将一次长度为len(scan)的扫描插入stream，用于测试淘汰策略能否抵抗扫描对热点数据的冲刷
+func WithScans(stream, scan []string, every int) []string {
+	mixed := make([]string, 0, len(stream)+len(stream)/max(every, 1)*len(scan))
+	for i, key := range stream {
+		if i > 0 && i%every == 0 {
+			mixed = append(mixed, scan...)
+		}
+		mixed = append(mixed, key)
+	}
+
+	return mixed
+}
+
+// 长度为n的value，驱动淘汰策略时作为每个key的数据
+type Value int
+
+func (v Value) Len() int {
+	return int(v)
+}
+
+// 一次运行的结果
+type Result struct {
+	// 请求数量
+	Ops int
+
+	// 命中的请求数量
+	Hits int
+
+	// 运行的总时间
+	Duration time.Duration
+
+	// 运行期间堆内存分配的次数与字节数
+	Allocs uint64
+	Bytes  uint64
+}
+
+// 命中率
+func (r Result) HitRatio() float64 {
+	if r.Ops == 0 {
+		return 0
+	}
+
+	return float64(r.Hits) / float64(r.Ops)
+}
+
+// 每秒的请求数量
+func (r Result) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+
+	return float64(r.Ops) / r.Duration.Seconds()
+}
+
+// 每个请求的平均分配次数
+func (r Result) AllocsPerOp() float64 {
+	if r.Ops == 0 {
+		return 0
+	}
+
+	return float64(r.Allocs) / float64(r.Ops)
+}
+
+// 按stream依次读取c，未命中时写入value，模拟读穿透的缓存
+func Run(c cache.Cache, stream []string, value lru.Value) Result {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	hits := drive(c, stream, len(stream), value)
+
+	duration := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	return Result{
+		Ops:      len(stream),
+		Hits:     hits,
+		Duration: duration,
+		Allocs:   after.Mallocs - before.Mallocs,
+		Bytes:    after.TotalAlloc - before.TotalAlloc,
+	}
+}
+
+// 在基准测试中按stream循环读取c，报告内存分配与命中率
+func Bench(b *testing.B, c cache.Cache, stream []string, value lru.Value) {
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	hits := drive(c, stream, b.N, value)
+
+	b.ReportMetric(float64(hits)/float64(b.N), "hits/op")
+}
+
+// 循环读取stream中的前ops个key，返回命中的次数
+func drive(c cache.Cache, stream []string, ops int, value lru.Value) int {
+	hits := 0
+	for i := 0; i < ops; i++ {
+		key := stream[i%len(stream)]
+		if _, ok := c.Get(key); ok {
+			hits++
+		} else {
+			c.Add(key, value)
+		}
+	}
+
+	return hits
+}
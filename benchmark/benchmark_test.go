@@ -0,0 +1,113 @@
+package benchmark
+
+import (
+	"slices"
+	"testing"
+
+	"cache"
+	"cache/arc"
+	"cache/clock"
+	"cache/fifo"
+	"cache/lfu"
+	"cache/lru"
+	"cache/sampled"
+	"cache/tinylfu"
+	"cache/twoq"
+)
+
+// 参与比较的淘汰策略，容量以字节为单位
+var policies = map[string]func(capacity int64) cache.Cache{
+	"lru":     func(capacity int64) cache.Cache { return lru.New(capacity, nil) },
+	"lfu":     func(capacity int64) cache.Cache { return lfu.New(capacity, nil) },
+	"arc":     func(capacity int64) cache.Cache { return arc.New(capacity, nil) },
+	"fifo":    func(capacity int64) cache.Cache { return fifo.New(capacity, nil) },
+	"twoq":    func(capacity int64) cache.Cache { return twoq.New(capacity, nil) },
+	"tinylfu": func(capacity int64) cache.Cache { return tinylfu.New(capacity, nil) },
+	"sampled": func(capacity int64) cache.Cache { return sampled.New(capacity, nil) },
+	"clock":   func(capacity int64) cache.Cache { return clock.New(capacity, nil) },
+}
+
+func TestZipf(t *testing.T) {
+	stream := Zipf(1000, 10000, 1.2, 1)
+	if len(stream) != 10000 {
+		t.Fatalf("expected 10000 keys but got %d", len(stream))
+	}
+
+	// 最热的key占比远高于均匀分布
+	counts := make(map[string]int)
+	for _, key := range stream {
+		counts[key]++
+	}
+	if counts["key-0"] < 1000 || counts["key-0"] < counts["key-10"] {
+		t.Fatalf("expected key-0 to dominate but got %d", counts["key-0"])
+	}
+
+	// 相同的种子生成相同的序列
+	if !slices.Equal(stream, Zipf(1000, 10000, 1.2, 1)) {
+		t.Fatalf("expected the same stream for the same seed")
+	}
+}
+
+func TestUniformAndScan(t *testing.T) {
+	counts := make(map[string]int)
+	for _, key := range Uniform(10, 10000, 1) {
+		counts[key]++
+	}
+	for key, n := range counts {
+		if n < 800 || n > 1200 {
+			t.Fatalf("expected about 1000 of %s but got %d", key, n)
+		}
+	}
+
+	if stream := Scan(3, 7); !slices.Equal(stream, []string{"key-0", "key-1", "key-2", "key-0", "key-1", "key-2", "key-0"}) {
+		t.Fatalf("unexpected scan %v", stream)
+	}
+
+	if mixed := WithScans([]string{"a", "b", "c"}, []string{"x"}, 2); !slices.Equal(mixed, []string{"a", "b", "x", "c"}) {
+		t.Fatalf("unexpected mixed stream %v", mixed)
+	}
+}
+
+func TestRun(t *testing.T) {
+	// 容量足以容纳所有key时只有首次访问未命中
+	c := lru.New(0, nil)
+	result := Run(c, Scan(10, 100), Value(1))
+	if result.Ops != 100 || result.Hits != 90 || result.HitRatio() != 0.9 || result.Throughput() <= 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	// 顺序扫描超过容量的key空间时LRU不会命中
+	c = lru.New(5*int64(len("key-0")+1), nil)
+	if result := Run(c, Scan(10, 100), Value(1)); result.Hits != 0 {
+		t.Fatalf("expected no hits for LRU under scan but got %d", result.Hits)
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	stream := Zipf(10000, 100000, 1.1, 1)
+	for name, newCache := range policies {
+		result := Run(newCache(1000*int64(len("key-0000")+64)), stream, Value(64))
+		t.Logf("%-8s hit ratio %.3f, %.0f ops/s, %.2f allocs/op", name, result.HitRatio(), result.Throughput(), result.AllocsPerOp())
+		if result.HitRatio() <= 0 {
+			t.Fatalf("%s: expected hits under a Zipf workload", name)
+		}
+	}
+}
+
+func BenchmarkZipf(b *testing.B) {
+	stream := Zipf(10000, 1<<16, 1.1, 1)
+	for name, newCache := range policies {
+		b.Run(name, func(b *testing.B) {
+			Bench(b, newCache(1000*int64(len("key-0000")+64)), stream, Value(64))
+		})
+	}
+}
+
+func BenchmarkScan(b *testing.B) {
+	stream := WithScans(Zipf(10000, 1<<16, 1.1, 1), Scan(5000, 5000), 10000)
+	for name, newCache := range policies {
+		b.Run(name, func(b *testing.B) {
+			Bench(b, newCache(1000*int64(len("key-0000")+64)), stream, Value(64))
+		})
+	}
+}
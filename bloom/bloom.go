@@ -0,0 +1,83 @@
+package bloom
+
+import (
+	"hash/maphash"
+	"math"
+	"math/bits"
+	"sync/atomic"
+)
+
+// 布隆过滤器，判断key是否可能出现过：返回false时key一定没有出现过，返回true时有一定的误判率。
+// 可安全地被多个goroutine并发使用
+type Filter struct {
+	// 位数组
+	words []atomic.Uint64
+
+	// 位数组长度减一，用于代替取模运算
+	mask uint64
+
+	// 每个key对应的位数
+	k int
+
+	// 哈希种子
+	seed maphash.Seed
+}
+
+// 实例化布隆过滤器，n为预期的key数量，falsePositiveRate为期望的误判率，位数组长度向上取整为2的幂
+func New(n int, falsePositiveRate float64) *Filter {
+	n = max(n, 1)
+	falsePositiveRate = min(max(falsePositiveRate, 1e-9), 0.5)
+
+	m := math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	size := uint64(1) << bits.Len64(uint64(max(m, 64))-1)
+	k := max(int(math.Round(float64(size)/float64(n)*math.Ln2)), 1)
+
+	return &Filter{
+		words: make([]atomic.Uint64, size/64),
+		mask:  size - 1,
+		k:     min(k, 16),
+		seed:  maphash.MakeSeed(),
+	}
+}
+
+// 记录key，返回key在记录之前是否可能已经出现过
+func (f *Filter) Add(key string) bool {
+	h1, h2 := f.hash(key)
+	present := true
+	for i := 0; i < f.k; i++ {
+		bit := (h1 + uint64(i)*h2) & f.mask
+		word := &f.words[bit/64]
+		if old := word.Or(1 << (bit % 64)); old&(1<<(bit%64)) == 0 {
+			present = false
+		}
+	}
+
+	return present
+}
+
+// 判断key是否可能出现过
+func (f *Filter) Contains(key string) bool {
+	h1, h2 := f.hash(key)
+	for i := 0; i < f.k; i++ {
+		bit := (h1 + uint64(i)*h2) & f.mask
+		if f.words[bit/64].Load()&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// 清空所有记录
+func (f *Filter) Reset() {
+	for i := range f.words {
+		f.words[i].Store(0)
+	}
+}
+
+// 计算key的两个哈希值，用于双重哈希得到每一位的下标
+func (f *Filter) hash(key string) (uint64, uint64) {
+	h := maphash.String(f.seed, key)
+
+	return h, h>>32 | 1
+}
@@ -0,0 +1,39 @@
+package bloom
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	f := New(1000, 0.01)
+	if f.Contains("0") || f.Add("0") {
+		t.Fatalf("0 should not be present before it is added")
+	}
+	for i := 1; i < 1000; i++ {
+		f.Add(strconv.Itoa(i))
+	}
+
+	// 记录过的key一定被判断为出现过
+	for i := 0; i < 1000; i++ {
+		if !f.Contains(strconv.Itoa(i)) || !f.Add(strconv.Itoa(i)) {
+			t.Fatalf("%d should be present", i)
+		}
+	}
+
+	// 误判率接近期望值
+	falsePositives := 0
+	for i := 1000; i < 11000; i++ {
+		if f.Contains(strconv.Itoa(i)) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 300 {
+		t.Fatalf("expected about 1%% false positives but got %d of 10000", falsePositives)
+	}
+
+	f.Reset()
+	if f.Contains("1") {
+		t.Fatalf("1 should not be present after reset")
+	}
+}
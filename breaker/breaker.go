@@ -0,0 +1,226 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"cache/timesource"
+)
+
+// 熔断器处于打开状态，请求没有发出
+var ErrOpen = errors.New("breaker: circuit open")
+
+// 熔断器的状态
+type State int
+
+const (
+	// 正常放行请求并统计结果
+	Closed State = iota
+
+	// 拒绝所有请求，冷却时间结束后进入半开状态
+	Open
+
+	// 同一时刻只放行一个探测请求，成功时关闭，失败时重新打开
+	HalfOpen
+)
+
+// 返回状态的名称
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// 熔断器的配置，字段为零值时使用默认值
+type Config struct {
+	// 统计最近多少次请求的结果，默认为20
+	Window int
+
+	// 窗口内至少有多少次请求时才会打开，避免请求较少时偶然的失败导致熔断，默认为10
+	MinRequests int
+
+	// 窗口内失败请求的比例不小于该值时打开，默认为0.5
+	FailureRatio float64
+
+	// 打开后经过多长时间进入半开状态，默认为5秒
+	Cooldown time.Duration
+}
+
+// 实例化Breaker时的可选配置
+type Option func(b *Breaker)
+
+// 使用clock代替系统时间计算冷却时间，测试中可以传入timesource.Fake推进时间
+func WithClock(clock timesource.Clock) Option {
+	return func(b *Breaker) {
+		b.clock = clock
+	}
+}
+
+// 按最近一段请求的失败比例熔断的熔断器，可安全地被多个goroutine并发使用
+type Breaker struct {
+	config Config
+
+	// 获取当前时间的时钟
+	clock timesource.Clock
+
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 当前状态
+	state State
+
+	// 最近的请求是否失败，作为环形缓冲区使用
+	results []bool
+
+	// 下一个结果写入results的下标
+	next int
+
+	// 窗口内的请求次数与失败次数
+	requests, failures int
+
+	// 进入打开状态的时间
+	openedAt time.Time
+
+	// 半开状态下是否有进行中的探测请求
+	probing bool
+}
+
+// 实例化Breaker
+func New(config Config, opts ...Option) *Breaker {
+	if config.Window <= 0 {
+		config.Window = 20
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = 10
+	}
+	if config.FailureRatio <= 0 {
+		config.FailureRatio = 0.5
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 5 * time.Second
+	}
+
+	b := &Breaker{
+		config:  config,
+		clock:   timesource.System,
+		results: make([]bool, config.Window),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// 判断是否可以发出请求，返回true时调用方需在请求结束后调用Done或Ignore
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case Closed:
+		return true
+	case HalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return false
+	}
+}
+
+// 记录请求的结果，err为nil时表示成功。调用方主动取消的请求不计入统计
+func (b *Breaker) Done(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == HalfOpen {
+		b.probing = false
+	}
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	switch b.state {
+	case HalfOpen:
+		if err != nil {
+			b.open()
+		} else {
+			b.reset()
+		}
+	case Closed:
+		b.record(err != nil)
+		if b.requests >= b.config.MinRequests && float64(b.failures) >= b.config.FailureRatio*float64(b.requests) {
+			b.open()
+		}
+	}
+}
+
+// 结束一次请求但不记录结果，用于失败原因与对端健康状况无关的请求，例如调用方的ctx已经结束
+func (b *Breaker) Ignore() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == HalfOpen {
+		b.probing = false
+	}
+}
+
+// 获取当前状态
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.currentState()
+}
+
+// 获取当前状态，冷却时间结束时从打开状态进入半开状态，调用方需持有锁
+func (b *Breaker) currentState() State {
+	if b.state == Open && b.clock.Now().Sub(b.openedAt) >= b.config.Cooldown {
+		b.state = HalfOpen
+		b.probing = false
+	}
+
+	return b.state
+}
+
+// 将一次请求的结果写入窗口，覆盖最早的结果，调用方需持有锁
+func (b *Breaker) record(failed bool) {
+	if b.requests == len(b.results) {
+		if b.results[b.next] {
+			b.failures--
+		}
+	} else {
+		b.requests++
+	}
+
+	b.results[b.next] = failed
+	if failed {
+		b.failures++
+	}
+	b.next = (b.next + 1) % len(b.results)
+}
+
+// 进入打开状态，调用方需持有锁
+func (b *Breaker) open() {
+	b.state = Open
+	b.openedAt = b.clock.Now()
+}
+
+// 进入关闭状态并清空窗口，调用方需持有锁
+func (b *Breaker) reset() {
+	b.state = Closed
+	clear(b.results)
+	b.next, b.requests, b.failures = 0, 0, 0
+}
@@ -0,0 +1,65 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cache/timesource"
+)
+
+func TestBreaker(t *testing.T) {
+	clock := timesource.NewFake(time.Now())
+	b := New(Config{Window: 4, MinRequests: 4, FailureRatio: 0.5, Cooldown: time.Second}, WithClock(clock))
+	failure := errors.New("unavailable")
+
+	// 请求次数不足时不会打开
+	b.Done(failure)
+	b.Done(failure)
+	b.Done(nil)
+	if b.State() != Closed {
+		t.Fatalf("expected closed but got %v", b.State())
+	}
+
+	// 调用方取消的请求与被忽略的请求不计入统计
+	b.Done(context.Canceled)
+	b.Ignore()
+	if b.State() != Closed {
+		t.Fatalf("expected closed but got %v", b.State())
+	}
+
+	// 失败比例达到阈值时打开
+	b.Done(nil)
+	if b.State() != Open || b.Allow() {
+		t.Fatalf("expected open but got %v", b.State())
+	}
+
+	// 冷却时间结束后只放行一个探测请求，失败时重新打开
+	clock.Advance(time.Second)
+	if !b.Allow() || b.Allow() || b.State() != HalfOpen {
+		t.Fatalf("expected a single probe in half-open state")
+	}
+	b.Done(failure)
+	if b.State() != Open {
+		t.Fatalf("expected open after failed probe but got %v", b.State())
+	}
+
+	// 被忽略的探测请求不改变状态，之后可以再次探测
+	clock.Advance(time.Second)
+	b.Allow()
+	b.Ignore()
+	if b.State() != HalfOpen || !b.Allow() {
+		t.Fatalf("expected another probe after ignored one but got %v", b.State())
+	}
+	b.Done(failure)
+
+	// 探测成功时关闭并清空窗口
+	clock.Advance(time.Second)
+	b.Allow()
+	b.Done(nil)
+	b.Done(failure)
+	if b.State() != Closed || !b.Allow() {
+		t.Fatalf("expected closed after successful probe but got %v", b.State())
+	}
+}
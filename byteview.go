@@ -0,0 +1,34 @@
+package cache
+
+// 只读的字节视图，缓存中的数据以ByteView的形式存储与返回，调用方无法修改缓存中的数据
+type ByteView struct {
+	b []byte
+}
+
+// 复制b创建ByteView，之后修改b不会影响ByteView
+func NewByteView(b []byte) ByteView {
+	return ByteView{b: cloneBytes(b)}
+}
+
+// 获取数据的长度
+func (v ByteView) Len() int {
+	return len(v.b)
+}
+
+// 以切片的形式返回数据的副本
+func (v ByteView) ByteSlice() []byte {
+	return cloneBytes(v.b)
+}
+
+// 以字符串的形式返回数据
+func (v ByteView) String() string {
+	return string(v.b)
+}
+
+// 复制字节切片，避免调用方修改缓存中的数据
+func cloneBytes(b []byte) []byte {
+	c := make([]byte, len(b))
+	copy(c, b)
+
+	return c
+}
@@ -0,0 +1,17 @@
+package cache
+
+import "testing"
+
+func TestByteView(t *testing.T) {
+	b := []byte("630")
+	view := NewByteView(b)
+	b[0] = 'x'
+	if view.String() != "630" || view.Len() != 3 {
+		t.Fatalf("ByteView should not share memory with b, got %s", view)
+	}
+
+	view.ByteSlice()[0] = 'x'
+	if view.String() != "630" {
+		t.Fatalf("ByteSlice should return a copy, got %s", view)
+	}
+}
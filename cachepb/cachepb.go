@@ -0,0 +1,198 @@
+// cachepb包中的消息与cache.proto中的定义保持一致，
+// 按protobuf的编码格式手工实现编解码，无需依赖protoc生成的代码
+package cachepb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// protobuf的wire type
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// 数据不是合法的protobuf编码
+var ErrMalformed = errors.New("cachepb: malformed message")
+
+// 获取group中key对应数据的请求
+type Request struct {
+	Group string
+	Key   string
+}
+
+// 按protobuf格式编码
+func (m *Request) Marshal() ([]byte, error) {
+	var b []byte
+	b = appendString(b, 1, m.Group)
+	b = appendString(b, 2, m.Key)
+
+	return b, nil
+}
+
+// 按protobuf格式解码，忽略未知字段
+func (m *Request) Unmarshal(b []byte) error {
+	*m = Request{}
+
+	return parse(b, func(field int, wireType int, value []byte, _ uint64) error {
+		switch {
+		case field == 1 && wireType == wireBytes:
+			m.Group = string(value)
+		case field == 2 && wireType == wireBytes:
+			m.Key = string(value)
+		}
+		return nil
+	})
+}
+
+// 响应的标记
+const (
+	FlagNone uint32 = 0
+
+	// 请求方不应将数据放入热点缓存
+	FlagNoCache uint32 = 1
+)
+
+// 获取数据的响应
+type Response struct {
+	Value []byte
+
+	// 数据剩余的存活时间（单位为毫秒），0表示永不过期
+	TtlMs int64
+
+	// 各个标记按位或的结果
+	Flags uint32
+}
+
+// 数据剩余的存活时间，0表示永不过期
+func (m *Response) TTL() time.Duration {
+	return time.Duration(m.TtlMs) * time.Millisecond
+}
+
+// 判断响应是否带有标记flag
+func (m *Response) HasFlag(flag uint32) bool {
+	return m.Flags&flag != 0
+}
+
+// 按protobuf格式编码
+func (m *Response) Marshal() ([]byte, error) {
+	var b []byte
+	b = appendBytes(b, 1, m.Value)
+	b = appendVarint(b, 2, uint64(m.TtlMs))
+	b = appendVarint(b, 3, uint64(m.Flags))
+
+	return b, nil
+}
+
+// 按protobuf格式解码，忽略未知字段
+func (m *Response) Unmarshal(b []byte) error {
+	*m = Response{}
+
+	return parse(b, func(field int, wireType int, value []byte, number uint64) error {
+		switch {
+		case field == 1 && wireType == wireBytes:
+			m.Value = append([]byte(nil), value...)
+		case field == 2 && wireType == wireVarint:
+			m.TtlMs = int64(number)
+		case field == 3 && wireType == wireVarint:
+			m.Flags = uint32(number)
+		}
+		return nil
+	})
+}
+
+// 追加字段的tag
+func appendTag(b []byte, field int, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(field)<<3|uint64(wireType))
+}
+
+// 追加varint编码的整数字段，与proto3一致，零值不编码
+func appendVarint(b []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+
+	b = appendTag(b, field, wireVarint)
+
+	return binary.AppendUvarint(b, v)
+}
+
+// 追加string类型的字段，与proto3一致，零值不编码
+func appendString(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+
+	b = appendTag(b, field, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+
+	return append(b, s...)
+}
+
+// 追加bytes类型的字段，与proto3一致，零值不编码
+func appendBytes(b []byte, field int, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+
+	b = appendTag(b, field, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+
+	return append(b, v...)
+}
+
+// 依次解析每个字段，对于变长字段value为其内容，对于其他字段number为其数值
+func parse(b []byte, f func(field int, wireType int, value []byte, number uint64) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return ErrMalformed
+		}
+		b = b[n:]
+
+		field, wireType := int(tag>>3), int(tag&7)
+		if field == 0 {
+			return ErrMalformed
+		}
+
+		var value []byte
+		var number uint64
+		switch wireType {
+		case wireVarint:
+			number, n = binary.Uvarint(b)
+			if n <= 0 {
+				return ErrMalformed
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return ErrMalformed
+			}
+			number, b = binary.LittleEndian.Uint64(b), b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return ErrMalformed
+			}
+			number, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		case wireBytes:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return ErrMalformed
+			}
+			value, b = b[n:n+int(length)], b[n+int(length):]
+		default:
+			return fmt.Errorf("%w: unsupported wire type %d", ErrMalformed, wireType)
+		}
+
+		if err := f(field, wireType, value, number); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package cachepb
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRequest(t *testing.T) {
+	in := &Request{Group: "scores", Key: "Tom"}
+	b, err := in.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 与protoc生成的代码编码结果一致
+	expect := []byte{0x0a, 0x06, 's', 'c', 'o', 'r', 'e', 's', 0x12, 0x03, 'T', 'o', 'm'}
+	if !bytes.Equal(expect, b) {
+		t.Fatalf("expected %x but got %x", expect, b)
+	}
+
+	var out Request
+	if err := out.Unmarshal(b); err != nil || out != *in {
+		t.Fatalf("expected %+v but got %+v, error = %v", in, out, err)
+	}
+}
+
+func TestResponse(t *testing.T) {
+	in := &Response{Value: []byte("630"), TtlMs: 1500, Flags: FlagNoCache}
+	b, _ := in.Marshal()
+
+	expect := []byte{0x0a, 0x03, '6', '3', '0', 0x10, 0xdc, 0x0b, 0x18, 0x01}
+	if !bytes.Equal(expect, b) {
+		t.Fatalf("expected %x but got %x", expect, b)
+	}
+
+	// 未知字段会被忽略
+	b = append(b, 0x78, 0x01)
+
+	var out Response
+	if err := out.Unmarshal(b); err != nil || string(out.Value) != "630" {
+		t.Fatalf("expected 630 but got %s, error = %v", out.Value, err)
+	}
+	if out.TTL() != 1500*time.Millisecond || !out.HasFlag(FlagNoCache) {
+		t.Fatalf("expected ttl 1.5s with FlagNoCache but got %+v", out)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	var out Request
+	for _, b := range [][]byte{{0x0a}, {0x0a, 0x05, 'a'}, {0x00}, {0x0b}} {
+		if err := out.Unmarshal(b); !errors.Is(err, ErrMalformed) {
+			t.Fatalf("expected ErrMalformed for %x but got %v", b, err)
+		}
+	}
+}
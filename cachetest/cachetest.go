@@ -0,0 +1,138 @@
+// cachetest提供cache.Cache接口的测试替身，用于对依赖缓存的代码做单元测试
+package cachetest
+
+import (
+	"sync"
+
+	"cache"
+	"cache/lru"
+)
+
+// 一次对Fake的调用
+type Call struct {
+	// 方法名，例如"Get"
+	Method string
+
+	Key string
+}
+
+// 基于哈希表、不会淘汰条目的cache.Cache实现，记录所有调用并可以指定key未命中。
+// 可安全地被多个goroutine并发使用
+type Fake struct {
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	data map[string]lru.Value
+
+	// 按顺序记录的调用
+	calls []Call
+
+	// 存储key与其剩余的强制未命中次数映射关系的哈希表，小于0时表示一直未命中
+	misses map[string]int
+}
+
+var _ cache.Cache = (*Fake)(nil)
+
+// 实例化空的Fake
+func NewFake() *Fake {
+	return &Fake{
+		data:   make(map[string]lru.Value),
+		misses: make(map[string]int),
+	}
+}
+
+// 实现cache.Cache接口，key被指定未命中时即使存在也返回false
+func (f *Fake) Get(key string) (lru.Value, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{Method: "Get", Key: key})
+
+	if n, ok := f.misses[key]; ok {
+		if n > 0 {
+			if n == 1 {
+				delete(f.misses, key)
+			} else {
+				f.misses[key] = n - 1
+			}
+		}
+		return nil, false
+	}
+
+	value, ok := f.data[key]
+	return value, ok
+}
+
+// 实现cache.Cache接口
+func (f *Fake) Add(key string, value lru.Value) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{Method: "Add", Key: key})
+	f.data[key] = value
+}
+
+// 实现cache.Cache接口
+func (f *Fake) Remove(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{Method: "Remove", Key: key})
+	_, ok := f.data[key]
+	delete(f.data, key)
+
+	return ok
+}
+
+// 实现cache.Cache接口
+func (f *Fake) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{Method: "Len"})
+
+	return len(f.data)
+}
+
+// 使接下来n次Get(key)未命中，n小于0时一直未命中，直到再次调用Miss将n设为0
+func (f *Fake) Miss(key string, n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if n == 0 {
+		delete(f.misses, key)
+		return
+	}
+	f.misses[key] = n
+}
+
+// 按顺序获取所有调用
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Call(nil), f.calls...)
+}
+
+// 获取method被调用的次数
+func (f *Fake) CallCount(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := 0
+	for _, call := range f.calls {
+		if call.Method == method {
+			n++
+		}
+	}
+
+	return n
+}
+
+// 清空调用记录，不影响缓存中的数据
+func (f *Fake) ResetCalls() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = nil
+}
@@ -0,0 +1,56 @@
+package cachetest
+
+import (
+	"reflect"
+	"testing"
+)
+
+type String string
+
+func (d String) Len() int {
+	return len(d)
+}
+
+func TestFake(t *testing.T) {
+	f := NewFake()
+	f.Add("Tom", String("630"))
+	if v, ok := f.Get("Tom"); !ok || v != String("630") {
+		t.Fatalf("failed to get Tom")
+	}
+
+	// 指定次数的未命中之后恢复命中
+	f.Miss("Tom", 2)
+	for i := 0; i < 2; i++ {
+		if _, ok := f.Get("Tom"); ok {
+			t.Fatalf("expected scripted miss %d", i)
+		}
+	}
+	if _, ok := f.Get("Tom"); !ok {
+		t.Fatalf("expected hit after scripted misses")
+	}
+
+	f.Miss("Jack", -1)
+	f.Add("Jack", String("589"))
+	for i := 0; i < 3; i++ {
+		if _, ok := f.Get("Jack"); ok {
+			t.Fatalf("expected Jack to always miss")
+		}
+	}
+	f.Miss("Jack", 0)
+	if _, ok := f.Get("Jack"); !ok {
+		t.Fatalf("expected hit after clearing misses")
+	}
+
+	if f.Len() != 2 || !f.Remove("Tom") || f.Remove("Tom") {
+		t.Fatalf("unexpected Len or Remove result")
+	}
+	if n := f.CallCount("Get"); n != 8 {
+		t.Fatalf("expected 8 Get calls but got %d", n)
+	}
+
+	f.ResetCalls()
+	f.Get("Sam")
+	if calls := f.Calls(); !reflect.DeepEqual(calls, []Call{{Method: "Get", Key: "Sam"}}) {
+		t.Fatalf("unexpected calls %v", calls)
+	}
+}
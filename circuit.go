@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"reflect"
+	"sync"
+
+	"cache/breaker"
+)
+
+// 为每个远程节点开启熔断：节点最近请求的失败比例超过阈值时不再向其发送请求，直接从数据源加载，
+// 冷却时间结束后放行一个探测请求，成功时恢复。只有超时与传输失败计为失败，开启WithPeerFetch时重试都失败才计为一次失败
+func WithCircuitBreaker(config breaker.Config) Option {
+	return func(g *Group) {
+		g.circuits = &circuits{config: config, breakers: make(map[any]*breaker.Breaker)}
+	}
+}
+
+// 每个远程节点的熔断器
+type circuits struct {
+	config breaker.Config
+
+	// 保护breakers的互斥锁
+	mu sync.Mutex
+
+	// 存储远程节点与熔断器映射关系的哈希表，延迟创建。实现PeerAddresser的节点以地址为键
+	breakers map[any]*breaker.Breaker
+}
+
+// 获取peer的熔断器，未开启熔断或peer既没有地址也无法作为哈希表的键时返回nil
+func (g *Group) breakerOf(peer PeerGetter) *breaker.Breaker {
+	if g.circuits == nil {
+		return nil
+	}
+
+	var key any = peer
+	if p, ok := peer.(PeerAddresser); ok {
+		key = p.Addr()
+	} else if !reflect.ValueOf(peer).Comparable() {
+		return nil
+	}
+
+	c := g.circuits
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.breakers[key]
+	if !ok {
+		b = breaker.New(c.config, breaker.WithClock(groupClock{g}))
+		c.breakers[key] = b
+	}
+
+	return b
+}
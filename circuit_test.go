@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"cache/breaker"
+	"cache/cachepb"
+	"cache/timesource"
+)
+
+func TestGroup_WithCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	clock := timesource.NewFake(time.Now())
+	peer := &fakePeer{err: errors.New("unavailable")}
+	g := NewGroup("scores-circuit", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("local:" + key), nil
+	}), WithCircuitBreaker(breaker.Config{Window: 2, MinRequests: 2, Cooldown: time.Second}), WithClock(clock))
+	g.RegisterPeers(&fakePicker{peer: peer})
+
+	// 失败比例达到阈值后不再请求远程节点，直接从数据源加载
+	for _, key := range []string{"k1", "k2", "k3"} {
+		if view, err := g.Get(ctx, key); err != nil || view.String() != "local:"+key {
+			t.Fatalf("expected local value but got %q, %v", view.String(), err)
+		}
+	}
+	if peer.calls != 2 {
+		t.Fatalf("expected 2 calls but got %d", peer.calls)
+	}
+
+	// 冷却时间结束后探测成功即恢复
+	clock.Advance(time.Second)
+	peer.err = nil
+	for _, key := range []string{"k4", "k5"} {
+		if view, err := g.Get(ctx, key); err != nil || view.String() != "peer:scores-circuit/"+key {
+			t.Fatalf("expected value from peer but got %q, %v", view.String(), err)
+		}
+	}
+	if state := g.breakerOf(peer).State(); state != breaker.Closed {
+		t.Fatalf("expected closed but got %v", state)
+	}
+}
+
+func TestGroup_WithCircuitBreakerAnswered(t *testing.T) {
+	clock := timesource.NewFake(time.Now())
+	peer := &fakePeer{err: fmt.Errorf("%w: k", ErrNotFound)}
+	g := NewGroup("scores-circuit-answered", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("local:" + key), nil
+	}), WithCircuitBreaker(breaker.Config{Window: 2, MinRequests: 2, Cooldown: time.Second}), WithClock(clock))
+	g.RegisterPeers(&fakePicker{peer: peer})
+
+	// 远程节点确认key不存在或其数据源出错时说明节点是健康的，熔断器保持关闭
+	for _, key := range []string{"k1", "k2"} {
+		if _, err := g.Get(context.Background(), key); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound but got %v", err)
+		}
+	}
+	peer.err = fmt.Errorf("%w: k", ErrPeerGetter)
+	g.Get(context.Background(), "k3")
+	g.Get(context.Background(), "k4")
+
+	// 调用方的ctx结束导致的失败不计入统计
+	peer.err = context.Canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	g.Get(ctx, "k5")
+	g.Get(ctx, "k6")
+
+	if state := g.breakerOf(peer).State(); state != breaker.Closed || peer.calls != 6 {
+		t.Fatalf("expected closed after 6 calls but got %v, %d", state, peer.calls)
+	}
+}
+
+// 带有地址的远程节点，节点列表更新时会重新创建
+type addrPeer struct {
+	*fakePeer
+	addr string
+}
+
+func (p addrPeer) Addr() string {
+	return p.addr
+}
+
+// 无法作为哈希表键的远程节点
+type peerFunc func(ctx context.Context, in *cachepb.Request, out *cachepb.Response) error
+
+func (f peerFunc) Get(ctx context.Context, in *cachepb.Request, out *cachepb.Response) error {
+	return f(ctx, in, out)
+}
+
+func TestGroup_WithCircuitBreakerAddr(t *testing.T) {
+	ctx := context.Background()
+	picker := &fakePicker{peer: addrPeer{fakePeer: &fakePeer{err: errors.New("unavailable")}, addr: "10.0.0.2"}}
+	g := NewGroup("scores-circuit-addr", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("local:" + key), nil
+	}), WithCircuitBreaker(breaker.Config{Window: 2, MinRequests: 2, Cooldown: time.Second}))
+	g.RegisterPeers(picker)
+	g.Get(ctx, "k1")
+	g.Get(ctx, "k2")
+
+	// 重新创建的同一地址的节点沿用打开的熔断器
+	recreated := addrPeer{fakePeer: &fakePeer{}, addr: "10.0.0.2"}
+	picker.peer = recreated
+	if view, err := g.Get(ctx, "k3"); err != nil || view.String() != "local:k3" || recreated.calls != 0 {
+		t.Fatalf("expected breaker to stay open but got %q, %v, %d calls", view.String(), err, recreated.calls)
+	}
+
+	// 无法作为键的节点不使用熔断器
+	picker.peer = peerFunc(func(ctx context.Context, in *cachepb.Request, out *cachepb.Response) error {
+		out.Value = []byte("func")
+		return nil
+	})
+	if view, err := g.Get(ctx, "k4"); err != nil || view.String() != "func" {
+		t.Fatalf("expected value from peer but got %q, %v", view.String(), err)
+	}
+}
@@ -0,0 +1,169 @@
+package clock
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"cache/lru"
+)
+
+// 与lru包共用Value接口，便于在不同淘汰策略之间切换
+type Value = lru.Value
+
+// 环形缓冲区中的槽位
+type entry struct {
+	key   string
+	value Value
+
+	// 条目在环形缓冲区中的下标
+	index int
+
+	// 访问位，Get只需原子地设置该位而不必调整任何链表
+	referenced atomic.Bool
+}
+
+// Clock cache（second-chance算法），是LRU的近似实现，可安全地被多个goroutine并发使用
+type Cache struct {
+	// 读写锁，Get只需持有读锁
+	mu sync.RWMutex
+
+	// 缓存的最大容量（单位为字节）
+	capacity int64
+
+	// 已使用的缓存空间（单位为字节）
+	size int64
+
+	// 环形缓冲区，被删除的条目留下的空槽位为nil
+	slots []*entry
+
+	// 空槽位的下标
+	free []int
+
+	// 时钟指针，指向下一个待检查的槽位
+	hand int
+
+	// 存储key与条目映射关系的哈希表
+	cache map[string]*entry
+
+	// 可选的回调函数，在发生缓存条目清除时被执行
+	// 回调在持有锁的情况下执行，因此不能在回调中再调用Cache的方法
+	OnEvicted func(key string, value Value)
+}
+
+// 实例化Clock cache
+func New(capacity int64, onEvicted func(string, Value)) *Cache {
+	return &Cache{
+		capacity:  capacity,
+		cache:     make(map[string]*entry),
+		OnEvicted: onEvicted,
+	}
+}
+
+// 实现查找功能，命中时只设置访问位
+func (c *Cache) Get(key string) (value Value, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if keyValue, ok := c.cache[key]; ok {
+		keyValue.referenced.Store(true)
+		return keyValue.value, true
+	}
+
+	return
+}
+
+// 实现新增与修改功能
+func (c *Cache) Add(key string, value Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if keyValue, ok := c.cache[key]; ok {
+		// 更新缓存大小与键值对，修改也视为一次访问
+		c.size = c.size - int64(keyValue.value.Len()) + int64(value.Len())
+		keyValue.value = value
+		keyValue.referenced.Store(true)
+	} else {
+		// 新条目的访问位为空，只访问一次的条目会在时钟指针第一次经过时被淘汰
+		keyValue := &entry{key: key, value: value}
+		if n := len(c.free); n > 0 {
+			keyValue.index = c.free[n-1]
+			c.free = c.free[:n-1]
+			c.slots[keyValue.index] = keyValue
+		} else {
+			keyValue.index = len(c.slots)
+			c.slots = append(c.slots, keyValue)
+		}
+
+		c.cache[key] = keyValue
+		c.size += int64(len(key)) + int64(value.Len())
+	}
+
+	// 如果缓存大小大于缓存容量，则持续淘汰条目
+	for c.capacity != 0 && c.capacity < c.size {
+		c.evict()
+	}
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if keyValue, ok := c.cache[key]; ok {
+		c.removeEntry(keyValue)
+		return true
+	}
+
+	return false
+}
+
+// 获取缓存的条目数量
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.cache)
+}
+
+// 转动时钟指针，清除经过的条目的访问位，直到找到访问位为空的条目并淘汰，调用方需持有锁
+func (c *Cache) evict() {
+	if len(c.cache) == 0 {
+		return
+	}
+
+	for {
+		if c.hand >= len(c.slots) {
+			c.hand = 0
+		}
+
+		keyValue := c.slots[c.hand]
+		c.hand++
+
+		if keyValue == nil {
+			continue
+		}
+
+		// 访问位被设置的条目获得第二次机会
+		if keyValue.referenced.Swap(false) {
+			continue
+		}
+
+		c.removeEntry(keyValue)
+		return
+	}
+}
+
+// 从环形缓冲区与哈希表中删除条目并调用回调函数，调用方需持有锁
+func (c *Cache) removeEntry(keyValue *entry) {
+	c.slots[keyValue.index] = nil
+	c.free = append(c.free, keyValue.index)
+	delete(c.cache, keyValue.key)
+
+	// 更新缓存大小
+	c.size -= int64(len(keyValue.key)) + int64(keyValue.value.Len())
+
+	// 调用回调函数
+	if c.OnEvicted != nil {
+		c.OnEvicted(keyValue.key, keyValue.value)
+	}
+}
@@ -0,0 +1,92 @@
+package clock
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+func TestCache_Get(t *testing.T) {
+	clock := New(int64(0), nil)
+	clock.Add("peng", String("chang"))
+	if v, ok := clock.Get("peng"); !ok || string(v.(String)) != "chang" {
+		t.Fatalf("cache hit failed")
+	}
+
+	if _, ok := clock.Get("liu"); ok {
+		t.Fatalf("cache miss failed")
+	}
+}
+
+func TestOnEvicted(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	clock := New(int64(10), callback)
+	clock.Add("key1", String("123456"))
+	clock.Add("k2", String("k2"))
+	clock.Add("k3", String("k3"))
+	clock.Add("k4", String("k4"))
+
+	expect := []string{"key1", "k2"}
+
+	if !reflect.DeepEqual(expect, keys) {
+		t.Fatalf("Call OnEvicted failed, expect keys equals to %s but got %s", expect, keys)
+	}
+}
+
+func TestCache_SecondChance(t *testing.T) {
+	clock := New(int64(12), nil)
+	clock.Add("k1", String("vv"))
+	clock.Add("k2", String("vv"))
+	clock.Add("k3", String("vv"))
+
+	// k1被访问过，时钟指针经过时只清除其访问位，k2被淘汰
+	clock.Get("k1")
+	clock.Add("k4", String("vv"))
+
+	if _, ok := clock.Get("k2"); ok {
+		t.Fatalf("k2 should be evicted")
+	}
+	if _, ok := clock.Get("k1"); !ok {
+		t.Fatalf("k1 should get a second chance")
+	}
+
+	// 被删除条目的槽位会被复用
+	slots := len(clock.slots)
+	clock.Remove("k3")
+	clock.Add("k5", String("vv"))
+	if len(clock.slots) != slots || clock.Len() != 3 {
+		t.Fatalf("expected %d slots and 3 entries but got %d slots and %d entries", slots, len(clock.slots), clock.Len())
+	}
+}
+
+func TestCache_Concurrent(t *testing.T) {
+	clock := New(int64(100), nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				key := strconv.Itoa(i*1000 + j)
+				clock.Add(key, String("v"))
+				clock.Get(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if clock.size > 100 {
+		t.Fatalf("expected size <= 100 but got %d", clock.size)
+	}
+}
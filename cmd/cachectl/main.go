@@ -0,0 +1,200 @@
+// cachectl通过管理接口操作正在运行的缓存节点，用于排查线上问题。
+//
+// 用法：
+//
+//	cachectl [-addr http://localhost:8080/admin] <command> [arguments]
+//
+// 命令：
+//
+//	get   <group> <key>   获取key对应的数据，-peek时只查找缓存
+//	set   <group> <key> <value>
+//	                      写入key对应的数据，value为"-"时从标准输入读取
+//	del   <group> <key>   从缓存中移除key
+//	stats [group]         显示命名空间的统计信息，不指定时显示所有命名空间
+//	top                   按请求数排序，定期刷新显示所有命名空间的统计信息
+//	flush <group>         清空命名空间的缓存
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"cache"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "cachectl:", err)
+		os.Exit(1)
+	}
+}
+
+// 管理接口的客户端
+type client struct {
+	// 管理接口的地址
+	base string
+
+	http *http.Client
+}
+
+// 解析参数并执行命令
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("cachectl", flag.ContinueOnError)
+	addr := flags.String("addr", "http://localhost:8080/admin", "管理接口的地址")
+	peek := flags.Bool("peek", false, "get时只查找缓存，不加载数据")
+	interval := flags.Duration("interval", 2*time.Second, "top的刷新间隔")
+	count := flags.Int("n", 0, "top的刷新次数，为0时表示一直刷新")
+	timeout := flags.Duration("timeout", 10*time.Second, "请求的超时时间")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	c := &client{base: strings.TrimSuffix(*addr, "/"), http: &http.Client{Timeout: *timeout}}
+	args = flags.Args()
+	if len(args) == 0 {
+		return fmt.Errorf("missing command, expected one of get, set, del, stats, top, flush")
+	}
+
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "get":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: get <group> <key>")
+		}
+		path := keyPath(args[0], args[1])
+		if *peek {
+			path += "?peek=1"
+		}
+		body, err := c.do(http.MethodGet, path, nil)
+		if err != nil {
+			return err
+		}
+		_, err = stdout.Write(body)
+		return err
+	case "set":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: set <group> <key> <value>")
+		}
+		var value io.Reader = strings.NewReader(args[2])
+		if args[2] == "-" {
+			value = stdin
+		}
+		_, err := c.do(http.MethodPut, keyPath(args[0], args[1]), value)
+		return err
+	case "del":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: del <group> <key>")
+		}
+		_, err := c.do(http.MethodDelete, keyPath(args[0], args[1]), nil)
+		return err
+	case "stats":
+		if len(args) > 1 {
+			return fmt.Errorf("usage: stats [group]")
+		}
+		return c.printStats(stdout, args)
+	case "top":
+		for i := 0; *count == 0 || i < *count; i++ {
+			if i > 0 {
+				time.Sleep(*interval)
+				// 清屏并将光标移动到左上角
+				fmt.Fprint(stdout, "\033[H\033[2J")
+			}
+			if err := c.printStats(stdout, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "flush":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: flush <group>")
+		}
+		_, err := c.do(http.MethodPost, "/groups/"+url.PathEscape(args[0])+"/clear", nil)
+		return err
+	default:
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+// 获取key的路径
+func keyPath(group, key string) string {
+	return "/groups/" + url.PathEscape(group) + "/keys/" + url.PathEscape(key)
+}
+
+// 按请求数从多到少打印命名空间的统计信息，names为空时打印所有命名空间
+func (c *client) printStats(stdout io.Writer, names []string) error {
+	if len(names) == 0 {
+		body, err := c.do(http.MethodGet, "/groups", nil)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(body, &names); err != nil {
+			return err
+		}
+	}
+
+	stats := make(map[string]cache.Stats, len(names))
+	for _, name := range names {
+		body, err := c.do(http.MethodGet, "/groups/"+url.PathEscape(name)+"/stats", nil)
+		if err != nil {
+			return err
+		}
+		var s cache.Stats
+		if err := json.Unmarshal(body, &s); err != nil {
+			return err
+		}
+		stats[name] = s
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		return requests(stats[names[i]]) > requests(stats[names[j]])
+	})
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "GROUP\tREQUESTS\tHIT RATIO\tHOT HIT RATIO\tENTRIES\tBYTES\tEVICTIONS")
+	for _, name := range names {
+		s := stats[name]
+		fmt.Fprintf(w, "%s\t%d\t%.2f%%\t%.2f%%\t%d\t%d\t%d\n", name, requests(s),
+			s.Main.HitRatio()*100, s.Hot.HitRatio()*100,
+			s.Main.Entries+s.Hot.Entries, s.Main.Bytes+s.Hot.Bytes, s.Main.Evictions+s.Hot.Evictions)
+	}
+
+	return w.Flush()
+}
+
+// 命名空间收到的请求数
+func requests(s cache.Stats) int64 {
+	return s.Main.Hits + s.Main.Misses
+}
+
+// 发送请求，状态码不为2xx时返回响应体中的错误信息
+func (c *client) do(method, path string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, c.base+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%s %s: %s: %s", method, path, res.Status, strings.TrimSpace(string(data)))
+	}
+
+	return data, nil
+}
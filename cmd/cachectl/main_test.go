@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cache"
+	"cache/admin"
+)
+
+func TestRun(t *testing.T) {
+	cache.NewGroup("scores-cachectl", 2<<10, cache.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("loaded:" + key), nil
+	}))
+
+	mux := http.NewServeMux()
+	mux.Handle("/admin/", http.StripPrefix("/admin", admin.NewHandler()))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	addr := "-addr=" + server.URL + "/admin"
+	cases := []struct {
+		args   []string
+		stdin  string
+		expect string
+	}{
+		{[]string{addr, "get", "scores-cachectl", "Tom"}, "", "loaded:Tom"},
+		{[]string{addr, "set", "scores-cachectl", "Jack", "589"}, "", ""},
+		{[]string{addr, "set", "scores-cachectl", "Sam", "-"}, "567", ""},
+		{[]string{addr, "-peek", "get", "scores-cachectl", "Jack"}, "", "589"},
+		{[]string{addr, "-peek", "get", "scores-cachectl", "Sam"}, "", "567"},
+		{[]string{addr, "del", "scores-cachectl", "Jack"}, "", ""},
+		{[]string{addr, "flush", "scores-cachectl"}, "", ""},
+	}
+	for _, tc := range cases {
+		var stdout bytes.Buffer
+		if err := run(tc.args, strings.NewReader(tc.stdin), &stdout); err != nil || stdout.String() != tc.expect {
+			t.Fatalf("%v: expected %q but got %q, %v", tc.args, tc.expect, stdout.String(), err)
+		}
+	}
+
+	// 已删除的key只查找缓存时返回错误
+	if err := run([]string{addr, "-peek", "get", "scores-cachectl", "Jack"}, nil, &bytes.Buffer{}); err == nil {
+		t.Fatalf("expected an error for removed key")
+	}
+
+	var stdout bytes.Buffer
+	if err := run([]string{addr, "stats", "scores-cachectl"}, nil, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(stdout.String()), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[1], "scores-cachectl") {
+		t.Fatalf("unexpected stats output %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := run([]string{addr, "-n=1", "top"}, nil, &stdout); err != nil || !strings.Contains(stdout.String(), "scores-cachectl") {
+		t.Fatalf("unexpected top output %q, %v", stdout.String(), err)
+	}
+
+	if err := run([]string{addr, "unknown"}, nil, &bytes.Buffer{}); err == nil {
+		t.Fatalf("expected an error for unknown command")
+	}
+}
@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+
+	"cache/codec"
+)
+
+// 将返回任意Go值的加载函数包装为Getter，加载的结果使用c编码后缓存
+func CodecGetter(c codec.Codec, load func(ctx context.Context, key string) (any, error)) Getter {
+	return GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		v, err := load(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.Encode(v)
+	})
+}
+
+// 获取key对应的数据并使用c解码到v中，v必须是指针
+func (g *Group) GetValue(ctx context.Context, key string, c codec.Codec, v any) error {
+	view, err := g.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return c.Decode(view.b, v)
+}
+
+// 使用c编码v后写入key对应的数据
+func (g *Group) SetValue(ctx context.Context, key string, c codec.Codec, v any) error {
+	data, err := c.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	return g.Set(ctx, key, data)
+}
@@ -0,0 +1,94 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+
+	"cache/lru"
+)
+
+// 编解码器，将任意Go值与字节相互转换，使结构体可以被缓存、在节点之间传输以及保存到快照中
+type Codec interface {
+	// 将v编码为字节
+	Encode(v any) ([]byte, error)
+
+	// 将data解码到v中，v必须是指针
+	Decode(data []byte, v any) error
+}
+
+var (
+	// 使用encoding/gob编解码
+	Gob Codec = gobCodec{}
+
+	// 使用encoding/json编解码
+	JSON Codec = jsonCodec{}
+)
+
+// 使用encoding/gob的编解码器
+type gobCodec struct{}
+
+func (gobCodec) Encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Decode(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// 使用encoding/json的编解码器
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Decode(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// 使用Codec编码的lru.Value，保存快照时写入编码后的字节
+type Value struct {
+	// 被包装的值
+	V any
+
+	// 编码后的字节
+	data []byte
+}
+
+// 使用c编码v并包装为lru.Value，以编码后的长度作为开销
+func NewValue(c Codec, v any) (*Value, error) {
+	data, err := c.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Value{V: v, data: data}, nil
+}
+
+// 获取编码后的长度
+func (v *Value) Len() int {
+	return len(v.data)
+}
+
+// 实现encoding.BinaryMarshaler接口，用于保存快照
+func (v *Value) MarshalBinary() ([]byte, error) {
+	return v.data, nil
+}
+
+// 返回使用c将快照中的字节解码为T的函数，可作为lru.Cache.LoadFromFile的decode参数
+func Decoder[T any](c Codec) func(data []byte) (lru.Value, error) {
+	return func(data []byte) (lru.Value, error) {
+		var v T
+		if err := c.Decode(data, &v); err != nil {
+			return nil, err
+		}
+
+		return &Value{V: v, data: data}, nil
+	}
+}
@@ -0,0 +1,51 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+
+	"cache/lru"
+)
+
+type user struct {
+	Name string
+	Age  int
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	for name, c := range map[string]Codec{"gob": Gob, "json": JSON} {
+		data, err := c.Encode(user{Name: "Tom", Age: 30})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		var u user
+		if err := c.Decode(data, &u); err != nil || u != (user{Name: "Tom", Age: 30}) {
+			t.Fatalf("%s: expected Tom but got %+v, %v", name, u, err)
+		}
+	}
+}
+
+func TestValue_Snapshot(t *testing.T) {
+	v, err := NewValue(JSON, user{Name: "Tom", Age: 30})
+	if err != nil || v.Len() != len(`{"Name":"Tom","Age":30}`) {
+		t.Fatalf("unexpected value length %d, %v", v.Len(), err)
+	}
+
+	cache := lru.New(int64(0), nil)
+	cache.Add("tom", v)
+
+	var buf bytes.Buffer
+	if err := cache.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := lru.New(int64(0), nil)
+	if _, err := restored.Load(&buf, Decoder[user](JSON)); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := restored.Get("tom"); !ok || got.(*Value).V != (user{Name: "Tom", Age: 30}) {
+		t.Fatalf("unexpected restored value %v", got)
+	}
+}
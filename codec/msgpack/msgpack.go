@@ -0,0 +1,21 @@
+package msgpack
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+
+	"cache/codec"
+)
+
+// 使用MessagePack编解码，编码结果通常比JSON更紧凑
+var Codec codec.Codec = msgpackCodec{}
+
+// 使用vmihailenco/msgpack的编解码器
+type msgpackCodec struct{}
+
+func (msgpackCodec) Encode(v any) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+func (msgpackCodec) Decode(data []byte, v any) error {
+	return msgpack.Unmarshal(data, v)
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"cache/codec"
+)
+
+type score struct {
+	Name  string
+	Score int
+}
+
+func TestGroup_GetValue(t *testing.T) {
+	ctx := context.Background()
+	g := NewGroup("scores-codec", 2<<10, CodecGetter(codec.Gob, func(ctx context.Context, key string) (any, error) {
+		return score{Name: key, Score: 630}, nil
+	}))
+
+	var s score
+	if err := g.GetValue(ctx, "Tom", codec.Gob, &s); err != nil || s != (score{Name: "Tom", Score: 630}) {
+		t.Fatalf("expected Tom 630 but got %+v, %v", s, err)
+	}
+
+	if err := g.SetValue(ctx, "Jack", codec.JSON, score{Name: "Jack", Score: 589}); err != nil {
+		t.Fatal(err)
+	}
+	if view, _ := g.Peek("Jack"); view.String() != `{"Name":"Jack","Score":589}` {
+		t.Fatalf("unexpected encoded value %s", view)
+	}
+	if err := g.GetValue(ctx, "Jack", codec.JSON, &s); err != nil || s != (score{Name: "Jack", Score: 589}) {
+		t.Fatalf("expected Jack 589 but got %+v, %v", s, err)
+	}
+}
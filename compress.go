@@ -0,0 +1,16 @@
+package cache
+
+// 压缩器，在数据放入缓存时压缩，取出时解压
+type Compressor interface {
+	Compress(src []byte) ([]byte, error)
+	Decompress(src []byte) ([]byte, error)
+}
+
+// 开启压缩：长度不小于threshold的数据使用c压缩后放入缓存，缓存容量按压缩后的长度计算。
+// 压缩后没有变小或压缩失败的数据按原样存储
+func WithCompression(threshold int, c Compressor) Option {
+	return func(g *Group) {
+		g.compressor = c
+		g.compressThreshold = threshold
+	}
+}
@@ -0,0 +1,43 @@
+package compress
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+)
+
+// 使用compress/flate的压缩器，不依赖第三方库，压缩率较高但速度慢于snappy与zstd
+type Flate struct {
+	// 压缩级别，为0时使用flate.DefaultCompression
+	Level int
+}
+
+// 压缩src
+func (f Flate) Compress(src []byte) ([]byte, error) {
+	level := f.Level
+	if level == 0 {
+		level = flate.DefaultCompression
+	}
+
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// 解压src
+func (f Flate) Decompress(src []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(src))
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
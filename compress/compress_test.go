@@ -0,0 +1,20 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFlate(t *testing.T) {
+	src := bytes.Repeat([]byte("<div>fragment</div>"), 100)
+
+	compressed, err := Flate{}.Compress(src)
+	if err != nil || len(compressed) >= len(src) {
+		t.Fatalf("expected compressed length < %d but got %d, %v", len(src), len(compressed), err)
+	}
+
+	data, err := Flate{}.Decompress(compressed)
+	if err != nil || !bytes.Equal(data, src) {
+		t.Fatalf("round trip failed: %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package snappy
+
+import "github.com/golang/snappy"
+
+// 使用snappy的压缩器，速度快，适合对延迟敏感的场景
+type Compressor struct{}
+
+// 压缩src
+func (Compressor) Compress(src []byte) ([]byte, error) {
+	return snappy.Encode(nil, src), nil
+}
+
+// 解压src
+func (Compressor) Decompress(src []byte) ([]byte, error) {
+	return snappy.Decode(nil, src)
+}
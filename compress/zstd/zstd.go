@@ -0,0 +1,41 @@
+package zstd
+
+import "github.com/klauspost/compress/zstd"
+
+// 使用zstd的压缩器，压缩率接近flate且速度更快，可安全地被多个goroutine并发使用
+type Compressor struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+// 实例化zstd压缩器
+func New() (*Compressor, error) {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		encoder.Close()
+		return nil, err
+	}
+
+	return &Compressor{encoder: encoder, decoder: decoder}, nil
+}
+
+// 压缩src
+func (c *Compressor) Compress(src []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(src, nil), nil
+}
+
+// 解压src
+func (c *Compressor) Decompress(src []byte) ([]byte, error) {
+	return c.decoder.DecodeAll(src, nil)
+}
+
+// 释放编码器与解码器占用的资源
+func (c *Compressor) Close() {
+	c.encoder.Close()
+	c.decoder.Close()
+}
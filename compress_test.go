@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"cache/compress"
+)
+
+func TestGroup_WithCompression(t *testing.T) {
+	ctx := context.Background()
+	fragment := bytes.Repeat([]byte("<div>fragment</div>"), 100)
+	g := NewGroup("scores-compression", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		if key == "small" {
+			return []byte("630"), nil
+		}
+		return fragment, nil
+	}), WithCompression(64, compress.Flate{}))
+
+	if view, err := g.Get(ctx, "fragment"); err != nil || !bytes.Equal(view.ByteSlice(), fragment) {
+		t.Fatalf("expected decompressed fragment but got %d bytes, %v", view.Len(), err)
+	}
+	if view, ok := g.Peek("fragment"); !ok || view.Len() != len(fragment) {
+		t.Fatalf("expected Peek to decompress fragment but got %d bytes", view.Len())
+	}
+
+	// 容量按压缩后的长度计算
+	if size := g.Stats().Main.Bytes; size >= int64(len(fragment)) {
+		t.Fatalf("expected compressed size < %d but got %d", len(fragment), size)
+	}
+
+	// 小于阈值的数据不会被压缩
+	g.Get(ctx, "small")
+	if cached, ok := g.mainCache.Peek("small"); !ok || cached.(*cacheValue).compressed {
+		t.Fatalf("expected small value to be stored uncompressed")
+	}
+}
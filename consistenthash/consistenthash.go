@@ -0,0 +1,163 @@
+package consistenthash
+
+import (
+	"hash/crc32"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// 将字节切片映射为uint32的哈希函数
+type Hash func(data []byte) uint32
+
+// 一致性哈希环，每个真实节点对应replicas乘以其权重个虚拟节点
+type Map struct {
+	// 哈希函数
+	hash Hash
+
+	// 权重为1的真实节点对应的虚拟节点数量
+	replicas int
+
+	// 有序的虚拟节点哈希值，构成哈希环
+	keys []int
+
+	// 存储虚拟节点哈希值与真实节点名称映射关系的哈希表
+	hashMap map[int]string
+
+	// 存储真实节点名称与权重映射关系的哈希表
+	weights map[string]int
+}
+
+// 实例化一致性哈希环，fn为nil时默认使用crc32.ChecksumIEEE
+func New(replicas int, fn Hash) *Map {
+	m := &Map{
+		replicas: replicas,
+		hash:     fn,
+		hashMap:  make(map[int]string),
+		weights:  make(map[string]int),
+	}
+	if m.hash == nil {
+		m.hash = crc32.ChecksumIEEE
+	}
+
+	return m
+}
+
+// 添加权重为1的真实节点，节点已存在时将其权重重置为1
+func (m *Map) Add(keys ...string) {
+	for _, key := range keys {
+		m.remove(key)
+		m.add(key, 1)
+	}
+
+	sort.Ints(m.keys)
+	m.rebuild()
+}
+
+// 添加权重为weight的真实节点，节点拥有的哈希环比例与权重成正比，节点已存在时更新其权重
+func (m *Map) AddWeighted(key string, weight int) {
+	m.SetWeight(key, weight)
+}
+
+// 更新真实节点的权重，节点不存在时添加，weight小于等于0时删除节点
+func (m *Map) SetWeight(key string, weight int) {
+	m.remove(key)
+	if weight > 0 {
+		m.add(key, weight)
+	}
+
+	sort.Ints(m.keys)
+	m.rebuild()
+}
+
+// 获取真实节点的权重，节点不存在时返回0
+func (m *Map) Weight(key string) int {
+	return m.weights[key]
+}
+
+// 删除真实节点及其所有虚拟节点
+func (m *Map) Remove(keys ...string) {
+	for _, key := range keys {
+		m.remove(key)
+	}
+
+	m.rebuild()
+}
+
+// 添加真实节点的虚拟节点，调用方需重新排序哈希环
+func (m *Map) add(key string, weight int) {
+	m.weights[key] = weight
+
+	// 虚拟节点的名称为编号加真实节点名称
+	for i := 0; i < m.replicas*weight; i++ {
+		hash := int(m.hash([]byte(strconv.Itoa(i) + key)))
+		m.keys = append(m.keys, hash)
+		m.hashMap[hash] = key
+	}
+}
+
+// 删除真实节点的虚拟节点，调用方需重建哈希环
+func (m *Map) remove(key string) {
+	for i := 0; i < m.replicas*m.weights[key]; i++ {
+		hash := int(m.hash([]byte(strconv.Itoa(i) + key)))
+		if m.hashMap[hash] == key {
+			delete(m.hashMap, hash)
+		}
+	}
+	delete(m.weights, key)
+}
+
+// 重建有序的哈希环，只保留仍然存在且不重复的虚拟节点
+func (m *Map) rebuild() {
+	ring := m.keys[:0]
+	for _, hash := range m.keys {
+		if _, ok := m.hashMap[hash]; !ok {
+			continue
+		}
+		if len(ring) > 0 && ring[len(ring)-1] == hash {
+			continue
+		}
+		ring = append(ring, hash)
+	}
+	m.keys = ring
+}
+
+// 获取key所属的真实节点，哈希环为空时返回空字符串
+func (m *Map) Get(key string) string {
+	if len(m.keys) == 0 {
+		return ""
+	}
+
+	hash := int(m.hash([]byte(key)))
+
+	// 顺时针找到第一个哈希值不小于key的哈希值的虚拟节点
+	index := sort.Search(len(m.keys), func(i int) bool {
+		return m.keys[i] >= hash
+	})
+
+	// index等于len(m.keys)时回到哈希环的起点
+	return m.hashMap[m.keys[index%len(m.keys)]]
+}
+
+// 从key所属的真实节点开始顺时针获取最多n个不同的真实节点，第一个即为Get返回的节点
+func (m *Map) GetN(key string, n int) []string {
+	if len(m.keys) == 0 || n <= 0 {
+		return nil
+	}
+	n = min(n, len(m.weights))
+
+	hash := int(m.hash([]byte(key)))
+	index := sort.Search(len(m.keys), func(i int) bool {
+		return m.keys[i] >= hash
+	})
+
+	nodes := make([]string, 0, n)
+	for i := 0; i < len(m.keys) && len(nodes) < n; i++ {
+		node := m.hashMap[m.keys[(index+i)%len(m.keys)]]
+		if !slices.Contains(nodes, node) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
@@ -0,0 +1,143 @@
+package consistenthash
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// 直接将数字字符串转换为哈希值，便于推算结果
+func numericHash(key []byte) uint32 {
+	i, _ := strconv.Atoi(string(key))
+	return uint32(i)
+}
+
+func TestHashing(t *testing.T) {
+	hash := New(3, numericHash)
+
+	// 虚拟节点为 2, 4, 6, 12, 14, 16, 22, 24, 26
+	hash.Add("6", "4", "2")
+
+	testCases := map[string]string{
+		"2":  "2",
+		"11": "2",
+		"23": "4",
+		"27": "2",
+	}
+
+	for k, v := range testCases {
+		if hash.Get(k) != v {
+			t.Errorf("Asking for %s, should have yielded %s", k, v)
+		}
+	}
+
+	// 新增虚拟节点 8, 18, 28
+	hash.Add("8")
+
+	// 27现在应该映射到8
+	testCases["27"] = "8"
+
+	for k, v := range testCases {
+		if hash.Get(k) != v {
+			t.Errorf("Asking for %s, should have yielded %s", k, v)
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	hash := New(3, numericHash)
+	hash.Add("6", "4", "2", "8")
+	hash.Remove("8")
+
+	// 删除8之后27重新映射到2
+	if hash.Get("27") != "2" || len(hash.keys) != 9 {
+		t.Errorf("Asking for 27, should have yielded 2")
+	}
+
+	hash.Remove("6", "4", "2")
+	if hash.Get("27") != "" {
+		t.Errorf("empty ring should yield empty string")
+	}
+}
+
+func TestConsistency(t *testing.T) {
+	hash1 := New(50, nil)
+	hash2 := New(50, nil)
+
+	hash1.Add("Bill", "Bob", "Bonny")
+	hash2.Add("Bob", "Bonny", "Bill")
+
+	for _, key := range []string{"Ben", "Bob", "Bonny", "Bill"} {
+		if hash1.Get(key) != hash2.Get(key) {
+			t.Errorf("Fetching %s from both hashes should be the same", key)
+		}
+	}
+}
+
+func TestWeight(t *testing.T) {
+	hash := New(3, numericHash)
+
+	// 虚拟节点为 2, 12, 22, 32, 42, 52 与 4, 14, 24
+	hash.AddWeighted("2", 2)
+	hash.Add("4")
+	if hash.Get("30") != "2" || hash.Weight("2") != 2 {
+		t.Errorf("Asking for 30, should have yielded 2")
+	}
+
+	// 4的虚拟节点变为 4, 14, 24, 34, 44, 54，2的虚拟节点变为 2, 12, 22
+	hash.SetWeight("4", 2)
+	hash.SetWeight("2", 1)
+	if hash.Get("30") != "4" || len(hash.keys) != 9 {
+		t.Errorf("Asking for 30, should have yielded 4, ring has %d nodes", len(hash.keys))
+	}
+
+	hash.SetWeight("4", 0)
+	if hash.Get("30") != "2" || hash.Weight("4") != 0 || len(hash.keys) != 3 {
+		t.Errorf("expected 4 to be removed")
+	}
+}
+
+func TestWeightDistribution(t *testing.T) {
+	// crc32对相似的短字符串分布不够均匀，这里使用fnv
+	hash := New(100, func(data []byte) uint32 {
+		h := fnv.New32a()
+		h.Write(data)
+		return h.Sum32()
+	})
+	hash.Add("small")
+	hash.AddWeighted("big", 3)
+
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		counts[hash.Get(strconv.Itoa(i))]++
+	}
+	if ratio := float64(counts["big"]) / float64(counts["small"]); ratio < 2 || ratio > 4 {
+		t.Errorf("expected big to own about 3 times as many keys but got %v", counts)
+	}
+}
+
+func TestGetN(t *testing.T) {
+	hash := New(3, numericHash)
+	if nodes := hash.GetN("11", 2); nodes != nil {
+		t.Errorf("empty ring should yield nil but got %v", nodes)
+	}
+
+	// 虚拟节点为 2, 4, 6, 12, 14, 16, 22, 24, 26
+	hash.Add("6", "4", "2")
+
+	testCases := map[string][]string{
+		"11": {"2", "4"},
+		"25": {"6", "2"},
+	}
+	for k, v := range testCases {
+		if nodes := hash.GetN(k, 2); !slices.Equal(nodes, v) {
+			t.Errorf("Asking for %s, should have yielded %v but got %v", k, v, nodes)
+		}
+	}
+
+	// n超过节点数量时返回所有节点
+	if nodes := hash.GetN("25", 5); !slices.Equal(nodes, []string{"6", "2", "4"}) {
+		t.Errorf("expected all nodes but got %v", nodes)
+	}
+}
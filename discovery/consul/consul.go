@@ -0,0 +1,263 @@
+package consul
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"cache/discovery"
+)
+
+const (
+	// 注册的默认服务名
+	defaultService = "cache"
+
+	// TTL健康检查的默认存活时间
+	defaultTTL = 10 * time.Second
+
+	// 健康检查持续失败超过该时间后Consul自动注销服务，Consul允许的最小值为1分钟
+	deregisterAfter = time.Minute
+
+	// 阻塞查询的最长等待时间
+	waitTime = 5 * time.Minute
+
+	// 服务元数据中节点地址的key
+	addrMeta = "addr"
+)
+
+func init() {
+	discovery.RegisterBackend("consul", open)
+}
+
+// 按cfg创建客户端与Registry，Cluster作为服务名，Endpoints中的第一个地址作为Consul agent的地址
+func open(cfg discovery.Config) (discovery.Registry, error) {
+	config := api.DefaultConfig()
+	if len(cfg.Endpoints) > 0 {
+		config.Address = cfg.Endpoints[0]
+	}
+	client, err := api.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []Option
+	if cfg.Cluster != "" {
+		opts = append(opts, WithService(cfg.Cluster))
+	}
+	if cfg.TTL > 0 {
+		opts = append(opts, WithTTL(cfg.TTL))
+	}
+
+	return New(client, opts...), nil
+}
+
+// 实例化Registry时的可选配置
+type Option func(r *Registry)
+
+// 设置注册的服务名，同一个集群的节点需要使用相同的服务名
+func WithService(name string) Option {
+	return func(r *Registry) {
+		r.service = name
+	}
+}
+
+// 设置TTL健康检查的存活时间，节点每隔ttl/3上报一次健康状态，最小为1秒
+func WithTTL(ttl time.Duration) Option {
+	return func(r *Registry) {
+		r.ttl = max(ttl, time.Second)
+	}
+}
+
+// 使用Consul主动请求的HTTP健康检查代替TTL健康检查，url通常为节点的管理接口，
+// Consul每隔interval请求一次，返回2xx时认为节点健康
+func WithHTTPCheck(url string, interval time.Duration) Option {
+	return func(r *Registry) {
+		r.checkURL = url
+		r.checkInterval = interval
+	}
+}
+
+// 基于Consul的节点发现，节点注册为带健康检查的服务实例，监听服务中健康的实例即可得到集群中所有存活的节点
+type Registry struct {
+	client *api.Client
+
+	// 注册的服务名
+	service string
+
+	// TTL健康检查的存活时间
+	ttl time.Duration
+
+	// HTTP健康检查的地址与间隔，为空时使用TTL健康检查
+	checkURL      string
+	checkInterval time.Duration
+
+	// 保护以下字段的互斥锁
+	mu sync.Mutex
+
+	// 已注册的服务实例ID
+	id string
+
+	// 停止上报健康状态
+	cancel context.CancelFunc
+}
+
+var _ discovery.Registry = (*Registry)(nil)
+
+// 使用client实例化Registry
+func New(client *api.Client, opts ...Option) *Registry {
+	r := &Registry{
+		client:  client,
+		service: defaultService,
+		ttl:     defaultTTL,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// 将addr注册为服务实例，使用TTL健康检查时在后台定期上报健康状态，直到调用Deregister。
+// 同一个Registry只能注册一个节点
+func (r *Registry) Register(ctx context.Context, addr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.id != "" {
+		panic("Register called more than once")
+	}
+
+	id := r.service + "-" + addr
+	check := &api.AgentServiceCheck{
+		CheckID:                        "service:" + id,
+		DeregisterCriticalServiceAfter: deregisterAfter.String(),
+	}
+	if r.checkURL != "" {
+		check.HTTP = r.checkURL
+		check.Interval = r.checkInterval.String()
+	} else {
+		check.TTL = r.ttl.String()
+	}
+
+	registration := &api.AgentServiceRegistration{
+		ID:    id,
+		Name:  r.service,
+		Meta:  map[string]string{addrMeta: addr},
+		Check: check,
+	}
+	// Consul中显示节点的主机与端口，其他节点使用元数据中的完整地址
+	if u, err := url.Parse(addr); err == nil && u.Host != "" {
+		registration.Address = u.Hostname()
+		registration.Port, _ = strconv.Atoi(u.Port())
+	}
+	if err := r.client.Agent().ServiceRegister(registration); err != nil {
+		return err
+	}
+
+	r.id = id
+	if r.checkURL == "" {
+		if err := r.client.Agent().UpdateTTL(check.CheckID, "", api.HealthPassing); err != nil {
+			r.client.Agent().ServiceDeregister(id)
+			r.id = ""
+			return err
+		}
+
+		var heartbeatCtx context.Context
+		heartbeatCtx, r.cancel = context.WithCancel(context.Background())
+		go r.heartbeat(heartbeatCtx, check.CheckID)
+	}
+
+	return nil
+}
+
+// 每隔ttl/3上报一次健康状态
+func (r *Registry) heartbeat(ctx context.Context, checkID string) {
+	ticker := time.NewTicker(r.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.client.Agent().UpdateTTL(checkID, "", api.HealthPassing); err != nil {
+				log.Println("[Consul] Failed to update health check", checkID, err)
+			}
+		}
+	}
+}
+
+// 停止上报健康状态并注销服务实例，其他节点会立即将本节点从环中移除
+func (r *Registry) Deregister(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.id == "" {
+		return nil
+	}
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+
+	id := r.id
+	r.id = ""
+
+	return r.client.Agent().ServiceDeregister(id)
+}
+
+// Consul的客户端没有需要释放的资源
+func (r *Registry) Close() error {
+	return nil
+}
+
+// 通过阻塞查询监听服务中健康的实例，每次节点变化时以排序后的所有节点地址调用onChange，
+// 开始监听时会先以当前的节点调用一次，阻塞直到ctx结束或查询出错
+func (r *Registry) Watch(ctx context.Context, onChange func(peers []string)) error {
+	var index uint64
+	var last []string
+	for first := true; ; first = false {
+		opts := (&api.QueryOptions{WaitIndex: index, WaitTime: waitTime}).WithContext(ctx)
+		entries, meta, err := r.client.Health().Service(r.service, "", true, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		// 索引变小说明Consul的状态被重置，需要重新开始阻塞查询
+		if index = meta.LastIndex; index < opts.WaitIndex {
+			index = 0
+		}
+
+		peers := peersOf(entries)
+		if first || !slices.Equal(peers, last) {
+			onChange(peers)
+			last = peers
+		}
+	}
+}
+
+// 获取服务实例中排序后的节点地址，没有地址元数据的实例使用注册的主机与端口
+func peersOf(entries []*api.ServiceEntry) []string {
+	peers := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		addr, ok := entry.Service.Meta[addrMeta]
+		if !ok {
+			addr = net.JoinHostPort(entry.Service.Address, strconv.Itoa(entry.Service.Port))
+		}
+		peers = append(peers, addr)
+	}
+	sort.Strings(peers)
+
+	return peers
+}
@@ -0,0 +1,175 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"cache/discovery"
+)
+
+// 模拟Consul agent的注册、TTL健康检查与阻塞查询接口
+type fakeAgent struct {
+	mu       sync.Mutex
+	changed  *sync.Cond
+	index    uint64
+	services map[string]*api.AgentServiceRegistration
+	passing  map[string]bool
+}
+
+func newFakeAgent(t *testing.T) *api.Client {
+	agent := &fakeAgent{
+		index:    1,
+		services: make(map[string]*api.AgentServiceRegistration),
+		passing:  make(map[string]bool),
+	}
+	agent.changed = sync.NewCond(&agent.mu)
+
+	server := httptest.NewServer(agent)
+	t.Cleanup(server.Close)
+
+	config := api.DefaultConfig()
+	config.Address = strings.TrimPrefix(server.URL, "http://")
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func (a *fakeAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch path := r.URL.Path; {
+	case path == "/v1/agent/service/register":
+		var s api.AgentServiceRegistration
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		a.services[s.ID] = &s
+	case strings.HasPrefix(path, "/v1/agent/check/update/"):
+		a.passing[strings.TrimPrefix(path, "/v1/agent/check/update/service:")] = true
+	case strings.HasPrefix(path, "/v1/agent/service/deregister/"):
+		delete(a.services, strings.TrimPrefix(path, "/v1/agent/service/deregister/"))
+	case strings.HasPrefix(path, "/v1/health/service/"):
+		a.health(w, r, strings.TrimPrefix(path, "/v1/health/service/"))
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	a.index++
+	a.changed.Broadcast()
+}
+
+// 阻塞直到索引超过请求中的index，返回健康的实例
+func (a *fakeAgent) health(w http.ResponseWriter, r *http.Request, service string) {
+	index, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+	// 客户端取消请求时唤醒等待
+	stop := context.AfterFunc(r.Context(), func() {
+		a.mu.Lock()
+		a.changed.Broadcast()
+		a.mu.Unlock()
+	})
+	defer stop()
+
+	for a.index <= index && r.Context().Err() == nil {
+		a.changed.Wait()
+	}
+
+	entries := []*api.ServiceEntry{}
+	for id, s := range a.services {
+		if s.Name == service && a.passing[id] {
+			entries = append(entries, &api.ServiceEntry{Service: &api.AgentService{
+				ID: s.ID, Service: s.Name, Address: s.Address, Port: s.Port, Meta: s.Meta,
+			}})
+		}
+	}
+
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(a.index, 10))
+	json.NewEncoder(w).Encode(entries)
+}
+
+func TestRegistry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := newFakeAgent(t)
+
+	a := New(client, WithService("scores"))
+	if err := a.Register(ctx, "http://10.0.0.1:8008"); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan []string, 10)
+	done := make(chan error)
+	go func() {
+		done <- New(client, WithService("scores")).Watch(ctx, func(peers []string) {
+			changes <- peers
+		})
+	}()
+
+	expect := func(want ...string) {
+		t.Helper()
+		select {
+		case peers := <-changes:
+			if !reflect.DeepEqual(peers, want) {
+				t.Fatalf("expected peers %v but got %v", want, peers)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for peers %v", want)
+		}
+	}
+
+	// 开始监听时得到已注册的节点
+	expect("http://10.0.0.1:8008")
+
+	b := New(client, WithService("scores"), WithTTL(time.Second))
+	if err := b.Register(ctx, "http://10.0.0.2:8008"); err != nil {
+		t.Fatal(err)
+	}
+	expect("http://10.0.0.1:8008", "http://10.0.0.2:8008")
+
+	// 健康状态的上报不会改变节点列表
+	time.Sleep(time.Second)
+	select {
+	case peers := <-changes:
+		t.Fatalf("unexpected change %v", peers)
+	default:
+	}
+
+	if err := a.Deregister(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expect("http://10.0.0.2:8008")
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("expected context.Canceled but got %v", err)
+	}
+	b.Deregister(context.Background())
+}
+
+func TestOpen(t *testing.T) {
+	registry, err := discovery.Open(discovery.Config{Backend: "consul", Endpoints: []string{"127.0.0.1:8500"}, Cluster: "scores"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer registry.Close()
+
+	if r := registry.(*Registry); r.service != "scores" || r.ttl != defaultTTL {
+		t.Fatalf("unexpected registry %+v", r)
+	}
+}
@@ -0,0 +1,94 @@
+// 节点发现，节点启动时将自己注册到服务发现的后端，并监听集群中所有存活的节点来更新一致性哈希环，
+// 无需静态配置节点列表。后端实现位于子包中，导入子包即可通过Config选择对应的后端：
+//
+//	import _ "cache/discovery/etcd"
+//
+//	registry, err := discovery.Open(discovery.Config{Backend: "etcd", Endpoints: []string{"127.0.0.1:2379"}})
+//	registry.Register(ctx, "http://10.0.0.1:8008")
+//	go registry.Watch(ctx, func(peers []string) { pool.Set(peers...) })
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 节点发现的后端
+type Registry interface {
+	// 将本节点的地址注册到后端，并在后台保持注册直到调用Deregister
+	Register(ctx context.Context, addr string) error
+
+	// 将本节点从后端移除，其他节点会将本节点从环中移除
+	Deregister(ctx context.Context) error
+
+	// 每次节点变化时以排序后的所有节点地址调用onChange，开始监听时会先以当前的节点调用一次，
+	// 阻塞直到ctx结束或监听出错
+	Watch(ctx context.Context, onChange func(peers []string)) error
+
+	// 关闭Open创建的客户端
+	Close() error
+}
+
+// 选择并配置后端
+type Config struct {
+	// 后端的名称，例如"etcd"、"consul"或"memberlist"
+	Backend string
+
+	// 后端的地址，gossip类的后端为加入集群时联系的已有节点
+	Endpoints []string
+
+	// 本节点用于gossip的监听地址，例如"0.0.0.0:7946"，只有gossip类的后端使用
+	Bind string
+
+	// 集群的名称，同一个集群的节点需要使用相同的名称，不指定时使用后端的默认值
+	Cluster string
+
+	// 注册的存活时间，节点异常退出后最多经过TTL从其他节点的环中移除，不指定时使用后端的默认值
+	TTL time.Duration
+}
+
+var (
+	mu       sync.RWMutex
+	backends = make(map[string]func(cfg Config) (Registry, error))
+)
+
+// 注册名为name的后端，通常在后端的子包的init中调用，name重复时panic
+func RegisterBackend(name string, open func(cfg Config) (Registry, error)) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := backends[name]; ok {
+		panic("discovery: duplicate backend " + name)
+	}
+	backends[name] = open
+}
+
+// 获取所有已注册的后端的名称
+func Backends() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// 按cfg.Backend选择后端并创建Registry
+func Open(cfg Config) (Registry, error) {
+	mu.RLock()
+	open, ok := backends[cfg.Backend]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("discovery: unknown backend %q (forgotten import?)", cfg.Backend)
+	}
+
+	return open(cfg)
+}
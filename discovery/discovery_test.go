@@ -0,0 +1,51 @@
+package discovery
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// 什么也不做的后端
+type nopRegistry struct {
+	cfg Config
+}
+
+func (r *nopRegistry) Register(ctx context.Context, addr string) error { return nil }
+func (r *nopRegistry) Deregister(ctx context.Context) error            { return nil }
+func (r *nopRegistry) Close() error                                    { return nil }
+
+func (r *nopRegistry) Watch(ctx context.Context, onChange func(peers []string)) error {
+	onChange(r.cfg.Endpoints)
+	return nil
+}
+
+func TestOpen(t *testing.T) {
+	RegisterBackend("nop", func(cfg Config) (Registry, error) {
+		return &nopRegistry{cfg: cfg}, nil
+	})
+
+	registry, err := Open(Config{Backend: "nop", Endpoints: []string{"http://10.0.0.1:8008"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var peers []string
+	registry.Watch(context.Background(), func(p []string) { peers = p })
+	if !reflect.DeepEqual(peers, []string{"http://10.0.0.1:8008"}) {
+		t.Fatalf("unexpected peers %v", peers)
+	}
+
+	if names := Backends(); !reflect.DeepEqual(names, []string{"nop"}) {
+		t.Fatalf("unexpected backends %v", names)
+	}
+	if _, err := Open(Config{Backend: "unknown"}); err == nil {
+		t.Fatalf("expected error for unknown backend")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic for duplicate backend")
+		}
+	}()
+	RegisterBackend("nop", nil)
+}
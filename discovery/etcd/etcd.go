@@ -0,0 +1,260 @@
+package etcd
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"cache/discovery"
+)
+
+const (
+	// 节点注册的默认key前缀
+	defaultPrefix = "/_cache/peers/"
+
+	// 租约的默认存活时间
+	defaultTTL = 10 * time.Second
+
+	// 租约丢失后重新注册的间隔
+	retryInterval = time.Second
+
+	// 连接etcd的超时时间
+	dialTimeout = 5 * time.Second
+)
+
+func init() {
+	discovery.RegisterBackend("etcd", open)
+}
+
+// 按cfg创建客户端与Registry，Cluster作为key前缀的一部分
+func open(cfg discovery.Config) (discovery.Registry, error) {
+	client, err := clientv3.New(clientv3.Config{Endpoints: cfg.Endpoints, DialTimeout: dialTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []Option
+	if cfg.Cluster != "" {
+		opts = append(opts, WithPrefix("/_cache/"+cfg.Cluster+"/peers/"))
+	}
+	if cfg.TTL > 0 {
+		opts = append(opts, WithTTL(cfg.TTL))
+	}
+
+	r := New(client, opts...)
+	r.owned = true
+
+	return r, nil
+}
+
+// 实例化Registry时的可选配置
+type Option func(r *Registry)
+
+// 设置节点注册的key前缀，同一个集群的节点需要使用相同的前缀
+func WithPrefix(prefix string) Option {
+	return func(r *Registry) {
+		r.prefix = prefix
+	}
+}
+
+// 设置租约的存活时间，节点异常退出后最多经过ttl从其他节点的环中移除，最小为1秒
+func WithTTL(ttl time.Duration) Option {
+	return func(r *Registry) {
+		r.ttl = max(ttl, time.Second)
+	}
+}
+
+// 基于etcd的节点发现，节点以带租约的key注册在前缀下，监听前缀即可得到集群中所有存活的节点
+type Registry struct {
+	client *clientv3.Client
+
+	// 节点注册的key前缀
+	prefix string
+
+	// 租约的存活时间
+	ttl time.Duration
+
+	// client是否由Registry创建，Close时需要关闭
+	owned bool
+
+	// 保护以下字段的互斥锁
+	mu sync.Mutex
+
+	// 当前注册使用的租约
+	lease clientv3.LeaseID
+
+	// 停止续约
+	cancel context.CancelFunc
+}
+
+var _ discovery.Registry = (*Registry)(nil)
+
+// 使用client实例化Registry
+func New(client *clientv3.Client, opts ...Option) *Registry {
+	r := &Registry{
+		client: client,
+		prefix: defaultPrefix,
+		ttl:    defaultTTL,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// 将addr注册到etcd并在后台持续续约，租约丢失（例如与etcd断开时间超过ttl）后会自动重新注册，
+// 直到调用Deregister。同一个Registry只能注册一个节点
+func (r *Registry) Register(ctx context.Context, addr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		panic("Register called more than once")
+	}
+
+	lease, err := r.grant(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	keepCtx, cancel := context.WithCancel(context.Background())
+	keepAlive, err := r.client.KeepAlive(keepCtx, lease)
+	if err != nil {
+		cancel()
+		r.client.Revoke(ctx, lease)
+		return err
+	}
+
+	r.lease, r.cancel = lease, cancel
+	go r.keepAlive(keepCtx, addr, keepAlive)
+
+	return nil
+}
+
+// 申请租约并以该租约写入addr
+func (r *Registry) grant(ctx context.Context, addr string) (clientv3.LeaseID, error) {
+	grant, err := r.client.Grant(ctx, int64(r.ttl/time.Second))
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := r.client.Put(ctx, r.prefix+addr, addr, clientv3.WithLease(grant.ID)); err != nil {
+		r.client.Revoke(ctx, grant.ID)
+		return 0, err
+	}
+
+	return grant.ID, nil
+}
+
+// 消费续约的响应，续约通道关闭时重新注册
+func (r *Registry) keepAlive(ctx context.Context, addr string, ch <-chan *clientv3.LeaseKeepAliveResponse) {
+	for {
+		for range ch {
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Println("[Etcd] Lease lost, registering", addr, "again")
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+
+			lease, err := r.grant(ctx, addr)
+			if err != nil {
+				log.Println("[Etcd] Failed to register", addr, err)
+				continue
+			}
+			if ch, err = r.client.KeepAlive(ctx, lease); err != nil {
+				log.Println("[Etcd] Failed to keep alive", addr, err)
+				r.client.Revoke(context.Background(), lease)
+				continue
+			}
+
+			r.mu.Lock()
+			r.lease = lease
+			r.mu.Unlock()
+			break
+		}
+	}
+}
+
+// 停止续约并撤销租约，其他节点会立即将本节点从环中移除
+func (r *Registry) Deregister(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+	r.cancel = nil
+
+	_, err := r.client.Revoke(ctx, r.lease)
+
+	return err
+}
+
+// 关闭通过discovery.Open创建的客户端，使用New传入的客户端需要由调用方关闭
+func (r *Registry) Close() error {
+	if !r.owned {
+		return nil
+	}
+
+	return r.client.Close()
+}
+
+// 监听前缀下注册的节点，每次节点变化时以排序后的所有节点地址调用onChange，
+// 通常传入httppool或grpcpool的Set。开始监听时会先以当前的节点调用一次，阻塞直到ctx结束或监听出错
+func (r *Registry) Watch(ctx context.Context, onChange func(peers []string)) error {
+	res, err := r.client.Get(ctx, r.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+
+	peers := make(map[string]string, len(res.Kvs))
+	for _, kv := range res.Kvs {
+		peers[string(kv.Key)] = string(kv.Value)
+	}
+	onChange(sortedPeers(peers))
+
+	// 从读取时的下一个版本开始监听，不会遗漏两次操作之间的变化
+	watch := r.client.Watch(ctx, r.prefix, clientv3.WithPrefix(), clientv3.WithRev(res.Header.Revision+1))
+	for res := range watch {
+		if err := res.Err(); err != nil {
+			return err
+		}
+
+		for _, event := range res.Events {
+			key := string(event.Kv.Key)
+			if event.Type == mvccpb.DELETE {
+				delete(peers, key)
+			} else {
+				peers[key] = string(event.Kv.Value)
+			}
+		}
+		onChange(sortedPeers(peers))
+	}
+
+	return ctx.Err()
+}
+
+// 获取排序后的节点地址
+func sortedPeers(peers map[string]string) []string {
+	addrs := make([]string, 0, len(peers))
+	for _, addr := range peers {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	return addrs
+}
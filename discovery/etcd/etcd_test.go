@@ -0,0 +1,118 @@
+package etcd
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+
+	"cache/discovery"
+)
+
+// 启动一个进程内的etcd服务端并返回连接它的客户端
+func newClient(t *testing.T) *clientv3.Client {
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	clientURL, _ := url.Parse("http://127.0.0.1:0")
+	peerURL, _ := url.Parse("http://127.0.0.1:0")
+	cfg.ListenClientUrls = []url.URL{*clientURL}
+	cfg.ListenPeerUrls = []url.URL{*peerURL}
+
+	server, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.Fatal("etcd server is not ready")
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{server.Clients[0].Addr().String()},
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestRegistry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := newClient(t)
+
+	a := New(client, WithPrefix("/scores/"))
+	if err := a.Register(ctx, "http://10.0.0.1:8008"); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan []string, 10)
+	done := make(chan error)
+	go func() {
+		done <- New(client, WithPrefix("/scores/")).Watch(ctx, func(peers []string) {
+			changes <- peers
+		})
+	}()
+
+	expect := func(want ...string) {
+		t.Helper()
+		select {
+		case peers := <-changes:
+			if !reflect.DeepEqual(peers, want) {
+				t.Fatalf("expected peers %v but got %v", want, peers)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for peers %v", want)
+		}
+	}
+
+	// 开始监听时得到已注册的节点
+	expect("http://10.0.0.1:8008")
+
+	b := New(client, WithPrefix("/scores/"), WithTTL(time.Second))
+	if err := b.Register(ctx, "http://10.0.0.2:8008"); err != nil {
+		t.Fatal(err)
+	}
+	expect("http://10.0.0.1:8008", "http://10.0.0.2:8008")
+
+	// 续约使节点在超过ttl后仍然存活
+	time.Sleep(2 * time.Second)
+	select {
+	case peers := <-changes:
+		t.Fatalf("unexpected change %v", peers)
+	default:
+	}
+
+	if err := a.Deregister(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expect("http://10.0.0.2:8008")
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("expected context.Canceled but got %v", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	registry, err := discovery.Open(discovery.Config{Backend: "etcd", Endpoints: []string{"127.0.0.1:2379"}, Cluster: "scores"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer registry.Close()
+
+	if r := registry.(*Registry); r.prefix != "/_cache/scores/peers/" || r.ttl != defaultTTL {
+		t.Fatalf("unexpected registry %+v", r)
+	}
+}
@@ -0,0 +1,209 @@
+package memberlist
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+
+	"cache/discovery"
+)
+
+// 离开集群时等待通知其他节点的最长时间
+const leaveTimeout = 5 * time.Second
+
+// 尚未调用Register加入集群
+var ErrNotRegistered = errors.New("memberlist: not registered")
+
+func init() {
+	discovery.RegisterBackend("memberlist", open)
+}
+
+// 按cfg创建Registry，Endpoints为加入集群时联系的已有节点，Bind为gossip的监听地址，Cluster作为集群的标签
+func open(cfg discovery.Config) (discovery.Registry, error) {
+	config := memberlist.DefaultLANConfig()
+	if cfg.Bind != "" {
+		host, port, err := net.SplitHostPort(cfg.Bind)
+		if err != nil {
+			return nil, err
+		}
+		if config.BindPort, err = strconv.Atoi(port); err != nil {
+			return nil, err
+		}
+		config.BindAddr = host
+	}
+	config.Label = cfg.Cluster
+
+	return New(config, cfg.Endpoints), nil
+}
+
+// 基于gossip的节点发现，节点之间通过memberlist交换成员信息并探测故障，不依赖外部的协调服务。
+// 故障的节点被判定为下线后会从其他节点的环中移除
+type Registry struct {
+	config *memberlist.Config
+
+	// 加入集群时联系的已有节点
+	seeds []string
+
+	// 保护list的互斥锁
+	mu sync.Mutex
+
+	// 已加入的集群，未调用Register时为nil
+	list *memberlist.Memberlist
+
+	// 保护changed的互斥锁，memberlist在Create与Leave中同步通知成员变化，不能与mu共用
+	notifyMu sync.Mutex
+
+	// 成员变化时关闭并替换，用于通知所有的Watch
+	changed chan struct{}
+}
+
+var _ discovery.Registry = (*Registry)(nil)
+
+// 使用config实例化Registry，seeds为加入集群时联系的已有节点的gossip地址，为空时作为集群的第一个节点启动
+func New(config *memberlist.Config, seeds []string) *Registry {
+	return &Registry{
+		config:  config,
+		seeds:   seeds,
+		changed: make(chan struct{}),
+	}
+}
+
+// 以addr作为成员的名称启动gossip并加入集群，其他节点通过成员的名称得到本节点的地址。
+// 同一个Registry只能注册一个节点
+func (r *Registry) Register(ctx context.Context, addr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.list != nil {
+		panic("Register called more than once")
+	}
+
+	config := *r.config
+	config.Name = addr
+	config.Events = (*events)(r)
+	if config.Logger == nil && config.LogOutput == nil {
+		config.LogOutput = io.Discard
+	}
+
+	list, err := memberlist.Create(&config)
+	if err != nil {
+		return err
+	}
+	if len(r.seeds) > 0 {
+		if _, err := list.Join(r.seeds); err != nil {
+			list.Shutdown()
+			return err
+		}
+	}
+
+	r.list = list
+	r.notify()
+
+	return nil
+}
+
+// 本节点用于gossip的地址，其他节点可以将它作为seeds加入集群
+func (r *Registry) LocalAddr() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.list == nil {
+		return ""
+	}
+
+	return r.list.LocalNode().Address()
+}
+
+// 通知其他节点本节点离开并停止gossip，其他节点会立即将本节点从环中移除
+func (r *Registry) Deregister(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.list == nil {
+		return nil
+	}
+
+	timeout := leaveTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	err := r.list.Leave(timeout)
+	if shutdownErr := r.list.Shutdown(); err == nil {
+		err = shutdownErr
+	}
+	r.list = nil
+	r.notify()
+
+	return err
+}
+
+// 与Deregister相同
+func (r *Registry) Close() error {
+	return r.Deregister(context.Background())
+}
+
+// 监听集群中存活的成员，每次成员变化时以排序后的所有节点地址调用onChange，
+// 开始监听时会先以当前的成员调用一次，阻塞直到ctx结束或本节点离开集群。需要先调用Register
+func (r *Registry) Watch(ctx context.Context, onChange func(peers []string)) error {
+	var last []string
+	for first := true; ; first = false {
+		r.notifyMu.Lock()
+		changed := r.changed
+		r.notifyMu.Unlock()
+
+		r.mu.Lock()
+		list := r.list
+		r.mu.Unlock()
+
+		if list == nil {
+			return ErrNotRegistered
+		}
+
+		peers := peersOf(list.Members())
+		if first || !slices.Equal(peers, last) {
+			onChange(peers)
+			last = peers
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// 唤醒所有的Watch
+func (r *Registry) notify() {
+	r.notifyMu.Lock()
+	defer r.notifyMu.Unlock()
+
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// 获取成员中排序后的节点地址
+func peersOf(nodes []*memberlist.Node) []string {
+	peers := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		peers = append(peers, node.Name)
+	}
+	sort.Strings(peers)
+
+	return peers
+}
+
+// 接收memberlist的成员变化通知，与Registry的方法分开避免成为导出的方法
+type events Registry
+
+func (e *events) NotifyJoin(*memberlist.Node)   { (*Registry)(e).notify() }
+func (e *events) NotifyLeave(*memberlist.Node)  { (*Registry)(e).notify() }
+func (e *events) NotifyUpdate(*memberlist.Node) { (*Registry)(e).notify() }
@@ -0,0 +1,92 @@
+package memberlist
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+
+	"cache/discovery"
+)
+
+// 在本机的随机端口上启动gossip并加入seeds
+func newRegistry(t *testing.T, addr string, seeds ...string) *Registry {
+	config := memberlist.DefaultLocalConfig()
+	config.BindAddr = "127.0.0.1"
+	config.BindPort = 0
+
+	r := New(config, seeds)
+	if err := r.Register(context.Background(), addr); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+
+	return r
+}
+
+func TestRegistry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := New(memberlist.DefaultLocalConfig(), nil).Watch(ctx, func([]string) {}); err != ErrNotRegistered {
+		t.Fatalf("expected ErrNotRegistered but got %v", err)
+	}
+
+	a := newRegistry(t, "http://10.0.0.1:8008")
+	changes := make(chan []string, 10)
+	done := make(chan error)
+	go func() {
+		done <- a.Watch(ctx, func(peers []string) {
+			changes <- peers
+		})
+	}()
+
+	expect := func(want ...string) {
+		t.Helper()
+		timeout := time.After(10 * time.Second)
+		for {
+			select {
+			case peers := <-changes:
+				if reflect.DeepEqual(peers, want) {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timed out waiting for peers %v", want)
+			}
+		}
+	}
+
+	// 开始监听时只有本节点
+	expect("http://10.0.0.1:8008")
+
+	b := newRegistry(t, "http://10.0.0.2:8008", a.LocalAddr())
+	expect("http://10.0.0.1:8008", "http://10.0.0.2:8008")
+
+	if err := b.Deregister(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expect("http://10.0.0.1:8008")
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("expected context.Canceled but got %v", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	registry, err := discovery.Open(discovery.Config{Backend: "memberlist", Bind: "127.0.0.1:7946", Cluster: "scores"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer registry.Close()
+
+	if r := registry.(*Registry); r.config.BindAddr != "127.0.0.1" || r.config.BindPort != 7946 || r.config.Label != "scores" {
+		t.Fatalf("unexpected config %+v", r.config)
+	}
+
+	if _, err := discovery.Open(discovery.Config{Backend: "memberlist", Bind: "7946"}); err == nil {
+		t.Fatalf("expected error for bad bind address")
+	}
+}
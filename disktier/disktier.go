@@ -0,0 +1,250 @@
+package disktier
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"sync"
+
+	"cache/lru"
+)
+
+// 默认的大value阈值（单位为字节），超过该大小的value存储在磁盘上
+const DefaultThreshold = 1 << 20
+
+// value超过磁盘文件的大小
+var ErrTooLarge = errors.New("disktier: value too large")
+
+// 打开Cache时的可选配置
+type Option func(c *Cache)
+
+// 设置大value阈值（单位为字节），超过该大小的value存储在磁盘上
+func WithThreshold(bytes int) Option {
+	return func(c *Cache) {
+		c.threshold = bytes
+	}
+}
+
+// 内存中存储的value
+type bytesValue []byte
+
+// 获取字节切片的长度
+func (b bytesValue) Len() int {
+	return len(b)
+}
+
+// 磁盘文件中的一段连续区域
+type extent struct {
+	offset int64
+	length int64
+}
+
+// 索引中存储的value，记录数据在磁盘文件中的位置，Len为其占用的磁盘空间
+type diskRef extent
+
+// 获取数据占用的磁盘空间
+func (r diskRef) Len() int {
+	return int(r.length)
+}
+
+// 两级缓存：小value存储在内存中，大value存储在内存映射的磁盘文件中，内存中只保留索引。
+// 两级各自按LRU顺序淘汰。可安全地被多个goroutine并发使用
+type Cache struct {
+	// 大value阈值
+	threshold int
+
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 存储小value的内存缓存
+	memory *lru.Cache
+
+	// 大value的索引，容量为磁盘文件的大小
+	index *lru.Cache
+
+	// 磁盘文件
+	file *os.File
+
+	// 磁盘文件映射到内存中的区域
+	data []byte
+
+	// 按offset排序的空闲区域
+	free []extent
+}
+
+// 打开path处的磁盘文件，memoryBytes与diskBytes分别为内存与磁盘文件的容量（单位为字节）。
+// 磁盘文件只用作缓存空间，重新打开时其中的数据不会被恢复
+func Open(path string, memoryBytes, diskBytes int64, opts ...Option) (*Cache, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Truncate(diskBytes); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	data, err := mmap(file, int(diskBytes))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	c := &Cache{
+		threshold: DefaultThreshold,
+		memory:    lru.New(memoryBytes, nil),
+		file:      file,
+		data:      data,
+		free:      []extent{{offset: 0, length: diskBytes}},
+	}
+	// 索引的回调函数在持有c.mu时执行，释放被淘汰的条目占用的磁盘空间
+	c.index = lru.New(diskBytes, func(key string, value lru.Value) {
+		c.release(extent(value.(diskRef)))
+	})
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// 实现查找功能，返回数据的副本
+func (c *Cache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if value, ok := c.memory.Get(key); ok {
+		return cloneBytes(value.(bytesValue)), true
+	}
+
+	if value, ok := c.index.Get(key); ok {
+		ref := value.(diskRef)
+		return cloneBytes(c.data[ref.offset : ref.offset+ref.length]), true
+	}
+
+	return nil, false
+}
+
+// 实现新增与修改功能，value超过阈值时写入磁盘文件，磁盘空间不足时按LRU顺序淘汰磁盘上的条目
+func (c *Cache) Add(key string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(key)
+
+	if len(value) <= c.threshold {
+		c.memory.Add(key, bytesValue(cloneBytes(value)))
+		return nil
+	}
+
+	// 索引中条目的开销包括key，超过磁盘文件的大小时索引不会接受
+	length := int64(len(value))
+	if int64(len(key))+length > int64(len(c.data)) {
+		return ErrTooLarge
+	}
+
+	ext, ok := c.allocate(length)
+	for !ok && c.index.Len() > 0 {
+		c.index.RemoveOldest()
+		ext, ok = c.allocate(length)
+	}
+	if !ok {
+		return ErrTooLarge
+	}
+
+	copy(c.data[ext.offset:], value)
+	if err := c.index.TryAdd(key, diskRef(ext), 0); err != nil {
+		c.release(ext)
+		return err
+	}
+
+	return nil
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.remove(key)
+}
+
+// 从两级缓存中删除key，调用方需持有锁
+func (c *Cache) remove(key string) bool {
+	inMemory := c.memory.Remove(key)
+	onDisk := c.index.Remove(key)
+
+	return inMemory || onDisk
+}
+
+// 获取内存与磁盘上的条目数量
+func (c *Cache) Len() (memory, disk int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.memory.Len(), c.index.Len()
+}
+
+// 解除内存映射并关闭磁盘文件
+func (c *Cache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := munmap(c.data); err != nil {
+		return err
+	}
+	c.data = nil
+
+	return c.file.Close()
+}
+
+// 从空闲区域中分配length个字节，使用首次适应算法，调用方需持有锁
+func (c *Cache) allocate(length int64) (extent, bool) {
+	for i, free := range c.free {
+		if free.length < length {
+			continue
+		}
+
+		ext := extent{offset: free.offset, length: length}
+		if free.length == length {
+			c.free = append(c.free[:i], c.free[i+1:]...)
+		} else {
+			c.free[i] = extent{offset: free.offset + length, length: free.length - length}
+		}
+
+		return ext, true
+	}
+
+	return extent{}, false
+}
+
+// 释放区域并与相邻的空闲区域合并，调用方需持有锁
+func (c *Cache) release(ext extent) {
+	i := sort.Search(len(c.free), func(i int) bool {
+		return c.free[i].offset > ext.offset
+	})
+
+	c.free = append(c.free, extent{})
+	copy(c.free[i+1:], c.free[i:])
+	c.free[i] = ext
+
+	// 与后一个空闲区域合并
+	if i+1 < len(c.free) && c.free[i].offset+c.free[i].length == c.free[i+1].offset {
+		c.free[i].length += c.free[i+1].length
+		c.free = append(c.free[:i+1], c.free[i+2:]...)
+	}
+
+	// 与前一个空闲区域合并
+	if i > 0 && c.free[i-1].offset+c.free[i-1].length == c.free[i].offset {
+		c.free[i-1].length += c.free[i].length
+		c.free = append(c.free[:i], c.free[i+1:]...)
+	}
+}
+
+// 复制字节切片，避免调用方修改缓存中的数据
+func cloneBytes(b []byte) []byte {
+	c := make([]byte, len(b))
+	copy(c, b)
+
+	return c
+}
@@ -0,0 +1,106 @@
+//go:build unix
+
+package disktier
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestCache_Tiers(t *testing.T) {
+	c, err := Open(filepath.Join(t.TempDir(), "cache.data"), 1<<10, 1<<10, WithThreshold(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	small := []byte("small")
+	large := bytes.Repeat([]byte("x"), 100)
+	c.Add("small", small)
+	c.Add("large", large)
+
+	if memory, disk := c.Len(); memory != 1 || disk != 1 {
+		t.Fatalf("expected 1 entry in each tier but got %d, %d", memory, disk)
+	}
+	if v, ok := c.Get("small"); !ok || !bytes.Equal(v, small) {
+		t.Fatalf("cache hit small failed")
+	}
+	if v, ok := c.Get("large"); !ok || !bytes.Equal(v, large) {
+		t.Fatalf("cache hit large failed")
+	}
+
+	// 修改为小value后从磁盘上移除
+	c.Add("large", small)
+	if memory, disk := c.Len(); memory != 2 || disk != 0 {
+		t.Fatalf("expected 2 entries in memory but got %d, %d", memory, disk)
+	}
+	if len(c.free) != 1 || c.free[0].length != 1<<10 {
+		t.Fatalf("expected released disk space but got %v", c.free)
+	}
+}
+
+func TestCache_DiskEviction(t *testing.T) {
+	c, err := Open(filepath.Join(t.TempDir(), "cache.data"), 1<<10, 256, WithThreshold(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// 磁盘空间只能容纳两个value，加入第三个时淘汰最久未访问的value
+	for _, key := range []string{"k1", "k2"} {
+		if err := c.Add(key, bytes.Repeat([]byte(key), 50)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.Get("k1")
+	if err := c.Add("k3", bytes.Repeat([]byte("k3"), 60)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.Get("k2"); ok {
+		t.Fatalf("k2 should be evicted")
+	}
+	for _, key := range []string{"k1", "k3"} {
+		if v, ok := c.Get(key); !ok || v[0] != key[0] || v[1] != key[1] {
+			t.Fatalf("cache hit %s failed", key)
+		}
+	}
+
+	if err := c.Add("huge", make([]byte, 512)); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge but got %v", err)
+	}
+}
+
+func TestCache_Release(t *testing.T) {
+	c := &Cache{free: []extent{}}
+
+	// 释放的区域与相邻的空闲区域合并
+	c.release(extent{offset: 0, length: 10})
+	c.release(extent{offset: 20, length: 10})
+	c.release(extent{offset: 10, length: 10})
+
+	if len(c.free) != 1 || c.free[0] != (extent{offset: 0, length: 30}) {
+		t.Fatalf("expected a single merged extent but got %v", c.free)
+	}
+}
+
+func TestCache_KeyTooLarge(t *testing.T) {
+	c, err := Open(filepath.Join(t.TempDir(), "cache.data"), 1<<10, 32, WithThreshold(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// 加上key后超过磁盘文件的大小时返回ErrTooLarge，不占用磁盘空间
+	if err := c.Add("long-key-name", make([]byte, 24)); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge but got %v", err)
+	}
+	if _, disk := c.Len(); disk != 0 || len(c.free) != 1 || c.free[0].length != 32 {
+		t.Fatalf("expected no disk space used but got %d entries, %v", disk, c.free)
+	}
+	if err := c.Add("k", make([]byte, 31)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package disktier
+
+import (
+	"errors"
+	"os"
+)
+
+// 当前平台不支持内存映射
+func mmap(f *os.File, size int) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// 当前平台不支持内存映射
+func munmap(data []byte) error {
+	return errors.ErrUnsupported
+}
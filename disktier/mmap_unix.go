@@ -0,0 +1,18 @@
+//go:build unix
+
+package disktier
+
+import (
+	"os"
+	"syscall"
+)
+
+// 将文件的前size个字节映射到内存中，写入映射区域的数据会同步到文件
+func mmap(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+}
+
+// 解除内存映射
+func munmap(data []byte) error {
+	return syscall.Munmap(data)
+}
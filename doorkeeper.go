@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"sync/atomic"
+
+	"cache/bloom"
+)
+
+// 开启准入门卫：从数据源加载的key第一次出现时只记录在布隆过滤器中，不放入本地缓存，
+// 再次加载时才放入，避免只访问一次的key淘汰已被证明是热点的条目。
+// 布隆过滤器记录n个key后被清空，因此只有在相近的时间内被再次访问的key才会被缓存
+func WithDoorkeeper(n int) Option {
+	return func(g *Group) {
+		g.doorkeeper = &doorkeeper{filter: bloom.New(n, 0.01), limit: int64(max(n, 1))}
+	}
+}
+
+// 准入门卫，记录最近出现过的key
+type doorkeeper struct {
+	// 最近出现过的key
+	filter *bloom.Filter
+
+	// 自上次清空以来记录的新key数量
+	additions atomic.Int64
+
+	// 记录的新key数量达到该值时清空布隆过滤器
+	limit int64
+}
+
+// 记录key，返回key在此之前是否出现过
+func (d *doorkeeper) admit(key string) bool {
+	if d.filter.Add(key) {
+		return true
+	}
+
+	if d.additions.Add(1) >= d.limit {
+		d.additions.Store(0)
+		d.filter.Reset()
+	}
+
+	return false
+}
+
+// 判断从数据源加载的key是否可以放入本地缓存，刷新已缓存的key时总是允许
+func (g *Group) admit(key string) bool {
+	return g.doorkeeper == nil || g.mainCache.Contains(key) || g.doorkeeper.admit(key)
+}
@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGroup_WithDoorkeeper(t *testing.T) {
+	ctx := context.Background()
+	loads := 0
+	g := NewGroup("scores-doorkeeper", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		loads++
+		return []byte(db[key]), nil
+	}), WithDoorkeeper(2))
+
+	// 第一次加载时只记录key，第二次加载时才放入缓存
+	for i := 0; i < 3; i++ {
+		if view, err := g.Get(ctx, "Tom"); err != nil || view.String() != "630" {
+			t.Fatalf("failed to get Tom: %s, %v", view, err)
+		}
+	}
+	if loads != 2 {
+		t.Fatalf("expected 2 loads but got %d", loads)
+	}
+
+	// 记录的新key数量达到上限后布隆过滤器被清空
+	g.Get(ctx, "Jack")
+	g.Get(ctx, "Sam")
+	g.Get(ctx, "Jack")
+	if _, ok := g.Peek("Jack"); ok {
+		t.Fatalf("Jack should not be admitted after the doorkeeper is reset")
+	}
+}
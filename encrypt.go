@@ -0,0 +1,15 @@
+package cache
+
+// 加密器，在数据放入缓存时加密，取出时解密，encrypt.Keyring实现了该接口
+type Sealer interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(sealed []byte) ([]byte, error)
+}
+
+// 开启加密：数据使用s加密后放入缓存，使内存转储中的数据不是明文。
+// 同时开启压缩时先压缩再加密，缓存容量按加密后的长度计算
+func WithEncryption(s Sealer) Option {
+	return func(g *Group) {
+		g.sealer = s
+	}
+}
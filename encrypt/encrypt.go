@@ -0,0 +1,113 @@
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// 密文的格式错误或无法通过认证
+var ErrInvalidCiphertext = errors.New("encrypt: invalid ciphertext")
+
+// 密钥环，使用AES-GCM加密数据。密文以4字节的密钥ID开头，轮换密钥后仍可解密使用旧密钥加密的数据。
+// 可安全地被多个goroutine并发使用
+type Keyring struct {
+	// 保护以下字段的读写锁
+	mu sync.RWMutex
+
+	// 加密新数据使用的密钥ID
+	current uint32
+
+	// 存储密钥ID与AEAD映射关系的哈希表
+	keys map[uint32]cipher.AEAD
+}
+
+// 使用ID为id的密钥实例化密钥环，key的长度必须为16、24或32字节，分别对应AES-128、AES-192与AES-256
+func New(id uint32, key []byte) (*Keyring, error) {
+	k := &Keyring{keys: make(map[uint32]cipher.AEAD)}
+	if err := k.Rotate(id, key); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// 添加ID为id的密钥并用它加密之后的数据，旧密钥保留用于解密
+func (k *Keyring) Rotate(id uint32, key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys[id] = aead
+	k.current = id
+
+	return nil
+}
+
+// 移除ID为id的旧密钥，之后无法再解密使用它加密的数据，不能移除当前密钥
+func (k *Keyring) Remove(id uint32) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if id == k.current {
+		return fmt.Errorf("encrypt: cannot remove current key %d", id)
+	}
+	delete(k.keys, id)
+
+	return nil
+}
+
+// 使用当前密钥加密plaintext，返回密钥ID、随机nonce与密文拼接而成的字节
+func (k *Keyring) Seal(plaintext []byte) ([]byte, error) {
+	k.mu.RLock()
+	id, aead := k.current, k.keys[k.current]
+	k.mu.RUnlock()
+
+	out := make([]byte, 4+aead.NonceSize(), 4+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	binary.BigEndian.PutUint32(out, id)
+	nonce := out[4:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(out, nonce, plaintext, out[:4]), nil
+}
+
+// 解密Seal返回的字节，密钥ID未知或认证失败时返回错误
+func (k *Keyring) Open(sealed []byte) ([]byte, error) {
+	if len(sealed) < 4 {
+		return nil, ErrInvalidCiphertext
+	}
+	id := binary.BigEndian.Uint32(sealed)
+
+	k.mu.RLock()
+	aead, ok := k.keys[id]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("encrypt: unknown key %d", id)
+	}
+
+	if len(sealed) < 4+aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, ciphertext := sealed[4:4+aead.NonceSize()], sealed[4+aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, sealed[:4])
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	return plaintext, nil
+}
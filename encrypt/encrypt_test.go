@@ -0,0 +1,52 @@
+package encrypt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestKeyring_Rotate(t *testing.T) {
+	k, err := New(1, bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old, err := k.Seal([]byte("secret"))
+	if err != nil || bytes.Contains(old, []byte("secret")) {
+		t.Fatalf("expected ciphertext without plaintext, %v", err)
+	}
+
+	if err := k.Rotate(2, bytes.Repeat([]byte{2}, 16)); err != nil {
+		t.Fatal(err)
+	}
+	sealed, _ := k.Seal([]byte("secret"))
+
+	for _, b := range [][]byte{old, sealed} {
+		if plaintext, err := k.Open(b); err != nil || string(plaintext) != "secret" {
+			t.Fatalf("expected secret but got %s, %v", plaintext, err)
+		}
+	}
+
+	// 移除旧密钥后无法再解密旧数据，当前密钥不能被移除
+	if err := k.Remove(2); err == nil {
+		t.Fatalf("expected error when removing current key")
+	}
+	k.Remove(1)
+	if _, err := k.Open(old); err == nil {
+		t.Fatalf("expected error for removed key")
+	}
+}
+
+func TestKeyring_Tampered(t *testing.T) {
+	k, _ := New(1, bytes.Repeat([]byte{1}, 32))
+	sealed, _ := k.Seal([]byte("secret"))
+	sealed[len(sealed)-1] ^= 1
+
+	if _, err := k.Open(sealed); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Fatalf("expected ErrInvalidCiphertext but got %v", err)
+	}
+	if _, err := k.Open([]byte{0, 0}); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Fatalf("expected ErrInvalidCiphertext but got %v", err)
+	}
+}
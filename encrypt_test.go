@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"cache/compress"
+	"cache/encrypt"
+)
+
+func TestGroup_WithEncryption(t *testing.T) {
+	ctx := context.Background()
+	keyring, err := encrypt.New(1, bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fragment := bytes.Repeat([]byte("<div>secret</div>"), 100)
+	g := NewGroup("scores-encryption", 4<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return fragment, nil
+	}), WithCompression(64, compress.Flate{}), WithEncryption(keyring))
+
+	if view, err := g.Get(ctx, "fragment"); err != nil || !bytes.Equal(view.ByteSlice(), fragment) {
+		t.Fatalf("expected decrypted fragment but got %d bytes, %v", view.Len(), err)
+	}
+
+	cached, _ := g.mainCache.Peek("fragment")
+	if v := cached.(*cacheValue); !v.compressed || bytes.Contains(v.data.b, []byte("secret")) {
+		t.Fatalf("expected value to be compressed and encrypted in memory")
+	}
+
+	// 轮换密钥后仍可读取使用旧密钥加密的数据
+	keyring.Rotate(2, bytes.Repeat([]byte{2}, 32))
+	if view, ok := g.Peek("fragment"); !ok || view.Len() != len(fragment) {
+		t.Fatalf("expected value encrypted with old key to be readable")
+	}
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"cache/lru"
+)
+
+// 导出的条目，合法的UTF-8数据以字符串形式写入value，其他数据以base64形式写入bytes
+type exportedEntry struct {
+	Key    string     `json:"key"`
+	Value  string     `json:"value,omitempty"`
+	Bytes  []byte     `json:"bytes,omitempty"`
+	Expire *time.Time `json:"expire,omitempty"`
+}
+
+// 以JSON格式按key排序导出本地缓存中未过期的条目，不包括热点缓存，可用于在环境之间迁移数据或编写golden测试
+func (g *Group) ExportJSON(w io.Writer) error {
+	entries := make([]exportedEntry, 0, g.mainCache.Len())
+	g.mainCache.Range(func(key string, value lru.Value) bool {
+		view, ok := g.viewOf(value.(*cacheValue))
+		if !ok {
+			return true
+		}
+
+		e := exportedEntry{Key: key}
+		if utf8.Valid(view.b) {
+			e.Value = string(view.b)
+		} else {
+			e.Bytes = view.b
+		}
+		entries = append(entries, e)
+
+		return true
+	})
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	for i := range entries {
+		if info, ok := g.mainCache.EntryInfo(entries[i].Key); ok && info.TTL > 0 {
+			expire := g.now().Add(info.TTL).Round(time.Millisecond)
+			entries[i].Expire = &expire
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(entries)
+}
+
+// 从ExportJSON导出的数据恢复条目到本地缓存，已过期的条目被跳过，返回恢复的条目数量。
+// 没有过期时间的条目按WithTTL等选项的设置加入
+func (g *Group) ImportJSON(r io.Reader) (int, error) {
+	var entries []exportedEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return 0, err
+	}
+
+	now := g.now()
+	n := 0
+	for _, e := range entries {
+		value := NewByteView(e.Bytes)
+		if e.Bytes == nil {
+			value = ByteView{b: []byte(e.Value)}
+		}
+
+		var ttl time.Duration
+		if e.Expire != nil {
+			if ttl = e.Expire.Sub(now); ttl <= 0 {
+				continue
+			}
+		}
+		g.forget(e.Key)
+		g.populateWithTTL(e.Key, value, ttl)
+		n++
+	}
+
+	return n, nil
+}
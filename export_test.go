@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGroup_ExportJSON(t *testing.T) {
+	ctx := context.Background()
+	g := NewGroup("scores-export", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		if key == "binary" {
+			return []byte{0xff, 0x00}, nil
+		}
+		return []byte(db[key]), nil
+	}))
+	g.Get(ctx, "Tom")
+	g.Get(ctx, "Jack")
+	g.Get(ctx, "binary")
+
+	var buf bytes.Buffer
+	if err := g.ExportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := `[
+  {
+    "key": "Jack",
+    "value": "589"
+  },
+  {
+    "key": "Tom",
+    "value": "630"
+  },
+  {
+    "key": "binary",
+    "bytes": "/wA="
+  }
+]
+`
+	if buf.String() != golden {
+		t.Fatalf("unexpected export %s", buf.String())
+	}
+
+	restored := NewGroup("scores-import", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		t.Fatalf("unexpected load of %s", key)
+		return nil, nil
+	}))
+	if n, err := restored.ImportJSON(&buf); err != nil || n != 3 {
+		t.Fatalf("expected 3 imported entries but got %d, %v", n, err)
+	}
+	if view, err := restored.Get(ctx, "binary"); err != nil || !bytes.Equal(view.ByteSlice(), []byte{0xff, 0x00}) {
+		t.Fatalf("unexpected imported value %v, %v", view.ByteSlice(), err)
+	}
+}
+
+func TestGroup_ExportJSONWithTTL(t *testing.T) {
+	ctx := context.Background()
+	g := NewGroup("scores-export-ttl", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(db[key]), nil
+	}), WithTTL(time.Hour))
+	g.Get(ctx, "Tom")
+
+	var buf bytes.Buffer
+	g.ExportJSON(&buf)
+	if !bytes.Contains(buf.Bytes(), []byte(`"expire"`)) {
+		t.Fatalf("expected expire in export %s", buf.String())
+	}
+
+	restored := NewGroup("scores-import-ttl", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return nil, ErrNotFound
+	}))
+	if n, err := restored.ImportJSON(&buf); err != nil || n != 1 {
+		t.Fatalf("expected 1 imported entry but got %d, %v", n, err)
+	}
+	if info, ok := restored.mainCache.EntryInfo("Tom"); !ok || info.TTL < 59*time.Minute {
+		t.Fatalf("expected imported TTL close to 1h but got %+v", info)
+	}
+}
@@ -0,0 +1,135 @@
+package fifo
+
+import (
+	"sync"
+
+	"cache/linkedlist"
+	"cache/lru"
+)
+
+// 与lru包共用Value接口，便于在不同淘汰策略之间切换
+type Value = lru.Value
+
+// 链表节点
+type entry struct {
+	key   string
+	value Value
+}
+
+// FIFO cache，按加入顺序淘汰条目，查找不会调整链表，可安全地被多个goroutine并发使用
+type Cache struct {
+	// 读写锁，Get只需持有读锁
+	mu sync.RWMutex
+
+	// 缓存的最大容量（单位为字节）
+	capacity int64
+
+	// 已使用的缓存空间（单位为字节）
+	size int64
+
+	// 按加入顺序排列的双向链表，越靠前越是最近加入的
+	queue *linkedlist.List[entry]
+
+	// 存储key与链表节点映射关系的哈希表
+	cache map[string]*linkedlist.Element[entry]
+
+	// 可选的回调函数，在发生缓存条目清除时被执行
+	// 回调在持有锁的情况下执行，因此不能在回调中再调用Cache的方法
+	OnEvicted func(key string, value Value)
+}
+
+// 实例化FIFO cache
+func New(capacity int64, onEvicted func(string, Value)) *Cache {
+	return &Cache{
+		capacity:  capacity,
+		queue:     linkedlist.New[entry](),
+		cache:     make(map[string]*linkedlist.Element[entry]),
+		OnEvicted: onEvicted,
+	}
+}
+
+// 实现查找功能
+func (c *Cache) Get(key string) (value Value, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if element, ok := c.cache[key]; ok {
+		return element.Value.value, true
+	}
+
+	return
+}
+
+// 实现新增与修改功能，修改不会改变条目的淘汰顺序
+func (c *Cache) Add(key string, value Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.cache[key]; ok {
+		keyValue := &element.Value
+
+		// 更新缓存大小与键值对
+		c.size = c.size - int64(keyValue.value.Len()) + int64(value.Len())
+		keyValue.value = value
+	} else {
+		c.cache[key] = c.queue.PushFront(entry{key: key, value: value})
+		c.size += int64(len(key)) + int64(value.Len())
+	}
+
+	// 如果缓存大小大于缓存容量，则持续移除最早加入的节点
+	for c.capacity != 0 && c.capacity < c.size {
+		c.removeOldest()
+	}
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.cache[key]; ok {
+		c.removeElement(element)
+		return true
+	}
+
+	return false
+}
+
+// 实现缓存淘汰功能
+func (c *Cache) RemoveOldest() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeOldest()
+}
+
+// 获取缓存的条目数量
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.queue.Len()
+}
+
+// 移除最早加入的节点，调用方需持有锁
+func (c *Cache) removeOldest() {
+	if oldest := c.queue.Back(); oldest != nil {
+		c.removeElement(oldest)
+	}
+}
+
+// 从链表与哈希表中删除节点并调用回调函数，调用方需持有锁
+func (c *Cache) removeElement(element *linkedlist.Element[entry]) {
+	c.queue.Remove(element)
+
+	keyValue := &element.Value
+	delete(c.cache, keyValue.key)
+
+	// 更新缓存大小
+	c.size -= int64(len(keyValue.key)) + int64(keyValue.value.Len())
+
+	// 调用回调函数
+	if c.OnEvicted != nil {
+		c.OnEvicted(keyValue.key, keyValue.value)
+	}
+}
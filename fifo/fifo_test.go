@@ -0,0 +1,73 @@
+package fifo
+
+import (
+	"reflect"
+	"testing"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+func TestCache_Get(t *testing.T) {
+	fifo := New(int64(0), nil)
+	fifo.Add("peng", String("chang"))
+	if v, ok := fifo.Get("peng"); !ok || string(v.(String)) != "chang" {
+		t.Fatalf("cache hit failed")
+	}
+
+	if _, ok := fifo.Get("liu"); ok {
+		t.Fatalf("cache miss failed")
+	}
+}
+
+func TestCache_RemoveOldest(t *testing.T) {
+	k1, k2, k3 := "key1", "key2", "k3"
+	v1, v2, v3 := "value1", "value2", "v3"
+	cap := len(k1 + k2 + v1 + v2)
+	fifo := New(int64(cap), nil)
+	fifo.Add(k1, String(v1))
+	fifo.Add(k2, String(v2))
+
+	// 访问不会改变淘汰顺序，key1仍然最先被淘汰
+	fifo.Get(k1)
+	fifo.Add(k3, String(v3))
+
+	if _, ok := fifo.Get(k1); ok || fifo.Len() != 2 {
+		t.Fatalf("RemoveOldest key1 failed")
+	}
+}
+
+func TestOnEvicted(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	fifo := New(int64(10), callback)
+	fifo.Add("key1", String("123456"))
+	fifo.Add("k2", String("k2"))
+	fifo.Add("k3", String("k3"))
+	fifo.Add("k4", String("k4"))
+
+	expect := []string{"key1", "k2"}
+
+	if !reflect.DeepEqual(expect, keys) {
+		t.Fatalf("Call OnEvicted failed, expect keys equals to %s but got %s", expect, keys)
+	}
+}
+
+func TestCache_Remove(t *testing.T) {
+	fifo := New(int64(0), nil)
+	fifo.Add("key1", String("value1"))
+	fifo.Add("key2", String("value2"))
+
+	if !fifo.Remove("key1") || fifo.Remove("key1") {
+		t.Fatalf("Remove key1 failed")
+	}
+	if fifo.Len() != 1 || fifo.size != int64(len("key2")+len("value2")) {
+		t.Fatalf("expected 1 entry of 10 bytes")
+	}
+}
@@ -0,0 +1,169 @@
+package generic
+
+import "sync"
+
+// 链表节点，使用类型参数直接保存key与value，避免接口装箱
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+
+	// 条目占用的开销
+	cost int64
+
+	// 前驱与后继节点
+	prev, next *entry[K, V]
+}
+
+// 泛型LRU cache，key可以是任意可比较的类型，可安全地被多个goroutine并发使用
+type Cache[K comparable, V any] struct {
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 缓存的最大容量（单位与开销函数一致）
+	capacity int64
+
+	// 已使用的容量
+	size int64
+
+	// 计算条目开销的函数
+	cost func(key K, value V) int64
+
+	// 双向链表的哨兵节点，root.next为最近访问的节点，root.prev为最近最少访问的节点
+	root entry[K, V]
+
+	// 存储key与链表节点映射关系的哈希表
+	cache map[K]*entry[K, V]
+
+	// 可选的回调函数，在发生缓存条目清除时被执行
+	// 回调在持有锁的情况下执行，因此不能在回调中再调用Cache的方法
+	OnEvicted func(key K, value V)
+}
+
+// 实例化泛型LRU cache，cost用于计算每个条目的开销，为nil时每个条目的开销为1，即capacity为最大条目数量
+func New[K comparable, V any](capacity int64, cost func(K, V) int64, onEvicted func(K, V)) *Cache[K, V] {
+	if cost == nil {
+		cost = func(K, V) int64 { return 1 }
+	}
+
+	c := &Cache[K, V]{
+		capacity:  capacity,
+		cost:      cost,
+		cache:     make(map[K]*entry[K, V]),
+		OnEvicted: onEvicted,
+	}
+	c.root.prev = &c.root
+	c.root.next = &c.root
+
+	return c
+}
+
+// 实现查找功能
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.cache[key]; ok {
+		c.moveToFront(e)
+		return e.value, true
+	}
+
+	return
+}
+
+// 实现新增与修改功能
+func (c *Cache[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cost := c.cost(key, value)
+
+	if e, ok := c.cache[key]; ok {
+		c.moveToFront(e)
+		c.size += cost - e.cost
+		e.value = value
+		e.cost = cost
+	} else {
+		e := &entry[K, V]{key: key, value: value, cost: cost}
+		c.insertFront(e)
+		c.cache[key] = e
+		c.size += cost
+	}
+
+	// 如果缓存大小大于缓存容量，则持续移除最近最少访问的节点
+	for c.capacity != 0 && c.capacity < c.size {
+		c.removeOldest()
+	}
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.cache[key]; ok {
+		c.removeEntry(e)
+		return true
+	}
+
+	return false
+}
+
+// 实现缓存淘汰功能
+func (c *Cache[K, V]) RemoveOldest() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeOldest()
+}
+
+// 获取缓存的条目数量
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.cache)
+}
+
+// 移除最近最少访问的节点，调用方需持有锁
+func (c *Cache[K, V]) removeOldest() {
+	if oldest := c.root.prev; oldest != &c.root {
+		c.removeEntry(oldest)
+	}
+}
+
+// 从链表与哈希表中删除节点并调用回调函数，调用方需持有锁
+func (c *Cache[K, V]) removeEntry(e *entry[K, V]) {
+	c.unlink(e)
+	delete(c.cache, e.key)
+	c.size -= e.cost
+
+	if c.OnEvicted != nil {
+		c.OnEvicted(e.key, e.value)
+	}
+}
+
+// 将节点插入到链表最前面
+func (c *Cache[K, V]) insertFront(e *entry[K, V]) {
+	e.prev = &c.root
+	e.next = c.root.next
+	c.root.next.prev = e
+	c.root.next = e
+}
+
+// 将节点从链表中摘除
+func (c *Cache[K, V]) unlink(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = nil
+	e.next = nil
+}
+
+// 将节点移动到链表最前面
+func (c *Cache[K, V]) moveToFront(e *entry[K, V]) {
+	if c.root.next == e {
+		return
+	}
+
+	c.unlink(e)
+	c.insertFront(e)
+}
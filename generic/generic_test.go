@@ -0,0 +1,69 @@
+package generic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCache_Get(t *testing.T) {
+	lru := New[int, string](int64(0), nil, nil)
+	lru.Add(1, "chang")
+	if v, ok := lru.Get(1); !ok || v != "chang" {
+		t.Fatalf("cache hit failed")
+	}
+
+	if _, ok := lru.Get(2); ok {
+		t.Fatalf("cache miss failed")
+	}
+}
+
+func TestCache_RemoveOldest(t *testing.T) {
+	// 未指定开销函数时，capacity为最大条目数量
+	lru := New[int, int](int64(2), nil, nil)
+	lru.Add(1, 1)
+	lru.Add(2, 2)
+	lru.Get(1)
+	lru.Add(3, 3)
+
+	if _, ok := lru.Get(2); ok || lru.Len() != 2 {
+		t.Fatalf("RemoveOldest 2 failed")
+	}
+}
+
+func TestOnEvicted(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value []byte) {
+		keys = append(keys, key)
+	}
+	cost := func(key string, value []byte) int64 {
+		return int64(len(key) + len(value))
+	}
+	lru := New(int64(10), cost, callback)
+	lru.Add("key1", []byte("123456"))
+	lru.Add("k2", []byte("k2"))
+	lru.Add("k3", []byte("k3"))
+	lru.Add("k4", []byte("k4"))
+
+	expect := []string{"key1", "k2"}
+
+	if !reflect.DeepEqual(expect, keys) {
+		t.Fatalf("Call OnEvicted failed, expect keys equals to %s but got %s", expect, keys)
+	}
+}
+
+func TestCache_Add(t *testing.T) {
+	cost := func(key string, value string) int64 {
+		return int64(len(key) + len(value))
+	}
+	lru := New(int64(0), cost, nil)
+	lru.Add("key", "1")
+	lru.Add("key", "111")
+
+	if lru.size != int64(len("key")+len("111")) {
+		t.Fatal("expected 6 but got", lru.size)
+	}
+
+	if !lru.Remove("key") || lru.Remove("key") || lru.size != 0 {
+		t.Fatalf("Remove key failed")
+	}
+}
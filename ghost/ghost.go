@@ -0,0 +1,80 @@
+package ghost
+
+import (
+	"sync"
+
+	"cache/linkedlist"
+)
+
+// 最近被淘汰的key的有界列表，只保存key不保存value。超过容量时丢弃最早加入的key。
+// 缓存未命中时若key仍在列表中则称为幽灵命中，说明更大的容量本可以命中该key。
+// 可安全地被多个goroutine并发使用
+type List struct {
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 最多保存的key数量
+	capacity int
+
+	// 按加入顺序排列的双向链表，越靠前越是最近加入的
+	order *linkedlist.List[string]
+
+	// 存储key与链表节点映射关系的哈希表
+	index map[string]*linkedlist.Element[string]
+}
+
+// 实例化幽灵列表，capacity为最多保存的key数量
+func New(capacity int) *List {
+	return &List{
+		capacity: max(capacity, 1),
+		order:    linkedlist.New[string](),
+		index:    make(map[string]*linkedlist.Element[string]),
+	}
+}
+
+// 记录被淘汰的key，已存在的key移动到最前面
+func (l *List) Add(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.index[key]; ok {
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.index[key] = l.order.PushFront(key)
+	for l.order.Len() > l.capacity {
+		delete(l.index, l.order.Remove(l.order.Back()))
+	}
+}
+
+// 判断key是否最近被淘汰过
+func (l *List) Contains(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, ok := l.index[key]
+	return ok
+}
+
+// 删除key，返回key是否存在于列表中，key重新进入缓存时调用
+func (l *List) Remove(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.index[key]
+	if ok {
+		l.order.Remove(element)
+		delete(l.index, key)
+	}
+
+	return ok
+}
+
+// 获取列表中的key数量
+func (l *List) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
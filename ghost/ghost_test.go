@@ -0,0 +1,20 @@
+package ghost
+
+import "testing"
+
+func TestList(t *testing.T) {
+	l := New(2)
+	l.Add("k1")
+	l.Add("k2")
+	l.Add("k1")
+
+	// 超过容量时丢弃最早加入的key，重复加入的key视为最近加入
+	l.Add("k3")
+	if l.Contains("k2") || !l.Contains("k1") || !l.Contains("k3") || l.Len() != 2 {
+		t.Fatalf("expected k2 to be dropped")
+	}
+
+	if !l.Remove("k1") || l.Remove("k1") || l.Contains("k1") || l.Len() != 1 {
+		t.Fatalf("Remove k1 failed")
+	}
+}
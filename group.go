@@ -0,0 +1,612 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"cache/breaker"
+	"cache/cachepb"
+	"cache/hotkeys"
+	"cache/lru"
+	"cache/quota"
+	"cache/singleflight"
+	"cache/timesource"
+)
+
+// 数据源，缓存未命中时从中加载key对应的数据
+type Getter interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// 实现Getter接口的函数类型
+type GetterFunc func(ctx context.Context, key string) ([]byte, error)
+
+// 实现Getter接口
+func (f GetterFunc) Get(ctx context.Context, key string) ([]byte, error) {
+	return f(ctx, key)
+}
+
+// 缓存中存储的value
+type cacheValue struct {
+	data ByteView
+
+	// 开始在后台提前刷新的时间，零值表示不提前刷新
+	refreshAt time.Time
+
+	// 过期时间，之后在宽限期内仍可返回但会被标记为已过期，零值表示没有宽限期
+	expireAt time.Time
+
+	// data是否为压缩后的数据
+	compressed bool
+}
+
+// 获取数据的长度，压缩时为压缩后的长度
+func (v *cacheValue) Len() int {
+	return v.data.Len()
+}
+
+// 缓存的命名空间，负责在缓存未命中时从数据源或远程节点加载数据
+type Group struct {
+	// 命名空间的名称
+	name string
+
+	// 数据源
+	getter Getter
+
+	// 缓存属于本节点的数据
+	mainCache *lru.Cache
+
+	// 缓存从远程节点获取的热点数据，避免每次都发起请求
+	hotCache *lru.Cache
+
+	// 选择远程节点的PeerPicker，未注册时所有数据都从数据源加载
+	peers PeerPicker
+
+	// 保证同一个key同一时刻只加载一次
+	loader *singleflight.Group
+
+	// 保证同一个key同一时刻只从数据源加载一次，本节点与其他节点的请求共用。
+	// 与loader分开，避免节点之间的哈希环不一致时两个节点互相等待对方的转发
+	localLoader *singleflight.Group
+
+	// 本地缓存中条目的存活时间，为0时表示永不过期
+	ttl time.Duration
+
+	// 条目的存活时间经过该比例后开始在后台提前刷新，为0时表示不提前刷新
+	refreshAhead float64
+
+	// 条目过期后仍可返回的宽限期，为0时表示过期后立即重新加载
+	staleGrace time.Duration
+
+	// 保护refreshing的互斥锁
+	refreshMu sync.Mutex
+
+	// 正在后台刷新的key
+	refreshing map[string]struct{}
+
+	// 数据源返回ErrNotFound的结果的存活时间，为0时表示不缓存这类结果
+	negativeTTL time.Duration
+
+	// 缓存数据源返回ErrNotFound的结果，未开启负缓存时为nil
+	negCache *lru.Cache
+
+	// 开启写穿透时的持久化存储，为nil时表示未开启写穿透
+	store Store
+
+	// 写回队列，为nil时表示未开启写回
+	writeBehind *writeBehind
+
+	// 统计热点key，为nil时表示未开启热点统计
+	hotKeys *hotkeys.Tracker
+
+	// 热点key复制到的节点数量，小于等于1时表示未开启热点复制
+	replicas int
+
+	// 窗口内访问次数不小于该值的key被视为需要复制的热点key
+	replicaThreshold uint64
+
+	// 压缩数据的压缩器，为nil时表示未开启压缩
+	compressor Compressor
+
+	// 长度不小于该值的数据才会被压缩
+	compressThreshold int
+
+	// 加密数据的Sealer，为nil时表示未开启加密
+	sealer Sealer
+
+	// 判断过期与刷新时使用的时钟，为nil时使用系统时间
+	clock timesource.Clock
+
+	// 本地缓存中每个条目额外计入的固定开销（单位为字节）
+	entryOverhead int64
+
+	// 本地缓存中单个条目的最大字节数，为0时表示只受cacheBytes限制
+	maxValueBytes int64
+
+	// 本地缓存共用的配额，为nil时表示只受cacheBytes限制
+	quota *quota.Quota
+
+	// 从远程节点获取数据的超时与重试配置，为nil时表示不设置超时也不重试
+	peerFetch *PeerFetch
+
+	// 每个远程节点的熔断器，为nil时表示未开启熔断
+	circuits *circuits
+
+	// 准入门卫，为nil时表示所有从数据源加载的key都直接放入本地缓存
+	doorkeeper *doorkeeper
+}
+
+var (
+	// 保护groups的读写锁
+	mu sync.RWMutex
+
+	// 存储名称与命名空间映射关系的哈希表
+	groups = make(map[string]*Group)
+)
+
+// 实例化命名空间，cacheBytes为缓存的容量（单位为字节），其中八分之一用于热点缓存
+func NewGroup(name string, cacheBytes int64, getter Getter, opts ...Option) *Group {
+	if getter == nil {
+		panic("nil Getter")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	g := &Group{
+		name:        name,
+		getter:      getter,
+		loader:      &singleflight.Group{},
+		localLoader: &singleflight.Group{},
+		refreshing:  make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	cacheOpts := []lru.Option{lru.WithClock(groupClock{g}), lru.WithEntryOverhead(g.entryOverhead), lru.WithMaxValueBytes(g.maxValueBytes), lru.WithQuota(g.quota)}
+	g.mainCache = lru.New(cacheBytes-cacheBytes/8, nil, cacheOpts...)
+	g.hotCache = lru.New(cacheBytes/8, nil, cacheOpts...)
+	if g.negativeTTL > 0 {
+		g.negCache = lru.New(cacheBytes/8, nil, cacheOpts...)
+	}
+	if g.writeBehind != nil {
+		go g.writeBehind.run()
+	}
+	groups[name] = g
+
+	return g
+}
+
+// 获取名称为name的命名空间，不存在时返回nil
+func GetGroup(name string) *Group {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return groups[name]
+}
+
+// 按名称排序获取所有命名空间的名称
+func GroupNames() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// 获取命名空间的名称
+func (g *Group) Name() string {
+	return g.name
+}
+
+// 注册选择远程节点的PeerPicker，只能调用一次
+func (g *Group) RegisterPeers(peers PeerPicker) {
+	if g.peers != nil {
+		panic("RegisterPeers called more than once")
+	}
+
+	g.peers = peers
+}
+
+// 获取key对应的数据，依次查找本地缓存、热点缓存，未命中时从key所属的节点或数据源加载。
+// ctx会传递给远程节点的请求与数据源，并发加载同一个key时使用第一个调用方的ctx
+func (g *Group) Get(ctx context.Context, key string) (ByteView, error) {
+	value, _, err := g.GetStale(ctx, key)
+	return value, err
+}
+
+// 与Get相同，开启WithStaleWhileRevalidate时stale表示返回的是已过期但仍在宽限期内的数据
+func (g *Group) GetStale(ctx context.Context, key string) (value ByteView, stale bool, err error) {
+	if key == "" {
+		return ByteView{}, false, fmt.Errorf("key is required")
+	}
+
+	if g.hotKeys != nil {
+		g.hotKeys.Record(key)
+	}
+
+	if err := g.lookupMissing(key); err != nil {
+		return ByteView{}, false, err
+	}
+
+	if value, stale, ok := g.lookupCache(key); ok {
+		return value, stale, nil
+	}
+
+	value, err = g.load(ctx, key)
+	return value, false, err
+}
+
+// 处理来自其他节点的请求：只查找本地缓存或从数据源加载，不会再转发给其他节点，
+// 避免节点之间的哈希环不一致时请求在节点之间循环转发
+func (g *Group) GetLocal(ctx context.Context, key string) (ByteView, error) {
+	if key == "" {
+		return ByteView{}, fmt.Errorf("key is required")
+	}
+
+	if err := g.lookupMissing(key); err != nil {
+		return ByteView{}, err
+	}
+
+	if value, _, ok := g.lookupMain(key); ok {
+		return value, nil
+	}
+
+	return g.loadLocally(ctx, key)
+}
+
+// 与GetLocal相同，返回发给其他节点的响应。响应带有数据在本地缓存中剩余的存活时间，使其他节点热点缓存中的副本同时过期；
+// 数据没有被放入本地缓存或已过期时带有FlagNoCache标记，其他节点也不应缓存。
+// 数据源返回的错误使用ErrPeerGetter包装，超时与取消等其他错误原样返回
+func (g *Group) GetLocalResponse(ctx context.Context, key string) (*cachepb.Response, error) {
+	value, err := g.GetLocal(ctx, key)
+	if err != nil && key != "" && fromGetter(ctx, err) {
+		return nil, fmt.Errorf("%w: %w", ErrPeerGetter, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := &cachepb.Response{Value: value.ByteSlice()}
+	ttl, ok := g.remainingTTL(key)
+	switch {
+	case !ok:
+		out.Flags |= cachepb.FlagNoCache
+	case ttl > 0:
+		out.TtlMs = max(ttl.Milliseconds(), 1)
+	}
+
+	return out, nil
+}
+
+// 判断GetLocal返回的错误是否来自数据源，key不存在与ctx结束导致的错误不算在内
+func fromGetter(ctx context.Context, err error) bool {
+	return !errors.Is(err, ErrNotFound) && !errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil
+}
+
+// 获取key在本地缓存中剩余的存活时间，开启宽限期时不包括宽限期，为0时表示永不过期。
+// key不在本地缓存中或已过期时ok为false
+func (g *Group) remainingTTL(key string) (time.Duration, bool) {
+	info, ok := g.mainCache.EntryInfo(key)
+	if !ok {
+		return 0, false
+	}
+
+	cached, ok := g.mainCache.Peek(key)
+	if !ok {
+		return 0, false
+	}
+	if expireAt := cached.(*cacheValue).expireAt; !expireAt.IsZero() {
+		ttl := expireAt.Sub(g.now())
+		return ttl, ttl > 0
+	}
+
+	return info.TTL, true
+}
+
+// 批量获取keys对应的数据，属于同一个远程节点的key在节点支持时通过一次请求获取
+func (g *Group) GetMulti(ctx context.Context, keys []string) (map[string]ByteView, error) {
+	values := make(map[string]ByteView, len(keys))
+	batches := make(map[MultiPeerGetter][]string)
+
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("key is required")
+		}
+
+		if g.hotKeys != nil {
+			g.hotKeys.Record(key)
+		}
+
+		if err := g.lookupMissing(key); err != nil {
+			return nil, err
+		}
+
+		if value, _, ok := g.lookupCache(key); ok {
+			values[key] = value
+			continue
+		}
+
+		if peer, ok := g.pickPeer(key); ok {
+			if multi, ok := peer.(MultiPeerGetter); ok {
+				batches[multi] = append(batches[multi], key)
+				continue
+			}
+		}
+
+		value, err := g.load(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+
+	for peer, keys := range batches {
+		if err := g.getMultiFromPeer(ctx, peer, keys, values); err != nil {
+			if !errors.Is(err, breaker.ErrOpen) {
+				log.Println("[Cache] Failed to get from peer", err)
+			}
+
+			// 批量请求失败时逐个加载
+			for _, key := range keys {
+				value, err := g.load(ctx, key)
+				if err != nil {
+					return nil, err
+				}
+				values[key] = value
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// 查找本地缓存与热点缓存，stale表示命中的是已过期但仍在宽限期内的数据
+func (g *Group) lookupCache(key string) (value ByteView, stale, ok bool) {
+	if value, stale, ok := g.lookupMain(key); ok {
+		return value, stale, true
+	}
+
+	if cached, ok := g.hotCache.Get(key); ok {
+		if value, ok := g.viewOf(cached.(*cacheValue)); ok {
+			return value, false, true
+		}
+	}
+
+	return ByteView{}, false, false
+}
+
+// 查找本地缓存，命中的条目需要提前刷新或已过期时在后台重新加载
+func (g *Group) lookupMain(key string) (value ByteView, stale, ok bool) {
+	cached, ok := g.mainCache.Get(key)
+	if !ok {
+		return ByteView{}, false, false
+	}
+
+	v := cached.(*cacheValue)
+	value, ok = g.viewOf(v)
+	if !ok {
+		return ByteView{}, false, false
+	}
+
+	now := g.now()
+	stale = !v.expireAt.IsZero() && now.After(v.expireAt)
+	if stale || !v.refreshAt.IsZero() && now.After(v.refreshAt) {
+		g.refresh(key)
+	}
+
+	return value, stale, true
+}
+
+// 从key所属的节点或数据源加载数据，同一个key的并发请求只会加载一次
+func (g *Group) load(ctx context.Context, key string) (ByteView, error) {
+	value, err := g.loader.Do(key, func() (interface{}, error) {
+		if peer, ok := g.pickPeer(key); ok {
+			value, err := g.getFromPeer(ctx, peer, key)
+			if err == nil {
+				return value, nil
+			}
+
+			// 所属节点确认key不存在时不再从数据源加载，开启负缓存时在本节点缓存该结果
+			if errors.Is(err, ErrNotFound) {
+				g.populateMissing(key, err)
+				return nil, err
+			}
+
+			// 熔断器打开时直接从数据源加载，不再记录日志
+			if !errors.Is(err, breaker.ErrOpen) {
+				log.Println("[Cache] Failed to get from peer", err)
+			}
+		}
+
+		return g.loadLocally(ctx, key)
+	})
+	if err != nil {
+		return ByteView{}, err
+	}
+
+	return value.(ByteView), nil
+}
+
+// 选择加载key的远程节点，选中本节点时ok为false。开启热点复制时，热点key在其所属的前几个节点中随机选择，
+// 选中的节点从数据源加载后各自缓存一份，使热点key的请求分散到多个节点上
+func (g *Group) pickPeer(key string) (PeerGetter, bool) {
+	if g.peers == nil {
+		return nil, false
+	}
+
+	if g.replicas > 1 && g.hotKeys != nil && g.hotKeys.Count(key) >= g.replicaThreshold {
+		if picker, ok := g.peers.(ReplicaPicker); ok {
+			return picker.PickReplica(key, g.replicas)
+		}
+	}
+
+	return g.peers.PickPeer(key)
+}
+
+// 从数据源加载key，本节点与其他节点对同一个key的请求共用一次加载，因此多个节点同时未命中时，
+// 所属节点只访问一次数据源并将结果返回给所有节点。数据源使用第一个调用方ctx的截止时间，
+// 加载不会因为某一个调用方取消而中断，所有调用方都取消后才会取消，调用方取消后立即返回
+func (g *Group) loadLocally(ctx context.Context, key string) (ByteView, error) {
+	value, err := g.localLoader.DoContext(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return g.getLocally(ctx, key)
+	})
+	if err != nil {
+		return ByteView{}, err
+	}
+
+	return value.(ByteView), nil
+}
+
+// 从数据源加载数据并放入本地缓存
+func (g *Group) getLocally(ctx context.Context, key string) (ByteView, error) {
+	b, err := g.getter.Get(ctx, key)
+	if err != nil {
+		g.populateMissing(key, err)
+		return ByteView{}, err
+	}
+
+	value := NewByteView(b)
+	if g.admit(key) {
+		g.populateMain(key, value)
+	}
+
+	return value, nil
+}
+
+// 将从数据源加载的数据放入本地缓存
+func (g *Group) populateMain(key string, value ByteView) {
+	v, ok := g.newCacheValue(value)
+	if !ok {
+		return
+	}
+
+	if g.ttl <= 0 {
+		g.mainCache.Add(key, v)
+		return
+	}
+
+	now := g.now()
+	if g.refreshAhead > 0 {
+		v.refreshAt = now.Add(time.Duration(float64(g.ttl) * g.refreshAhead))
+	}
+	if g.staleGrace > 0 {
+		v.expireAt = now.Add(g.ttl)
+	}
+	g.mainCache.AddWithTTL(key, v, g.ttl+g.staleGrace)
+}
+
+// 将从远程节点获取的数据放入热点缓存
+func (g *Group) populateHot(key string, value ByteView, ttl time.Duration) {
+	if v, ok := g.newCacheValue(value); ok {
+		g.hotCache.AddWithTTL(key, v, ttl)
+	}
+}
+
+// 创建放入缓存的条目，开启压缩且数据足够大时存储压缩后的数据，开启加密时存储加密后的数据。
+// 加密失败时返回false，数据不会被放入缓存
+func (g *Group) newCacheValue(value ByteView) (*cacheValue, bool) {
+	v := &cacheValue{data: value}
+	if g.compressor != nil && value.Len() >= g.compressThreshold {
+		compressed, err := g.compressor.Compress(value.b)
+		if err != nil {
+			log.Println("[Cache] Failed to compress", err)
+		} else if len(compressed) < value.Len() {
+			// 压缩后没有变小的数据按原样存储
+			v.data, v.compressed = ByteView{b: compressed}, true
+		}
+	}
+
+	if g.sealer != nil {
+		sealed, err := g.sealer.Seal(v.data.b)
+		if err != nil {
+			log.Println("[Cache] Failed to encrypt", err)
+			return nil, false
+		}
+		v.data = ByteView{b: sealed}
+	}
+
+	return v, true
+}
+
+// 获取条目中的数据，依次解密与解压，失败时视为未命中
+func (g *Group) viewOf(v *cacheValue) (ByteView, bool) {
+	data := v.data.b
+	if g.sealer != nil {
+		var err error
+		if data, err = g.sealer.Open(data); err != nil {
+			log.Println("[Cache] Failed to decrypt", err)
+			return ByteView{}, false
+		}
+	}
+
+	if v.compressed {
+		var err error
+		if data, err = g.compressor.Decompress(data); err != nil {
+			log.Println("[Cache] Failed to decompress", err)
+			return ByteView{}, false
+		}
+	}
+
+	return ByteView{b: data}, true
+}
+
+// 从远程节点获取数据并放入热点缓存，响应带有FlagNoCache标记时不放入
+func (g *Group) getFromPeer(ctx context.Context, peer PeerGetter, key string) (ByteView, error) {
+	in, out := &cachepb.Request{Group: g.name, Key: key}, &cachepb.Response{}
+	err := g.callPeer(ctx, peer, func(ctx context.Context) error {
+		*out = cachepb.Response{}
+		return peer.Get(ctx, in, out)
+	})
+	if err != nil {
+		return ByteView{}, err
+	}
+
+	value := NewByteView(out.Value)
+	if !out.HasFlag(cachepb.FlagNoCache) {
+		g.populateHot(key, value, out.TTL())
+	}
+
+	return value, nil
+}
+
+// 通过一次请求从远程节点获取keys对应的数据，放入热点缓存与values
+func (g *Group) getMultiFromPeer(ctx context.Context, peer MultiPeerGetter, keys []string, values map[string]ByteView) error {
+	in := make([]*cachepb.Request, len(keys))
+	for i, key := range keys {
+		in[i] = &cachepb.Request{Group: g.name, Key: key}
+	}
+
+	var out []*cachepb.Response
+	err := g.callPeer(ctx, peer, func(ctx context.Context) (err error) {
+		out, err = peer.GetMulti(ctx, in)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(out) != len(keys) {
+		return fmt.Errorf("expected %d responses but got %d", len(keys), len(out))
+	}
+
+	for i, key := range keys {
+		value := NewByteView(out[i].Value)
+		if !out[i].HasFlag(cachepb.FlagNoCache) {
+			g.populateHot(key, value, out[i].TTL())
+		}
+		values[key] = value
+	}
+
+	return nil
+}
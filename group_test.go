@@ -0,0 +1,516 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cache/cachepb"
+	"cache/quota"
+	"cache/timesource"
+)
+
+var db = map[string]string{
+	"Tom":  "630",
+	"Jack": "589",
+	"Sam":  "567",
+}
+
+func TestGetter(t *testing.T) {
+	var f Getter = GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(key), nil
+	})
+
+	expect := []byte("key")
+	if v, _ := f.Get(context.Background(), "key"); !reflect.DeepEqual(v, expect) {
+		t.Errorf("callback failed")
+	}
+}
+
+func TestGroup_Get(t *testing.T) {
+	ctx := context.Background()
+	loadCounts := make(map[string]int, len(db))
+	g := NewGroup("scores", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		if v, ok := db[key]; ok {
+			loadCounts[key]++
+			return []byte(v), nil
+		}
+		return nil, fmt.Errorf("%s not exist", key)
+	}))
+
+	for k, v := range db {
+		// 第一次从数据源加载
+		if view, err := g.Get(ctx, k); err != nil || view.String() != v {
+			t.Fatal("failed to get value of Tom")
+		}
+
+		// 第二次命中缓存
+		if _, err := g.Get(ctx, k); err != nil || loadCounts[k] > 1 {
+			t.Fatalf("cache %s miss", k)
+		}
+	}
+
+	if view, err := g.Get(ctx, "unknown"); err == nil {
+		t.Fatalf("the value of unknow should be empty, but %s got", view)
+	}
+
+	// 修改返回的数据不会影响缓存中的数据
+	view, _ := g.Get(ctx, "Tom")
+	view.ByteSlice()[0] = 'x'
+	if view, _ := g.Get(ctx, "Tom"); view.String() != "630" {
+		t.Fatalf("cached value should not be mutated")
+	}
+}
+
+func TestGetGroup(t *testing.T) {
+	groupName := "scores-get-group"
+	NewGroup(groupName, 2<<10, GetterFunc(func(ctx context.Context, key string) (bytes []byte, err error) {
+		return
+	}))
+
+	if group := GetGroup(groupName); group == nil || group.Name() != groupName {
+		t.Fatalf("group %s not exist", groupName)
+	}
+
+	if group := GetGroup(groupName + "111"); group != nil {
+		t.Fatalf("expect nil, but %s got", group.Name())
+	}
+}
+
+// 从内存中的数据获取数据的远程节点，用于测试
+type fakePeer struct {
+	calls int
+	flags uint32
+	err   error
+}
+
+func (p *fakePeer) Get(ctx context.Context, in *cachepb.Request, out *cachepb.Response) error {
+	p.calls++
+	if p.err != nil {
+		return p.err
+	}
+
+	out.Value = []byte("peer:" + in.Group + "/" + in.Key)
+	out.Flags = p.flags
+
+	return nil
+}
+
+// 支持批量获取的远程节点
+type fakeMultiPeer struct {
+	fakePeer
+	batches int
+}
+
+func (p *fakeMultiPeer) GetMulti(ctx context.Context, in []*cachepb.Request) ([]*cachepb.Response, error) {
+	p.batches++
+	out := make([]*cachepb.Response, len(in))
+	for i := range in {
+		out[i] = &cachepb.Response{}
+		if err := p.Get(ctx, in[i], out[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// 将所有key都分配给同一个远程节点
+type fakePicker struct {
+	peer PeerGetter
+}
+
+func (p *fakePicker) PickPeer(key string) (PeerGetter, bool) {
+	return p.peer, true
+}
+
+func TestGroup_GetFromPeer(t *testing.T) {
+	ctx := context.Background()
+	peer := &fakePeer{}
+	g := NewGroup("scores-peer", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("local:" + key), nil
+	}))
+	g.RegisterPeers(&fakePicker{peer: peer})
+
+	// 从远程节点获取的数据放入热点缓存
+	for i := 0; i < 2; i++ {
+		if view, err := g.Get(ctx, "Tom"); err != nil || view.String() != "peer:scores-peer/Tom" {
+			t.Fatalf("failed to get Tom from peer: %s, %v", view, err)
+		}
+	}
+	if peer.calls != 1 {
+		t.Fatalf("expected 1 peer call but got %d", peer.calls)
+	}
+
+	// 远程节点要求不缓存时每次都会发起请求
+	peer.flags = cachepb.FlagNoCache
+	g.Get(ctx, "Jack")
+	g.Get(ctx, "Jack")
+	if peer.calls != 3 {
+		t.Fatalf("expected 3 peer calls but got %d", peer.calls)
+	}
+
+	// 远程节点出错时从数据源加载
+	peer.err = errors.New("unavailable")
+	if view, err := g.Get(ctx, "Sam"); err != nil || view.String() != "local:Sam" {
+		t.Fatalf("expected fallback to local getter but got %s, %v", view, err)
+	}
+
+	// 来自其他节点的请求不会再转发
+	calls := peer.calls
+	if view, err := g.GetLocal(ctx, "Bob"); err != nil || view.String() != "local:Bob" || peer.calls != calls {
+		t.Fatalf("GetLocal should not forward to peers")
+	}
+}
+
+func TestGroup_GetMulti(t *testing.T) {
+	ctx := context.Background()
+	peer := &fakeMultiPeer{}
+	g := NewGroup("scores-multi", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("local:" + key), nil
+	}))
+	g.RegisterPeers(&fakePicker{peer: peer})
+
+	keys := []string{"Tom", "Jack", "Sam"}
+	values, err := g.GetMulti(ctx, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if values[key].String() != "peer:scores-multi/"+key {
+			t.Fatalf("unexpected value %s for %s", values[key], key)
+		}
+	}
+	if peer.batches != 1 || peer.calls != len(keys) {
+		t.Fatalf("expected 1 batch of %d keys but got %d batches, %d calls", len(keys), peer.batches, peer.calls)
+	}
+
+	// 已缓存的key不会再发起请求
+	if _, err := g.GetMulti(ctx, keys); err != nil || peer.batches != 1 {
+		t.Fatalf("expected hot cache hits, batches = %d, error = %v", peer.batches, err)
+	}
+
+	// 批量请求失败时逐个从数据源加载
+	peer.err = errors.New("unavailable")
+	values, err = g.GetMulti(ctx, []string{"Bob"})
+	if err != nil || values["Bob"].String() != "local:Bob" {
+		t.Fatalf("expected fallback to local getter but got %s, %v", values["Bob"], err)
+	}
+}
+
+// 热点key从副本节点获取
+type fakeReplicaPicker struct {
+	owner, replica PeerGetter
+	n              int
+}
+
+func (p *fakeReplicaPicker) PickPeer(key string) (PeerGetter, bool) {
+	return p.owner, true
+}
+
+func (p *fakeReplicaPicker) PickReplica(key string, n int) (PeerGetter, bool) {
+	p.n = n
+	return p.replica, true
+}
+
+func TestGroup_HotKeyReplication(t *testing.T) {
+	ctx := context.Background()
+	owner := &fakePeer{flags: cachepb.FlagNoCache}
+	replica := &fakePeer{flags: cachepb.FlagNoCache}
+	picker := &fakeReplicaPicker{owner: owner, replica: replica}
+	g := NewGroup("scores-replication", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("local:" + key), nil
+	}), WithHotKeys(10, time.Hour), WithHotKeyReplication(3, 3))
+	g.RegisterPeers(picker)
+
+	// 访问次数达到阈值之前从所属节点获取
+	g.Get(ctx, "Tom")
+	g.Get(ctx, "Tom")
+	if owner.calls != 2 || replica.calls != 0 {
+		t.Fatalf("expected 2 owner calls but got %d, %d", owner.calls, replica.calls)
+	}
+
+	g.Get(ctx, "Tom")
+	g.Get(ctx, "Tom")
+	if owner.calls != 2 || replica.calls != 2 || picker.n != 3 {
+		t.Fatalf("expected 2 replica calls among 3 nodes but got %d, %d, %d", owner.calls, replica.calls, picker.n)
+	}
+
+	// 其他key不受影响
+	g.Get(ctx, "Jack")
+	if owner.calls != 3 {
+		t.Fatalf("expected Jack from owner but got %d owner calls", owner.calls)
+	}
+}
+
+func TestGroup_GetAllocs(t *testing.T) {
+	ctx := context.Background()
+	g := NewGroup("scores-allocs", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(db[key]), nil
+	}))
+	g.Get(ctx, "Tom")
+
+	// 命中本地缓存时不分配内存
+	if allocs := testing.AllocsPerRun(100, func() { g.Get(ctx, "Tom") }); allocs != 0 {
+		t.Fatalf("expected Get hit to allocate nothing but got %v allocs", allocs)
+	}
+}
+
+func BenchmarkGroup_Get(b *testing.B) {
+	ctx := context.Background()
+	g := NewGroup("scores-bench", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(db[key]), nil
+	}))
+	g.Get(ctx, "Tom")
+
+	b.ReportAllocs()
+	for b.Loop() {
+		g.Get(ctx, "Tom")
+	}
+}
+
+func TestGroup_WithEntryOverhead(t *testing.T) {
+	ctx := context.Background()
+	g := NewGroup("scores-overhead", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(db[key]), nil
+	}), WithEntryOverhead(100))
+
+	g.Get(ctx, "Tom")
+	if bytes := g.Stats().Main.Bytes; bytes != int64(len("Tom")+len("630")+100) {
+		t.Fatalf("expected entry overhead to be counted but got %d bytes", bytes)
+	}
+}
+
+func TestGroup_WithMaxValueBytes(t *testing.T) {
+	ctx := context.Background()
+	g := NewGroup("scores-max-value", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(key), nil
+	}), WithMaxValueBytes(8))
+
+	// 过大的数据仍然返回给调用方，但不会被缓存
+	huge := strings.Repeat("x", 16)
+	if view, err := g.Get(ctx, huge); err != nil || view.String() != huge {
+		t.Fatalf("Get huge failed: %v", err)
+	}
+	g.Get(ctx, "Tom")
+	if _, ok := g.Peek(huge); ok {
+		t.Fatalf("expected huge value not to be cached")
+	}
+	if _, ok := g.Peek("Tom"); !ok {
+		t.Fatalf("expected Tom to be cached")
+	}
+}
+
+func TestGroup_WithQuota(t *testing.T) {
+	ctx := context.Background()
+	getter := GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(db[key]), nil
+	})
+	pool := quota.NewPool(0)
+	noisy := NewGroup("scores-quota-noisy", 0, getter, WithQuota(pool.NewQuota(6)))
+	quiet := NewGroup("scores-quota-quiet", 0, getter, WithQuota(pool.NewQuota(6)))
+
+	// 超出配额时只淘汰本命名空间的条目
+	noisy.Get(ctx, "Tom")
+	noisy.Get(ctx, "Jack")
+	quiet.Get(ctx, "Tom")
+	if _, ok := noisy.Peek("Tom"); ok {
+		t.Fatalf("expected Tom to be evicted from the noisy group")
+	}
+	if _, ok := quiet.Peek("Tom"); !ok {
+		t.Fatalf("expected Tom to be cached in the quiet group")
+	}
+}
+
+// 直接调用另一个Group的GetLocal的远程节点，模拟其他节点的请求到达所属节点
+type groupPeer struct {
+	g *Group
+}
+
+func (p groupPeer) Get(ctx context.Context, in *cachepb.Request, out *cachepb.Response) error {
+	response, err := p.g.GetLocalResponse(ctx, in.Key)
+	if err != nil {
+		return err
+	}
+	*out = *response
+
+	return nil
+}
+
+func TestGroup_CoalesceAtOwner(t *testing.T) {
+	ctx := context.Background()
+	var loads int32
+	release := make(chan struct{})
+	owner := NewGroup("scores-coalesce-owner", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return []byte(db[key]), nil
+	}))
+
+	// 多个节点同时未命中时，所属节点只访问一次数据源
+	nodes := []*Group{owner}
+	for i := range 3 {
+		node := NewGroup(fmt.Sprintf("scores-coalesce-%d", i), 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+			return nil, errors.New("should not load locally")
+		}))
+		node.RegisterPeers(&fakePicker{peer: groupPeer{owner}})
+		nodes = append(nodes, node)
+	}
+
+	var wg sync.WaitGroup
+	for _, node := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if view, err := node.Get(ctx, "Tom"); err != nil || view.String() != "630" {
+				t.Errorf("Get Tom failed: %v", err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&loads); n != 1 {
+		t.Fatalf("expected 1 load but got %d", n)
+	}
+}
+
+func TestGroup_CoalesceInconsistentRing(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	getter := GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(db[key]), nil
+	})
+
+	// 两个节点都认为key属于对方时，互相转发的请求不会相互等待
+	a := NewGroup("scores-ring-a", 2<<10, getter)
+	b := NewGroup("scores-ring-b", 2<<10, getter)
+	a.RegisterPeers(&fakePicker{peer: groupPeer{b}})
+	b.RegisterPeers(&fakePicker{peer: groupPeer{a}})
+
+	var wg sync.WaitGroup
+	for _, g := range []*Group{a, b} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := g.Get(ctx, "Tom"); err != nil {
+				t.Errorf("Get Tom failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGroup_PeerResponseTTL(t *testing.T) {
+	ctx := context.Background()
+	clock := timesource.NewFake(time.Now())
+	var loads int32
+	owner := NewGroup("scores-peer-ttl-owner", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(fmt.Sprintf("%s-%d", key, atomic.AddInt32(&loads, 1))), nil
+	}), WithTTL(time.Minute), WithClock(clock))
+	node := NewGroup("scores-peer-ttl-node", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return nil, errors.New("should not load locally")
+	}), WithClock(clock))
+	node.RegisterPeers(&fakePicker{peer: groupPeer{owner}})
+
+	if view, _ := node.Get(ctx, "Tom"); view.String() != "Tom-1" {
+		t.Fatalf("expected Tom-1 but got %q", view.String())
+	}
+
+	// 热点缓存中的副本与所属节点的条目同时过期
+	clock.Advance(30 * time.Second)
+	if view, _ := node.Get(ctx, "Tom"); view.String() != "Tom-1" {
+		t.Fatalf("expected Tom-1 from hot cache but got %q", view.String())
+	}
+	clock.Advance(31 * time.Second)
+	if view, _ := node.Get(ctx, "Tom"); view.String() != "Tom-2" {
+		t.Fatalf("expected hot copy to expire with the owner but got %q", view.String())
+	}
+}
+
+func TestGroup_PeerResponseNoCache(t *testing.T) {
+	ctx := context.Background()
+	owner := NewGroup("scores-peer-nocache-owner", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(db[key]), nil
+	}), WithDoorkeeper(100))
+
+	// 所属节点没有缓存的数据带有FlagNoCache标记
+	out := &cachepb.Response{}
+	if err := (groupPeer{owner}).Get(ctx, &cachepb.Request{Key: "Tom"}, out); err != nil || !out.HasFlag(cachepb.FlagNoCache) {
+		t.Fatalf("expected FlagNoCache but got %+v, %v", out, err)
+	}
+	if err := (groupPeer{owner}).Get(ctx, &cachepb.Request{Key: "Tom"}, out); err != nil || out.HasFlag(cachepb.FlagNoCache) {
+		t.Fatalf("expected cached value without FlagNoCache but got %+v, %v", out, err)
+	}
+}
+
+func TestGroup_PeerNotFound(t *testing.T) {
+	ctx := context.Background()
+	peer := &fakePeer{err: fmt.Errorf("%w: Tom", ErrNotFound)}
+	var loads int
+	g := NewGroup("scores-peer-missing", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		loads++
+		return []byte("local:" + key), nil
+	}), WithNegativeTTL(time.Minute))
+	g.RegisterPeers(&fakePicker{peer: peer})
+
+	// 所属节点确认key不存在时不再从数据源加载，结果放入负缓存
+	for i := 0; i < 2; i++ {
+		if _, err := g.Get(ctx, "Tom"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound but got %v", err)
+		}
+	}
+	if loads != 0 || peer.calls != 1 {
+		t.Fatalf("expected 0 loads and 1 peer call but got %d, %d", loads, peer.calls)
+	}
+}
+
+func TestGroup_GetterContext(t *testing.T) {
+	deadlines := make(chan time.Time, 1)
+	g := NewGroup("scores-getter-ctx", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		deadline, _ := ctx.Deadline()
+		deadlines <- deadline
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+
+	// 调用方ctx的截止时间传递给数据源，到期后数据源的ctx结束
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	if _, err := g.Get(ctx, "Tom"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded but got %v", err)
+	}
+	if got := <-deadlines; !got.Equal(want) {
+		t.Fatalf("expected getter deadline %v but got %v", want, got)
+	}
+}
+
+func TestGroup_GetLocalResponseError(t *testing.T) {
+	g := NewGroup("scores-local-response-error", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("db unavailable")
+	}))
+
+	// 只有数据源返回的错误使用ErrPeerGetter包装
+	if _, err := g.GetLocalResponse(context.Background(), "Tom"); !errors.Is(err, ErrPeerGetter) {
+		t.Fatalf("expected ErrPeerGetter but got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.GetLocalResponse(ctx, "Jack"); err == nil || errors.Is(err, ErrPeerGetter) {
+		t.Fatalf("canceled request should not be reported as ErrPeerGetter: %v", err)
+	}
+	if _, err := g.GetLocalResponse(context.Background(), ""); err == nil || errors.Is(err, ErrPeerGetter) {
+		t.Fatalf("empty key should not be reported as ErrPeerGetter: %v", err)
+	}
+}
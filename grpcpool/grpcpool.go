@@ -0,0 +1,372 @@
+package grpcpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"cache"
+	"cache/cachepb"
+	"cache/consistenthash"
+)
+
+// 一致性哈希环中每个节点默认的虚拟节点数量
+const defaultReplicas = 50
+
+// 实例化Pool时的可选配置
+type Option func(p *Pool)
+
+// 设置服务端的传输层凭证，例如使用credentials.NewTLS开启mTLS
+func WithServerCredentials(creds credentials.TransportCredentials) Option {
+	return func(p *Pool) {
+		p.serverOptions = append(p.serverOptions, grpc.Creds(creds))
+	}
+}
+
+// 设置连接其他节点时使用的传输层凭证，默认不加密
+func WithClientCredentials(creds credentials.TransportCredentials) Option {
+	return func(p *Pool) {
+		p.clientCredentials = creds
+	}
+}
+
+// 基于gRPC的节点池，既作为服务端提供本节点的数据，也作为cache.PeerPicker选择远程节点。
+// 与每个远程节点只建立一个连接，所有请求复用该连接
+type Pool struct {
+	// 本节点的地址，例如"10.0.0.1:8008"
+	self string
+
+	// 服务端的配置
+	serverOptions []grpc.ServerOption
+
+	// 连接其他节点时使用的传输层凭证
+	clientCredentials credentials.TransportCredentials
+
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 一致性哈希环，根据key选择节点
+	peers *consistenthash.Map
+
+	// 存储远程节点地址与客户端映射关系的哈希表
+	clients map[string]*client
+
+	// gRPC服务端，调用Serve之后才会创建
+	server *grpc.Server
+}
+
+// 实例化节点池，self为本节点的地址
+func NewPool(self string, opts ...Option) *Pool {
+	p := &Pool{
+		self:              self,
+		clientCredentials: insecure.NewCredentials(),
+		clients:           make(map[string]*client),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+var (
+	_ cache.ReplicaPicker = (*Pool)(nil)
+	_ cache.PeerLister    = (*Pool)(nil)
+)
+
+// 打印带有节点地址的日志
+func (p *Pool) Log(format string, v ...interface{}) {
+	log.Printf("[Server %s] %s", p.self, fmt.Sprintf(format, v...))
+}
+
+// 设置所有节点的地址（包括本节点），会替换之前设置的节点。
+// 仍然存在的节点继续复用已建立的连接，被移除的节点的连接会被关闭。
+// 连接任一新节点失败时关闭本次新建的连接并返回错误，之前设置的节点保持不变
+func (p *Pool) Set(peers ...string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// 先连接所有新节点，全部成功后才替换现有的状态
+	dialed := make(map[string]*client)
+	for _, peer := range peers {
+		if _, ok := p.clients[peer]; ok || peer == p.self || dialed[peer] != nil {
+			continue
+		}
+
+		conn, err := grpc.NewClient(peer,
+			grpc.WithTransportCredentials(p.clientCredentials),
+			grpc.WithDefaultCallOptions(grpc.ForceCodec(codec{})))
+		if err != nil {
+			for _, c := range dialed {
+				c.conn.Close()
+			}
+			return err
+		}
+		dialed[peer] = &client{addr: peer, conn: conn}
+	}
+
+	clients := make(map[string]*client, len(peers))
+	for _, peer := range peers {
+		if _, ok := clients[peer]; ok || peer == p.self {
+			continue
+		}
+
+		if c, ok := p.clients[peer]; ok {
+			clients[peer] = c
+			delete(p.clients, peer)
+		} else {
+			clients[peer] = dialed[peer]
+		}
+	}
+
+	for _, c := range p.clients {
+		c.conn.Close()
+	}
+	p.clients = clients
+	p.peers = consistenthash.New(defaultReplicas, nil)
+	p.peers.Add(peers...)
+
+	return nil
+}
+
+// 实现cache.PeerPicker接口，key属于本节点或没有设置节点时ok为false
+func (p *Pool) PickPeer(key string) (cache.PeerGetter, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.peers == nil {
+		return nil, false
+	}
+
+	if peer := p.peers.Get(key); peer != "" && peer != p.self {
+		p.Log("Pick peer %s", peer)
+		return p.clients[peer], true
+	}
+
+	return nil, false
+}
+
+// 实现cache.PeerLister接口，获取除本节点以外的所有节点
+func (p *Pool) Peers() []cache.PeerGetter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	peers := make([]cache.PeerGetter, 0, len(p.clients))
+	for _, c := range p.clients {
+		peers = append(peers, c)
+	}
+
+	return peers
+}
+
+// 实现cache.ReplicaPicker接口，在key所属的前n个节点中随机选择一个，选中本节点或没有设置节点时ok为false
+func (p *Pool) PickReplica(key string, n int) (cache.PeerGetter, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.peers == nil {
+		return nil, false
+	}
+
+	replicas := p.peers.GetN(key, n)
+	if len(replicas) == 0 {
+		return nil, false
+	}
+
+	if peer := replicas[rand.IntN(len(replicas))]; peer != p.self {
+		p.Log("Pick replica %s", peer)
+		return p.clients[peer], true
+	}
+
+	return nil, false
+}
+
+// 在lis上提供本节点的数据，直到调用Close
+func (p *Pool) Serve(lis net.Listener) error {
+	p.mu.Lock()
+	if p.server == nil {
+		p.server = grpc.NewServer(append(p.serverOptions, grpc.ForceServerCodec(codec{}))...)
+		p.server.RegisterService(&serviceDesc, &server{pool: p})
+	}
+	s := p.server
+	p.mu.Unlock()
+
+	return s.Serve(lis)
+}
+
+// 停止服务端并关闭与所有远程节点的连接
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.server != nil {
+		p.server.Stop()
+	}
+
+	for _, c := range p.clients {
+		c.conn.Close()
+	}
+	p.clients = make(map[string]*client)
+}
+
+// 从本节点加载group中key对应的数据，供其他节点的请求使用，响应带有剩余的存活时间与标记
+func load(ctx context.Context, group, key string) (*cachepb.Response, error) {
+	g := cache.GetGroup(group)
+	if g == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "no such group: %s", group)
+	}
+
+	// NotFound只用于表示key不存在，请求方据此不再从数据源加载，Unknown只用于表示数据源加载失败
+	out, err := g.GetLocalResponse(ctx, key)
+	switch {
+	case err == nil:
+		return out, nil
+	case errors.Is(err, cache.ErrNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, cache.ErrPeerGetter):
+		return nil, status.Error(codes.Unknown, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return nil, status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return nil, status.Error(codes.Canceled, err.Error())
+	default:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+}
+
+// 实现cache.proto中定义的服务
+type server struct {
+	pool *Pool
+}
+
+// 返回本节点中key对应的数据
+func (s *server) Get(ctx context.Context, in *cachepb.Request) (*cachepb.Response, error) {
+	s.pool.Log("Get %s/%s", in.Group, in.Key)
+
+	return load(ctx, in.Group, in.Key)
+}
+
+// 从本节点的缓存中移除key
+func (s *server) Invalidate(ctx context.Context, in *cachepb.Request) (*cachepb.Response, error) {
+	s.pool.Log("Invalidate %s/%s", in.Group, in.Key)
+
+	g := cache.GetGroup(in.Group)
+	if g == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "no such group: %s", in.Group)
+	}
+	g.Remove(in.Key)
+
+	return &cachepb.Response{}, nil
+}
+
+// 依次返回流中每个请求对应的数据，任意一个key加载失败时结束整个流
+func (s *server) GetStream(stream grpc.ServerStream) error {
+	for {
+		in := new(cachepb.Request)
+		if err := stream.RecvMsg(in); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		out, err := load(stream.Context(), in.Group, in.Key)
+		if err != nil {
+			return err
+		}
+
+		if err := stream.SendMsg(out); err != nil {
+			return err
+		}
+	}
+}
+
+// 从远程节点获取数据的客户端
+type client struct {
+	// 远程节点的地址
+	addr string
+
+	conn *grpc.ClientConn
+}
+
+var (
+	_ cache.MultiPeerGetter = (*client)(nil)
+	_ cache.PeerInvalidator = (*client)(nil)
+	_ cache.PeerAddresser   = (*client)(nil)
+)
+
+// 实现cache.PeerAddresser接口
+func (c *client) Addr() string {
+	return c.addr
+}
+
+// 实现cache.PeerGetter接口，从远程节点获取数据
+func (c *client) Get(ctx context.Context, in *cachepb.Request, out *cachepb.Response) error {
+	return fromStatus(c.conn.Invoke(ctx, getMethod, in, out))
+}
+
+// 实现cache.PeerInvalidator接口，通知远程节点移除key
+func (c *client) Invalidate(ctx context.Context, in *cachepb.Request) error {
+	return c.conn.Invoke(ctx, invalidateMethod, in, &cachepb.Response{})
+}
+
+// 实现cache.MultiPeerGetter接口，通过一个流从远程节点获取数据
+func (c *client) GetMulti(ctx context.Context, in []*cachepb.Request) ([]*cachepb.Response, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[0], getStreamMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	// 发送与接收同时进行，避免请求过多时双方的缓冲区被写满
+	sent := make(chan error, 1)
+	go func() {
+		for _, req := range in {
+			if err := stream.SendMsg(req); err != nil {
+				sent <- err
+				return
+			}
+		}
+		sent <- stream.CloseSend()
+	}()
+
+	responses := make([]*cachepb.Response, 0, len(in))
+	for range in {
+		out := new(cachepb.Response)
+		if err := stream.RecvMsg(out); err != nil {
+			return nil, fromStatus(err)
+		}
+		responses = append(responses, out)
+	}
+
+	if err := <-sent; err != nil {
+		return nil, err
+	}
+
+	return responses, nil
+}
+
+// 将所属节点返回的NotFound与Unknown分别转换为cache.ErrNotFound与cache.ErrPeerGetter
+func fromStatus(err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", cache.ErrNotFound, status.Convert(err).Message())
+	case codes.Unknown:
+		return fmt.Errorf("%w: %s", cache.ErrPeerGetter, status.Convert(err).Message())
+	}
+
+	return err
+}
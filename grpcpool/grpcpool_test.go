@@ -0,0 +1,184 @@
+package grpcpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"cache"
+	"cache/cachepb"
+)
+
+var db = map[string]string{
+	"Tom":  "630",
+	"Jack": "589",
+	"Sam":  "567",
+}
+
+// 启动一个节点
+func startPeer(t *testing.T) *Pool {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := NewPool(lis.Addr().String())
+	go pool.Serve(lis)
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// 实例化从db中加载数据的命名空间，返回其数据源的调用次数
+func newGroup(name string, peers cache.PeerPicker) (*cache.Group, *int32) {
+	var loads int32
+	g := cache.NewGroup(name, 2<<10, cache.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		atomic.AddInt32(&loads, 1)
+		if v, ok := db[key]; ok {
+			return []byte(v), nil
+		}
+		return nil, errors.New(key + " not exist")
+	}))
+	g.RegisterPeers(peers)
+
+	return g, &loads
+}
+
+func TestPool_PickPeer(t *testing.T) {
+	ctx := context.Background()
+	a := startPeer(t)
+	b := startPeer(t)
+	for _, pool := range []*Pool{a, b} {
+		if err := pool.Set(a.self, b.self); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g, loads := newGroup("scores-pick-peer", a)
+	for key, value := range db {
+		for i := 0; i < 2; i++ {
+			v, err := g.Get(ctx, key)
+			if err != nil || v.String() != value {
+				t.Fatalf("failed to get value of %s: %v", key, err)
+			}
+		}
+	}
+
+	// 每个key只会被加载一次，属于远程节点的key第二次读取时命中热点缓存
+	if n := atomic.LoadInt32(loads); n != int32(len(db)) {
+		t.Fatalf("expected %d loads but got %d", len(db), n)
+	}
+
+	// 无论key属于哪个节点，加载失败的错误都会返回给调用方
+	if _, err := g.Get(ctx, "Nobody"); err == nil {
+		t.Fatalf("expected an error for unknown key")
+	}
+}
+
+func TestPool_GetMulti(t *testing.T) {
+	ctx := context.Background()
+	a := startPeer(t)
+	b := startPeer(t)
+	for _, pool := range []*Pool{a, b} {
+		if err := pool.Set(a.self, b.self); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g, _ := newGroup("scores-multi", a)
+	keys := []string{"Tom", "Jack", "Sam"}
+	values, err := g.GetMulti(ctx, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if values[key].String() != db[key] {
+			t.Fatalf("expected %s for %s but got %s", db[key], key, values[key])
+		}
+	}
+}
+
+func TestPool_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	a := startPeer(t)
+	b := startPeer(t)
+	for _, pool := range []*Pool{a, b} {
+		if err := pool.Set(a.self, b.self); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 两个节点共用同一个进程，Group同时代表两个节点的缓存
+	g, _ := newGroup("scores-invalidate", a)
+	g.Get(ctx, "Tom")
+
+	if peers := a.Peers(); len(peers) != 1 {
+		t.Fatalf("expected 1 peer but got %d", len(peers))
+	}
+	if err := g.Invalidate(ctx, "Tom"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.Peek("Tom"); ok {
+		t.Fatalf("Tom should be invalidated")
+	}
+
+	if err := a.Peers()[0].(cache.PeerInvalidator).Invalidate(ctx, &cachepb.Request{Group: "unknown", Key: "Tom"}); err == nil {
+		t.Fatalf("expected error for unknown group")
+	}
+}
+
+func TestPool_GetNotFound(t *testing.T) {
+	ctx := context.Background()
+	a := startPeer(t)
+	b := startPeer(t)
+	if err := a.Set(a.self, b.self); err != nil {
+		t.Fatal(err)
+	}
+	cache.NewGroup("scores-missing", 2<<10, cache.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		if key == "Tom" {
+			return nil, fmt.Errorf("%w: %s", cache.ErrNotFound, key)
+		}
+		return nil, errors.New("db unavailable")
+	}))
+
+	// key不存在时请求方得到ErrNotFound，数据源的其他错误为ErrPeerGetter，找不到命名空间不会被当作key不存在
+	peer := a.Peers()[0]
+	if err := peer.Get(ctx, &cachepb.Request{Group: "scores-missing", Key: "Tom"}, &cachepb.Response{}); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound but got %v", err)
+	}
+	if _, err := peer.(cache.MultiPeerGetter).GetMulti(ctx, []*cachepb.Request{{Group: "scores-missing", Key: "Tom"}}); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound from GetMulti but got %v", err)
+	}
+	if err := peer.Get(ctx, &cachepb.Request{Group: "scores-missing", Key: "Jack"}, &cachepb.Response{}); !errors.Is(err, cache.ErrPeerGetter) {
+		t.Fatalf("expected ErrPeerGetter but got %v", err)
+	}
+	if err := peer.Get(ctx, &cachepb.Request{Group: "unknown", Key: "Tom"}, &cachepb.Response{}); err == nil || errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("unknown group should not be reported as ErrNotFound: %v", err)
+	}
+}
+
+func TestLoad_Canceled(t *testing.T) {
+	cache.NewGroup("scores-load-canceled", 2<<10, cache.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+
+	// ctx结束导致的失败使用对应的状态码，不会被当作数据源加载失败
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := load(ctx, "scores-load-canceled", "Tom"); status.Code(err) != codes.Canceled {
+		t.Fatalf("expected Canceled but got %v", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := load(ctx, "scores-load-canceled", "Jack"); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded but got %v", err)
+	}
+}
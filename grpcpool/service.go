@@ -0,0 +1,127 @@
+package grpcpool
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"cache/cachepb"
+)
+
+// cache.proto中定义的方法的完整名称
+const (
+	getMethod        = "/cachepb.Cache/Get"
+	getStreamMethod  = "/cachepb.Cache/GetStream"
+	invalidateMethod = "/cachepb.Cache/Invalidate"
+)
+
+// cache.proto中定义的服务
+type cacheServer interface {
+	Get(ctx context.Context, in *cachepb.Request) (*cachepb.Response, error)
+	GetStream(stream grpc.ServerStream) error
+	Invalidate(ctx context.Context, in *cachepb.Request) (*cachepb.Response, error)
+}
+
+// 与cache.proto中的Cache服务对应的服务描述
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: "cachepb.Cache",
+	HandlerType: (*cacheServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Get",
+			Handler:    getHandler,
+		},
+		{
+			MethodName: "Invalidate",
+			Handler:    invalidateHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetStream",
+			Handler:       getStreamHandler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "cache.proto",
+}
+
+// 处理Get请求
+func getHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(cachepb.Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(cacheServer).Get(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: getMethod}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(cacheServer).Get(ctx, req.(*cachepb.Request))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+// 处理Invalidate请求
+func invalidateHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(cachepb.Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(cacheServer).Invalidate(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: invalidateMethod}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(cacheServer).Invalidate(ctx, req.(*cachepb.Request))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+// 处理GetStream请求
+func getStreamHandler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(cacheServer).GetStream(stream)
+}
+
+// cachepb中的消息
+type message interface {
+	Marshal() ([]byte, error)
+	Unmarshal(b []byte) error
+}
+
+// 使用cachepb中手工实现的编解码，编码结果与protoc生成的代码一致，
+// 因此名称仍为proto，其他语言的客户端可以直接使用cache.proto与节点通信
+type codec struct{}
+
+// 实现encoding.Codec接口
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(message)
+	if !ok {
+		return nil, fmt.Errorf("grpcpool: unexpected message type %T", v)
+	}
+
+	return m.Marshal()
+}
+
+// 实现encoding.Codec接口
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(message)
+	if !ok {
+		return fmt.Errorf("grpcpool: unexpected message type %T", v)
+	}
+
+	return m.Unmarshal(data)
+}
+
+// 实现encoding.Codec接口
+func (codec) Name() string {
+	return "proto"
+}
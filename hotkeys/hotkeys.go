@@ -0,0 +1,214 @@
+package hotkeys
+
+import (
+	"container/heap"
+	"hash/maphash"
+	"sort"
+	"sync"
+	"time"
+
+	"cache/sketch"
+	"cache/timesource"
+)
+
+// count-min sketch每行的计数器数量
+const sketchWidth = 1 << 12
+
+// key及其在滑动窗口内的估算访问次数
+type KeyCount struct {
+	Key   string
+	Count uint64
+}
+
+// 统计滑动窗口内访问次数最多的k个key，占用的空间与key的数量无关。
+// 访问次数由count-min sketch估算，可能略大于实际值。可安全地被多个goroutine并发使用
+type Tracker struct {
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 窗口的长度
+	window time.Duration
+
+	// 当前窗口与上一个窗口的计数，估算时取两者之和，近似于长度为一至两个窗口的滑动窗口
+	current, previous *sketch.Sketch
+
+	// 当前窗口的开始时间
+	started time.Time
+
+	// 访问次数最多的k个key组成的最小堆
+	top topHeap
+
+	// 存储key与其在堆中的位置映射关系的哈希表
+	index map[string]*heapItem
+
+	// 记录的key数量上限
+	k int
+
+	// 判断窗口是否结束时使用的时钟
+	clock timesource.Clock
+}
+
+// 实例化Tracker时的可选配置
+type Option func(t *Tracker)
+
+// 使用clock代替系统时间划分窗口，测试中可以传入timesource.Fake推进时间
+func WithClock(clock timesource.Clock) Option {
+	return func(t *Tracker) {
+		t.clock = clock
+	}
+}
+
+// 实例化Tracker，记录每个窗口内访问次数最多的k个key
+func New(k int, window time.Duration, opts ...Option) *Tracker {
+	seed := maphash.MakeSeed()
+
+	t := &Tracker{
+		window:   window,
+		current:  newSketch(seed),
+		previous: newSketch(seed),
+		index:    make(map[string]*heapItem, k),
+		k:        max(k, 1),
+		clock:    timesource.System,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	t.started = t.clock.Now()
+
+	return t
+}
+
+// 记录一次对key的访问
+func (t *Tracker) Record(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rotate(t.clock.Now())
+	t.current.Increment(key)
+	count := t.estimate(key)
+
+	if item, ok := t.index[key]; ok {
+		item.count = count
+		heap.Fix(&t.top, item.index)
+		return
+	}
+
+	if len(t.top) < t.k {
+		item := &heapItem{key: key, count: count}
+		heap.Push(&t.top, item)
+		t.index[key] = item
+		return
+	}
+
+	// 访问次数超过堆中最少的key时替换它
+	if min := t.top[0]; count > min.count {
+		delete(t.index, min.key)
+		min.key, min.count = key, count
+		t.index[key] = min
+		heap.Fix(&t.top, 0)
+	}
+}
+
+// 按访问次数从多到少获取最多n个key
+func (t *Tracker) HottestKeys(n int) []KeyCount {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rotate(t.clock.Now())
+
+	keys := make([]KeyCount, 0, len(t.top))
+	for _, item := range t.top {
+		if item.count > 0 {
+			keys = append(keys, KeyCount{Key: item.key, Count: item.count})
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Count != keys[j].Count {
+			return keys[i].Count > keys[j].Count
+		}
+		return keys[i].Key < keys[j].Key
+	})
+
+	return keys[:min(n, len(keys))]
+}
+
+// 获取key在滑动窗口内的估算访问次数
+func (t *Tracker) Count(key string) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rotate(t.clock.Now())
+
+	return t.estimate(key)
+}
+
+// 当前窗口结束时开始新的窗口，并重新估算堆中key的访问次数，调用方需持有锁
+func (t *Tracker) rotate(now time.Time) {
+	elapsed := now.Sub(t.started)
+	if elapsed < t.window {
+		return
+	}
+
+	t.previous, t.current = t.current, t.previous
+	t.current.Clear()
+	// 超过两个窗口没有访问时上一个窗口的计数也已失效
+	if elapsed >= 2*t.window {
+		t.previous.Clear()
+	}
+	t.started = now
+
+	for _, item := range t.top {
+		item.count = t.estimate(item.key)
+	}
+	heap.Init(&t.top)
+}
+
+// 估算key在滑动窗口内的访问次数，调用方需持有锁
+func (t *Tracker) estimate(key string) uint64 {
+	return uint64(t.current.Estimate(key)) + uint64(t.previous.Estimate(key))
+}
+
+// 堆中的元素
+type heapItem struct {
+	key   string
+	count uint64
+
+	// 在堆中的下标
+	index int
+}
+
+// 按访问次数排序的最小堆，实现heap.Interface接口
+type topHeap []*heapItem
+
+func (h topHeap) Len() int {
+	return len(h)
+}
+
+func (h topHeap) Less(i, j int) bool {
+	return h[i].count < h[j].count
+}
+
+func (h topHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *topHeap) Push(x interface{}) {
+	item := x.(*heapItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *topHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return item
+}
+
+// 实例化32位计数器的count-min sketch，两个窗口使用相同的种子
+func newSketch(seed maphash.Seed) *sketch.Sketch {
+	return sketch.New(sketchWidth, sketch.WithCounterBits(32), sketch.WithSeed(seed))
+}
@@ -0,0 +1,80 @@
+package hotkeys
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"cache/timesource"
+)
+
+func TestTracker_HottestKeys(t *testing.T) {
+	tracker := New(3, time.Hour)
+
+	for i := 0; i < 100; i++ {
+		tracker.Record("cold" + strconv.Itoa(i))
+	}
+	for i := 0; i < 50; i++ {
+		tracker.Record("hot")
+	}
+	for i := 0; i < 20; i++ {
+		tracker.Record("warm")
+	}
+
+	keys := tracker.HottestKeys(2)
+	if len(keys) != 2 || keys[0].Key != "hot" || keys[1].Key != "warm" {
+		t.Fatalf("expected hot and warm but got %v", keys)
+	}
+
+	// count-min sketch的估算值不会小于实际值
+	if keys[0].Count < 50 || keys[1].Count < 20 {
+		t.Fatalf("estimated counts should not be less than actual counts: %v", keys)
+	}
+
+	if n := len(tracker.HottestKeys(10)); n != 3 {
+		t.Fatalf("expected at most 3 keys but got %d", n)
+	}
+}
+
+func TestTracker_Window(t *testing.T) {
+	clock := timesource.NewFake(time.Now())
+	tracker := New(2, time.Minute, WithClock(clock))
+
+	for i := 0; i < 10; i++ {
+		tracker.Record("old")
+	}
+
+	// 下一个窗口内仍然计入上一个窗口的访问
+	clock.Advance(75 * time.Second)
+	tracker.Record("new")
+	expect := []KeyCount{{Key: "old", Count: 10}, {Key: "new", Count: 1}}
+	if keys := tracker.HottestKeys(2); !reflect.DeepEqual(expect, keys) {
+		t.Fatalf("expect keys equals to %v but got %v", expect, keys)
+	}
+
+	// 超过两个窗口之后过去的访问不再计入
+	clock.Advance(2 * time.Minute)
+	if keys := tracker.HottestKeys(2); len(keys) != 0 {
+		t.Fatalf("expected no hot keys but got %v", keys)
+	}
+}
+
+func TestTracker_Count(t *testing.T) {
+	tracker := New(1, time.Hour)
+	for i := 0; i < 5; i++ {
+		tracker.Record("hot")
+	}
+	tracker.Record("warm")
+
+	// 不在堆中的key同样可以估算
+	if n := tracker.Count("hot"); n < 5 {
+		t.Fatalf("expected at least 5 but got %d", n)
+	}
+	if n := tracker.Count("warm"); n < 1 {
+		t.Fatalf("expected at least 1 but got %d", n)
+	}
+	if n := tracker.Count("cold"); n != 0 {
+		t.Fatalf("expected 0 but got %d", n)
+	}
+}
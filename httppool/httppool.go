@@ -0,0 +1,324 @@
+package httppool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"cache"
+	"cache/cachepb"
+	"cache/consistenthash"
+)
+
+const (
+	// 节点之间通信的默认路径前缀
+	defaultBasePath = "/_cache/"
+
+	// 一致性哈希环中每个节点默认的虚拟节点数量
+	defaultReplicas = 50
+
+	// 说明错误原因的响应头，用于区分key不存在与找不到命名空间等其他错误
+	errorHeader = "X-Cache-Error"
+
+	// key不存在时errorHeader的值
+	errorNotFound = "not-found"
+
+	// 数据源加载失败时errorHeader的值
+	errorGetter = "getter"
+)
+
+// 基于HTTP的节点池，既作为服务端提供本节点的数据，也作为cache.PeerPicker选择远程节点
+type Pool struct {
+	// 本节点的地址，例如"http://10.0.0.1:8008"
+	self string
+
+	// 节点之间通信的路径前缀
+	basePath string
+
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 一致性哈希环，根据key选择节点
+	peers *consistenthash.Map
+
+	// 存储远程节点地址与客户端映射关系的哈希表
+	httpGetters map[string]*httpGetter
+}
+
+var (
+	_ cache.ReplicaPicker = (*Pool)(nil)
+	_ cache.PeerLister    = (*Pool)(nil)
+)
+
+// 实例化节点池，self为本节点的地址
+func NewPool(self string) *Pool {
+	return &Pool{
+		self:     self,
+		basePath: defaultBasePath,
+	}
+}
+
+// 打印带有节点地址的日志
+func (p *Pool) Log(format string, v ...interface{}) {
+	log.Printf("[Server %s] %s", p.self, fmt.Sprintf(format, v...))
+}
+
+// 设置所有节点的地址（包括本节点），会替换之前设置的节点
+func (p *Pool) Set(peers ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.peers = consistenthash.New(defaultReplicas, nil)
+	p.peers.Add(peers...)
+
+	p.httpGetters = make(map[string]*httpGetter, len(peers))
+	for _, peer := range peers {
+		p.httpGetters[peer] = &httpGetter{baseURL: peer + p.basePath}
+	}
+}
+
+// 实现cache.PeerPicker接口，key属于本节点或没有设置节点时ok为false
+func (p *Pool) PickPeer(key string) (cache.PeerGetter, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.peers == nil {
+		return nil, false
+	}
+
+	if peer := p.peers.Get(key); peer != "" && peer != p.self {
+		p.Log("Pick peer %s", peer)
+		return p.httpGetters[peer], true
+	}
+
+	return nil, false
+}
+
+// 实现cache.PeerLister接口，获取除本节点以外的所有节点
+func (p *Pool) Peers() []cache.PeerGetter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	peers := make([]cache.PeerGetter, 0, len(p.httpGetters))
+	for addr, getter := range p.httpGetters {
+		if addr != p.self {
+			peers = append(peers, getter)
+		}
+	}
+
+	return peers
+}
+
+// 实现cache.ReplicaPicker接口，在key所属的前n个节点中随机选择一个，选中本节点或没有设置节点时ok为false
+func (p *Pool) PickReplica(key string, n int) (cache.PeerGetter, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.peers == nil {
+		return nil, false
+	}
+
+	replicas := p.peers.GetN(key, n)
+	if len(replicas) == 0 {
+		return nil, false
+	}
+
+	if peer := replicas[rand.IntN(len(replicas))]; peer != p.self {
+		p.Log("Pick replica %s", peer)
+		return p.httpGetters[peer], true
+	}
+
+	return nil, false
+}
+
+// 处理其他节点的请求，路径格式为/<basePath>/<group>/<key>，GET只返回本节点的数据，
+// PUT接收其他节点迁移过来的数据，DELETE从本节点的缓存中移除key
+func (p *Pool) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, p.basePath) {
+		http.Error(w, "unexpected path: "+r.URL.Path, http.StatusNotFound)
+		return
+	}
+	p.Log("%s %s", r.Method, r.URL.Path)
+
+	parts := strings.SplitN(r.URL.Path[len(p.basePath):], "/", 2)
+	if len(parts) != 2 {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	group := cache.GetGroup(parts[0])
+	if group == nil {
+		http.Error(w, "no such group: "+parts[0], http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		p.receive(w, r, group, parts[1])
+		return
+	case http.MethodDelete:
+		group.Remove(parts[1])
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	out, err := group.GetLocalResponse(r.Context(), parts[1])
+	if err != nil {
+		writeLoadError(w, err)
+		return
+	}
+
+	body, err := out.Marshal()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Write(body)
+}
+
+// 写入加载数据失败的响应，key不存在或数据源加载失败时在errorHeader中注明原因，使请求方不必再重试，
+// 超时等其他错误不带有errorHeader
+func writeLoadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, cache.ErrNotFound):
+		w.Header().Set(errorHeader, errorNotFound)
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, cache.ErrPeerGetter):
+		w.Header().Set(errorHeader, errorGetter)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// 将请求体中迁移过来的数据放入group
+func (p *Pool) receive(w http.ResponseWriter, r *http.Request, group *cache.Group, key string) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var in cachepb.Response
+	if err := in.Unmarshal(body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	group.Receive(key, in.Value, in.TTL())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 从远程节点获取数据的客户端
+type httpGetter struct {
+	// 远程节点的地址与路径前缀，例如"http://10.0.0.2:8008/_cache/"
+	baseURL string
+}
+
+var (
+	_ cache.PeerPusher      = (*httpGetter)(nil)
+	_ cache.PeerInvalidator = (*httpGetter)(nil)
+	_ cache.PeerAddresser   = (*httpGetter)(nil)
+)
+
+// 实现cache.PeerAddresser接口，返回远程节点的地址与路径前缀
+func (h *httpGetter) Addr() string {
+	return h.baseURL
+}
+
+// 实现cache.PeerGetter接口，从远程节点获取数据
+func (h *httpGetter) Get(ctx context.Context, in *cachepb.Request, out *cachepb.Response) error {
+	u := fmt.Sprintf("%v%v/%v", h.baseURL, url.PathEscape(in.Group), url.PathEscape(in.Key))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound && res.Header.Get(errorHeader) == errorNotFound {
+		return fmt.Errorf("%w: %s/%s", cache.ErrNotFound, in.Group, in.Key)
+	}
+	if res.StatusCode == http.StatusInternalServerError && res.Header.Get(errorHeader) == errorGetter {
+		return fmt.Errorf("%w: %s/%s", cache.ErrPeerGetter, in.Group, in.Key)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned: %v", res.Status)
+	}
+
+	bytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %v", err)
+	}
+
+	if err := out.Unmarshal(bytes); err != nil {
+		return fmt.Errorf("decoding response body: %v", err)
+	}
+
+	return nil
+}
+
+// 实现cache.PeerPusher接口，将迁移的数据推送给远程节点
+func (h *httpGetter) Push(ctx context.Context, in *cachepb.Request, value *cachepb.Response) error {
+	u := fmt.Sprintf("%v%v/%v", h.baseURL, url.PathEscape(in.Group), url.PathEscape(in.Key))
+
+	body, err := value.Marshal()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("server returned: %v", res.Status)
+	}
+
+	return nil
+}
+
+// 实现cache.PeerInvalidator接口，通知远程节点移除key
+func (h *httpGetter) Invalidate(ctx context.Context, in *cachepb.Request) error {
+	u := fmt.Sprintf("%v%v/%v", h.baseURL, url.PathEscape(in.Group), url.PathEscape(in.Key))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("server returned: %v", res.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,254 @@
+package httppool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cache"
+	"cache/cachepb"
+)
+
+var db = map[string]string{
+	"Tom":  "630",
+	"Jack": "589",
+	"Sam":  "567",
+}
+
+// 从db中加载数据的数据源，记录调用次数
+func dbGetter(loads *int32) cache.Getter {
+	return cache.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		atomic.AddInt32(loads, 1)
+		if v, ok := db[key]; ok {
+			return []byte(v), nil
+		}
+		return nil, errors.New(key + " not exist")
+	})
+}
+
+func TestPool_PickPeer(t *testing.T) {
+	ctx := context.Background()
+	// 远程节点直接使用db中的数据响应请求
+	var remoteLoads int32
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&remoteLoads, 1)
+		key := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		body, _ := (&cachepb.Response{Value: []byte(db[key])}).Marshal()
+		w.Write(body)
+	}))
+	defer remote.Close()
+
+	self := "http://self"
+	pool := NewPool(self)
+	pool.Set(self, remote.URL)
+
+	var localLoads int32
+	group := cache.NewGroup("scores-pick-peer", 2<<10, dbGetter(&localLoads))
+	group.RegisterPeers(pool)
+
+	for key, value := range db {
+		for i := 0; i < 2; i++ {
+			if v, err := group.Get(ctx, key); err != nil || v.String() != value {
+				t.Fatalf("failed to get value of %s: %v", key, err)
+			}
+		}
+	}
+
+	// 每个key只会被加载一次，属于远程节点的key第二次读取时命中热点缓存
+	if n := atomic.LoadInt32(&localLoads) + atomic.LoadInt32(&remoteLoads); n != int32(len(db)) {
+		t.Fatalf("expected %d loads but got %d", len(db), n)
+	}
+}
+
+func TestPool_ServeHTTP(t *testing.T) {
+	ctx := context.Background()
+	var loads int32
+	cache.NewGroup("scores-serve", 2<<10, dbGetter(&loads))
+	server := httptest.NewServer(NewPool("http://self"))
+	defer server.Close()
+
+	getter := &httpGetter{baseURL: server.URL + defaultBasePath}
+	out := &cachepb.Response{}
+	if err := getter.Get(ctx, &cachepb.Request{Group: "scores-serve", Key: "Tom"}, out); err != nil || string(out.Value) != "630" {
+		t.Fatalf("failed to get Tom: %s, %v", out.Value, err)
+	}
+
+	for path, status := range map[string]int{
+		"/other/scores-serve/Tom":             http.StatusNotFound,
+		defaultBasePath + "unknown/Tom":       http.StatusNotFound,
+		defaultBasePath + "scores-serve":      http.StatusBadRequest,
+		defaultBasePath + "scores-serve/Nobo": http.StatusInternalServerError,
+	} {
+		res, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != status {
+			t.Fatalf("expected status %d for %s but got %d", status, path, res.StatusCode)
+		}
+	}
+}
+
+func TestHTTPGetter_Protobuf(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := (&cachepb.Response{Value: []byte("630"), TtlMs: 1000, Flags: cachepb.FlagNoCache}).Marshal()
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	out := &cachepb.Response{}
+	err := (&httpGetter{baseURL: server.URL + defaultBasePath}).Get(context.Background(), &cachepb.Request{Group: "scores", Key: "Tom"}, out)
+	if err != nil || string(out.Value) != "630" || out.TTL() != time.Second || !out.HasFlag(cachepb.FlagNoCache) {
+		t.Fatalf("unexpected response %+v, error = %v", out, err)
+	}
+}
+
+func TestHTTPGetter_Push(t *testing.T) {
+	ctx := context.Background()
+	var loads int32
+	g := cache.NewGroup("scores-push", 2<<10, dbGetter(&loads))
+	server := httptest.NewServer(NewPool("http://self"))
+	defer server.Close()
+
+	getter := &httpGetter{baseURL: server.URL + defaultBasePath}
+	in := &cachepb.Request{Group: "scores-push", Key: "Nobody"}
+	if err := getter.Push(ctx, in, &cachepb.Response{Value: []byte("100"), TtlMs: 60000}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 迁移过来的数据直接命中本地缓存，不会从数据源加载
+	if view, err := g.Get(ctx, "Nobody"); err != nil || view.String() != "100" || loads != 0 {
+		t.Fatalf("failed to get pushed value: %s, %v, %d loads", view, err, loads)
+	}
+
+	in.Group = "unknown"
+	if err := getter.Push(ctx, in, &cachepb.Response{}); err == nil {
+		t.Fatalf("expected error for unknown group")
+	}
+}
+
+func TestPool_PickReplica(t *testing.T) {
+	pool := NewPool("http://self")
+	if _, ok := pool.PickReplica("Tom", 2); ok {
+		t.Fatalf("expected no replica without peers")
+	}
+
+	pool.Set("http://self", "http://10.0.0.2:8008", "http://10.0.0.3:8008")
+	owner := pool.peers.Get("Tom")
+	replicas := pool.peers.GetN("Tom", 2)
+	picked := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		peer, ok := pool.PickReplica("Tom", 2)
+		if !ok {
+			picked["http://self"] = true
+			continue
+		}
+		picked[strings.TrimSuffix(peer.(*httpGetter).baseURL, defaultBasePath)] = true
+	}
+
+	// 只会选中前2个节点，且两者都会被选中
+	if len(picked) != 2 || !picked[replicas[0]] || !picked[replicas[1]] || replicas[0] != owner {
+		t.Fatalf("expected replicas %v but picked %v", replicas, picked)
+	}
+}
+
+func TestPool_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	var loads int32
+	remote := cache.NewGroup("scores-invalidate", 2<<10, dbGetter(&loads))
+	server := httptest.NewServer(NewPool("http://remote"))
+	defer server.Close()
+
+	remote.Get(ctx, "Tom")
+	if _, ok := remote.Peek("Tom"); !ok {
+		t.Fatalf("Tom should be cached")
+	}
+
+	pool := NewPool("http://self")
+	pool.Set("http://self", server.URL)
+	if peers := pool.Peers(); len(peers) != 1 {
+		t.Fatalf("expected 1 peer but got %d", len(peers))
+	}
+
+	// 远程节点与本节点使用同名的Group，通知后远程节点的缓存被清除
+	for _, peer := range pool.Peers() {
+		if err := peer.(cache.PeerInvalidator).Invalidate(ctx, &cachepb.Request{Group: "scores-invalidate", Key: "Tom"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := remote.Peek("Tom"); ok {
+		t.Fatalf("Tom should be invalidated")
+	}
+}
+
+func TestPool_ServeHTTPTTL(t *testing.T) {
+	var loads int32
+	cache.NewGroup("scores-serve-ttl", 2<<10, dbGetter(&loads), cache.WithTTL(time.Minute))
+	server := httptest.NewServer(NewPool("http://self"))
+	defer server.Close()
+
+	// 响应带有数据在所属节点中剩余的存活时间
+	out := &cachepb.Response{}
+	err := (&httpGetter{baseURL: server.URL + defaultBasePath}).Get(context.Background(), &cachepb.Request{Group: "scores-serve-ttl", Key: "Tom"}, out)
+	if err != nil || out.TTL() <= 0 || out.TTL() > time.Minute || out.HasFlag(cachepb.FlagNoCache) {
+		t.Fatalf("unexpected response %+v, error = %v", out, err)
+	}
+}
+
+func TestHTTPGetter_NotFound(t *testing.T) {
+	cache.NewGroup("scores-serve-missing", 2<<10, cache.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return nil, fmt.Errorf("%w: %s", cache.ErrNotFound, key)
+	}))
+	server := httptest.NewServer(NewPool("http://self"))
+	defer server.Close()
+
+	// key不存在时请求方得到ErrNotFound，其他错误不会被当作key不存在
+	getter := &httpGetter{baseURL: server.URL + defaultBasePath}
+	err := getter.Get(context.Background(), &cachepb.Request{Group: "scores-serve-missing", Key: "Tom"}, &cachepb.Response{})
+	if !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound but got %v", err)
+	}
+	err = getter.Get(context.Background(), &cachepb.Request{Group: "unknown", Key: "Tom"}, &cachepb.Response{})
+	if err == nil || errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("unknown group should not be reported as ErrNotFound: %v", err)
+	}
+}
+
+func TestHTTPGetter_GetterError(t *testing.T) {
+	var loads int32
+	cache.NewGroup("scores-serve-failed", 2<<10, dbGetter(&loads))
+	server := httptest.NewServer(NewPool("http://self"))
+	defer server.Close()
+
+	// 所属节点的数据源加载失败时请求方得到ErrPeerGetter
+	getter := &httpGetter{baseURL: server.URL + defaultBasePath}
+	err := getter.Get(context.Background(), &cachepb.Request{Group: "scores-serve-failed", Key: "Nobody"}, &cachepb.Response{})
+	if !errors.Is(err, cache.ErrPeerGetter) || errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected ErrPeerGetter but got %v", err)
+	}
+}
+
+func TestPool_ServeHTTPCanceled(t *testing.T) {
+	cache.NewGroup("scores-serve-canceled", 2<<10, cache.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+	pool := NewPool("http://self")
+
+	// 请求方的ctx结束导致的失败不会被标记为数据源加载失败
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := httptest.NewRequest(http.MethodGet, defaultBasePath+"scores-serve-canceled/Tom", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	pool.ServeHTTP(w, r)
+	if w.Code != http.StatusInternalServerError || w.Header().Get(errorHeader) != "" {
+		t.Fatalf("unexpected response %d, %s = %q", w.Code, errorHeader, w.Header().Get(errorHeader))
+	}
+}
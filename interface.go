@@ -0,0 +1,12 @@
+package cache
+
+import "cache/lru"
+
+// 进程内缓存的最小接口，lru.Cache以及lfu、arc、fifo、twoq、tinylfu、sampled、clock、sharded等包中的Cache都实现了该接口。
+// 依赖该接口的代码可以在不同的淘汰策略之间切换，测试中可以替换为cachetest.Fake
+type Cache interface {
+	Get(key string) (lru.Value, bool)
+	Add(key string, value lru.Value)
+	Remove(key string) bool
+	Len() int
+}
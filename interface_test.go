@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"testing"
+
+	"cache/arc"
+	"cache/clock"
+	"cache/fifo"
+	"cache/lfu"
+	"cache/lru"
+	"cache/sampled"
+	"cache/sharded"
+	"cache/tinylfu"
+	"cache/twoq"
+)
+
+func TestCache_Implementations(t *testing.T) {
+	caches := map[string]Cache{
+		"lru":     lru.New(0, nil),
+		"lfu":     lfu.New(0, nil),
+		"arc":     arc.New(0, nil),
+		"fifo":    fifo.New(0, nil),
+		"twoq":    twoq.New(0, nil),
+		"tinylfu": tinylfu.New(0, nil),
+		"sampled": sampled.New(0, nil),
+		"clock":   clock.New(0, nil),
+		"sharded": sharded.New(4, 0, nil),
+	}
+
+	for name, c := range caches {
+		c.Add("Tom", ByteView{b: []byte("630")})
+		if v, ok := c.Get("Tom"); !ok || v.(ByteView).String() != "630" || c.Len() != 1 {
+			t.Fatalf("%s: failed to get Tom", name)
+		}
+		if !c.Remove("Tom") || c.Len() != 0 {
+			t.Fatalf("%s: failed to remove Tom", name)
+		}
+	}
+}
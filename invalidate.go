@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"cache/cachepb"
+)
+
+// 从本节点的所有缓存中移除key，并通知所有远程节点移除各自缓存中的key，
+// 修改数据源之后调用可以立即清除所有节点上的旧数据。
+// PeerPicker需要实现PeerLister，未实现PeerInvalidator的节点会被跳过，返回通知失败的节点的错误
+func (g *Group) Invalidate(ctx context.Context, key string) error {
+	g.Remove(key)
+
+	lister, ok := g.peers.(PeerLister)
+	if !ok {
+		return nil
+	}
+
+	in := &cachepb.Request{Group: g.name, Key: key}
+
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for _, peer := range lister.Peers() {
+		invalidator, ok := peer.(PeerInvalidator)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := invalidator.Invalidate(ctx, in); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("invalidate %s: %w", key, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
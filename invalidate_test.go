@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cache/cachepb"
+)
+
+// 记录被移除的key的远程节点
+type fakeInvalidator struct {
+	fakePeer
+	invalidated []string
+}
+
+func (p *fakeInvalidator) Invalidate(ctx context.Context, in *cachepb.Request) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.invalidated = append(p.invalidated, in.Group+"/"+in.Key)
+
+	return nil
+}
+
+// 所有key都属于本节点，并列出给定的远程节点
+type fakeLister struct {
+	peers []PeerGetter
+}
+
+func (p *fakeLister) PickPeer(key string) (PeerGetter, bool) {
+	return nil, false
+}
+
+func (p *fakeLister) Peers() []PeerGetter {
+	return p.peers
+}
+
+func TestGroup_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	a, b := &fakeInvalidator{}, &fakeInvalidator{}
+	g := NewGroup("scores-invalidate", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("local:" + key), nil
+	}))
+
+	// 未注册节点时只移除本地缓存
+	g.Get(ctx, "Tom")
+	if err := g.Invalidate(ctx, "Tom"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.Peek("Tom"); ok {
+		t.Fatalf("Tom should be removed")
+	}
+
+	g.RegisterPeers(&fakeLister{peers: []PeerGetter{a, b, &fakePeer{}}})
+	g.Get(ctx, "Tom")
+	if err := g.Invalidate(ctx, "Tom"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.Peek("Tom"); ok {
+		t.Fatalf("Tom should be removed")
+	}
+	for _, peer := range []*fakeInvalidator{a, b} {
+		if len(peer.invalidated) != 1 || peer.invalidated[0] != "scores-invalidate/Tom" {
+			t.Fatalf("unexpected invalidated keys %v", peer.invalidated)
+		}
+	}
+
+	b.err = errors.New("unavailable")
+	if err := g.Invalidate(ctx, "Jack"); !errors.Is(err, b.err) || len(a.invalidated) != 2 {
+		t.Fatalf("expected error from b but got %v", err)
+	}
+}
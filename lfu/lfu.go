@@ -0,0 +1,198 @@
+package lfu
+
+import (
+	"sync"
+
+	"cache/linkedlist"
+	"cache/lru"
+)
+
+// 与lru包共用Value接口，便于在两种淘汰策略之间切换
+type Value = lru.Value
+
+// 频次链表中的节点，保存访问次数相同的所有条目
+type frequency struct {
+	// 访问次数
+	count int
+
+	// 访问次数为count的条目，越靠前越是最近访问的
+	entries linkedlist.List[entry]
+}
+
+// 条目链表中的节点
+type entry struct {
+	key   string
+	value Value
+
+	// 条目所属的频次节点
+	frequency *linkedlist.Element[frequency]
+}
+
+// LFU cache，可安全地被多个goroutine并发使用
+type Cache struct {
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 缓存的最大容量（单位为字节）
+	capacity int64
+
+	// 已使用的缓存空间（单位为字节）
+	size int64
+
+	// 按访问次数从小到大排列的频次链表
+	frequencies *linkedlist.List[frequency]
+
+	// 存储key与条目链表节点映射关系的哈希表
+	cache map[string]*linkedlist.Element[entry]
+
+	// 可选的回调函数，在发生缓存条目清除时被执行
+	// 回调在持有锁的情况下执行，因此不能在回调中再调用Cache的方法
+	OnEvicted func(key string, value Value)
+}
+
+// 实例化LFU cache
+func New(capacity int64, onEvicted func(string, Value)) *Cache {
+	return &Cache{
+		capacity:    capacity,
+		frequencies: linkedlist.New[frequency](),
+		cache:       make(map[string]*linkedlist.Element[entry]),
+		OnEvicted:   onEvicted,
+	}
+}
+
+// 实现查找功能
+func (c *Cache) Get(key string) (value Value, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.cache[key]; ok {
+		// 增加条目的访问次数
+		c.increment(element)
+
+		return element.Value.value, true
+	}
+
+	return
+}
+
+// 实现新增与修改功能
+func (c *Cache) Add(key string, value Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.cache[key]; ok {
+		keyValue := &element.Value
+
+		// 更新缓存大小
+		c.size = c.size - int64(keyValue.value.Len()) + int64(value.Len())
+
+		// 更新键值对，修改也视为一次访问
+		keyValue.value = value
+		c.increment(element)
+	} else {
+		// 新条目的访问次数为1，放入频次链表最前面的节点
+		front := c.frequencies.Front()
+		if front == nil || front.Value.count != 1 {
+			front = c.frequencies.PushFront(frequency{count: 1})
+		}
+
+		c.cache[key] = front.Value.entries.PushFront(entry{key: key, value: value, frequency: front})
+
+		// 更新缓存大小
+		c.size += int64(len(key)) + int64(value.Len())
+	}
+
+	// 如果缓存大小大于缓存容量，则持续移除访问次数最少的条目
+	for c.capacity != 0 && c.capacity < c.size {
+		c.removeLeastFrequent()
+	}
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.cache[key]; ok {
+		c.removeElement(element)
+		return true
+	}
+
+	return false
+}
+
+// 实现缓存淘汰功能，访问次数相同时淘汰最久未访问的条目
+func (c *Cache) RemoveLeastFrequent() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeLeastFrequent()
+}
+
+// 获取缓存的条目数量
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.cache)
+}
+
+// 移除访问次数最少的条目，调用方需持有锁
+func (c *Cache) removeLeastFrequent() {
+	front := c.frequencies.Front()
+	if front == nil {
+		return
+	}
+
+	c.removeElement(front.Value.entries.Back())
+}
+
+// 将条目移动到访问次数加一的频次节点，条目节点在频次节点之间移动而不重新分配，调用方需持有锁
+func (c *Cache) increment(element *linkedlist.Element[entry]) {
+	keyValue := &element.Value
+	current := keyValue.frequency
+	count := current.Value.count + 1
+
+	// 下一个频次节点不存在或访问次数不连续时，需要一个新的频次节点
+	next := current.Next()
+	if next == nil || next.Value.count != count {
+		// 条目独占原频次节点时直接复用该节点
+		if current.Value.entries.Len() == 1 {
+			current.Value.count = count
+			return
+		}
+		next = c.frequencies.InsertAfter(frequency{count: count}, current)
+	}
+
+	// 从原频次节点中删除条目，并放入新频次节点的最前面
+	c.unlink(element)
+	keyValue.frequency = next
+	next.Value.entries.PushFrontElement(element)
+}
+
+// 从条目所属的频次节点中删除条目，频次节点为空时一并删除，调用方需持有锁
+func (c *Cache) unlink(element *linkedlist.Element[entry]) {
+	keyValue := &element.Value
+	entries := &keyValue.frequency.Value.entries
+	entries.Remove(element)
+
+	if entries.Len() == 0 {
+		c.frequencies.Remove(keyValue.frequency)
+	}
+}
+
+// 从频次链表与哈希表中删除条目并调用回调函数，调用方需持有锁
+func (c *Cache) removeElement(element *linkedlist.Element[entry]) {
+	c.unlink(element)
+
+	keyValue := &element.Value
+	delete(c.cache, keyValue.key)
+
+	// 更新缓存大小
+	c.size -= int64(len(keyValue.key)) + int64(keyValue.value.Len())
+
+	// 调用回调函数
+	if c.OnEvicted != nil {
+		c.OnEvicted(keyValue.key, keyValue.value)
+	}
+}
@@ -0,0 +1,102 @@
+package lfu
+
+import (
+	"reflect"
+	"testing"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+func TestCache_Get(t *testing.T) {
+	lfu := New(int64(0), nil)
+	lfu.Add("peng", String("chang"))
+	if v, ok := lfu.Get("peng"); !ok || string(v.(String)) != "chang" {
+		t.Fatalf("cache hit failed")
+	}
+
+	if _, ok := lfu.Get("liu"); ok {
+		t.Fatalf("cache miss failed")
+	}
+}
+
+func TestCache_RemoveLeastFrequent(t *testing.T) {
+	k1, k2, k3 := "key1", "key2", "k3"
+	v1, v2, v3 := "value1", "value2", "v3"
+	cap := len(k1 + k2 + v1 + v2)
+	lfu := New(int64(cap), nil)
+	lfu.Add(k1, String(v1))
+	lfu.Add(k2, String(v2))
+
+	// key1被访问过，因此key2的访问次数最少
+	lfu.Get(k1)
+	lfu.Add(k3, String(v3))
+
+	if _, ok := lfu.Get(k2); ok || lfu.Len() != 2 {
+		t.Fatalf("RemoveLeastFrequent key2 failed")
+	}
+	if _, ok := lfu.Get(k1); !ok {
+		t.Fatalf("key1 should still be in cache")
+	}
+}
+
+func TestOnEvicted(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	lfu := New(int64(10), callback)
+	lfu.Add("key1", String("123456"))
+	lfu.Add("k2", String("k2"))
+	lfu.Add("k3", String("k3"))
+	lfu.Add("k4", String("k4"))
+
+	expect := []string{"key1", "k2"}
+
+	if !reflect.DeepEqual(expect, keys) {
+		t.Fatalf("Call OnEvicted failed, expect keys equals to %s but got %s", expect, keys)
+	}
+}
+
+func TestCache_Remove(t *testing.T) {
+	lfu := New(int64(0), nil)
+	lfu.Add("key1", String("value1"))
+	lfu.Add("key2", String("value2"))
+	lfu.Get("key1")
+
+	if !lfu.Remove("key1") || lfu.Remove("key1") {
+		t.Fatalf("Remove key1 failed")
+	}
+	if lfu.Len() != 1 || lfu.size != int64(len("key2")+len("value2")) {
+		t.Fatalf("expected 1 entry of 10 bytes but got %d entries of %d bytes", lfu.Len(), lfu.size)
+	}
+	if lfu.frequencies.Len() != 1 {
+		t.Fatalf("empty frequency node should be removed")
+	}
+}
+
+func TestCache_Add(t *testing.T) {
+	lfu := New(int64(0), nil)
+	lfu.Add("key", String("1"))
+	lfu.Add("key", String("111"))
+
+	if lfu.size != int64(len("key")+len("111")) {
+		t.Fatal("expected 6 but got", lfu.size)
+	}
+}
+
+func TestCache_GetAllocs(t *testing.T) {
+	lfu := New(int64(0), nil)
+	lfu.Add("k1", String("v1"))
+	lfu.Add("k2", String("v2"))
+	lfu.Get("k2")
+
+	// 条目节点在频次节点之间移动而不重新分配
+	if allocs := testing.AllocsPerRun(100, func() { lfu.Get("k1") }); allocs != 0 {
+		t.Fatalf("expected Get hit to allocate nothing but got %v allocs", allocs)
+	}
+}
@@ -0,0 +1,178 @@
+package linkedlist
+
+// 链表节点，Value直接保存在节点中，既避免container/list的接口装箱，
+// 也使条目与前驱后继指针位于同一块内存
+type Element[T any] struct {
+	// 前驱与后继节点，链表为环形，root作为哨兵节点
+	prev, next *Element[T]
+
+	// 节点所属的链表，为nil时表示节点不在任何链表中
+	list *List[T]
+
+	// 节点保存的值
+	Value T
+}
+
+// 获取后继节点，没有后继节点时返回nil
+func (e *Element[T]) Next() *Element[T] {
+	if next := e.next; e.list != nil && next != &e.list.root {
+		return next
+	}
+
+	return nil
+}
+
+// 获取前驱节点，没有前驱节点时返回nil
+func (e *Element[T]) Prev() *Element[T] {
+	if prev := e.prev; e.list != nil && prev != &e.list.root {
+		return prev
+	}
+
+	return nil
+}
+
+// 双向链表，零值为可直接使用的空链表，不能被多个goroutine并发使用
+type List[T any] struct {
+	// 哨兵节点，root.next为头节点，root.prev为尾节点
+	root Element[T]
+
+	// 链表长度，不包括哨兵节点
+	len int
+}
+
+// 实例化空链表
+func New[T any]() *List[T] {
+	return new(List[T]).Init()
+}
+
+// 初始化或清空链表，链表中原有的节点不会被修改
+func (l *List[T]) Init() *List[T] {
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	l.len = 0
+
+	return l
+}
+
+// 获取链表长度
+func (l *List[T]) Len() int {
+	return l.len
+}
+
+// 获取头节点，链表为空时返回nil
+func (l *List[T]) Front() *Element[T] {
+	if l.len == 0 {
+		return nil
+	}
+
+	return l.root.next
+}
+
+// 获取尾节点，链表为空时返回nil
+func (l *List[T]) Back() *Element[T] {
+	if l.len == 0 {
+		return nil
+	}
+
+	return l.root.prev
+}
+
+// 零值链表在第一次插入时初始化
+func (l *List[T]) lazyInit() {
+	if l.root.next == nil {
+		l.Init()
+	}
+}
+
+// 将e插入到at之后
+func (l *List[T]) insert(e, at *Element[T]) *Element[T] {
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+	e.list = l
+	l.len++
+
+	return e
+}
+
+// 将e从链表中摘下，并清空e的前驱、后继与所属链表
+func (l *List[T]) unlink(e *Element[T]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.next = nil
+	e.prev = nil
+	e.list = nil
+	l.len--
+}
+
+// 将e移动到at之后
+func (l *List[T]) move(e, at *Element[T]) {
+	if e == at {
+		return
+	}
+	e.prev.next = e.next
+	e.next.prev = e.prev
+
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+}
+
+// 新建保存v的节点并插入到链表最前面
+func (l *List[T]) PushFront(v T) *Element[T] {
+	l.lazyInit()
+	return l.insert(&Element[T]{Value: v}, &l.root)
+}
+
+// 新建保存v的节点并插入到链表最后面
+func (l *List[T]) PushBack(v T) *Element[T] {
+	l.lazyInit()
+	return l.insert(&Element[T]{Value: v}, l.root.prev)
+}
+
+// 将不在任何链表中的节点插入到链表最前面，用于在链表之间移动节点而不重新分配
+func (l *List[T]) PushFrontElement(e *Element[T]) *Element[T] {
+	if e.list != nil {
+		panic("linkedlist: element is already in a list")
+	}
+	l.lazyInit()
+
+	return l.insert(e, &l.root)
+}
+
+// 新建保存v的节点并插入到mark之后，mark不属于该链表时返回nil
+func (l *List[T]) InsertAfter(v T, mark *Element[T]) *Element[T] {
+	if mark.list != l {
+		return nil
+	}
+
+	return l.insert(&Element[T]{Value: v}, mark)
+}
+
+// 从链表中删除e并返回e.Value，e不属于该链表时不做任何修改。
+// 被删除的节点可以通过PushFrontElement插入其他链表
+func (l *List[T]) Remove(e *Element[T]) T {
+	if e.list == l {
+		l.unlink(e)
+	}
+
+	return e.Value
+}
+
+// 将e移动到链表最前面，e不属于该链表时不做任何修改
+func (l *List[T]) MoveToFront(e *Element[T]) {
+	if e.list != l || l.root.next == e {
+		return
+	}
+	l.move(e, &l.root)
+}
+
+// 将e移动到链表最后面，e不属于该链表时不做任何修改
+func (l *List[T]) MoveToBack(e *Element[T]) {
+	if e.list != l || l.root.prev == e {
+		return
+	}
+	l.move(e, l.root.prev)
+}
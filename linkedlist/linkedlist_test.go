@@ -0,0 +1,84 @@
+package linkedlist
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 从头节点开始获取所有值
+func values[T any](l *List[T]) []T {
+	values := make([]T, 0, l.Len())
+	for e := l.Front(); e != nil; e = e.Next() {
+		values = append(values, e.Value)
+	}
+
+	return values
+}
+
+func TestList(t *testing.T) {
+	var l List[string]
+	if l.Front() != nil || l.Back() != nil || l.Len() != 0 {
+		t.Fatalf("zero value should be an empty list")
+	}
+
+	b := l.PushFront("b")
+	a := l.PushFront("a")
+	c := l.PushBack("c")
+	l.InsertAfter("b2", b)
+	if got := values(&l); !reflect.DeepEqual(got, []string{"a", "b", "b2", "c"}) {
+		t.Fatalf("unexpected values %v", got)
+	}
+	if l.Front() != a || l.Back() != c || a.Prev() != nil || c.Next() != nil {
+		t.Fatalf("unexpected front or back")
+	}
+
+	l.MoveToFront(c)
+	l.MoveToBack(a)
+	if got := values(&l); !reflect.DeepEqual(got, []string{"c", "b", "b2", "a"}) {
+		t.Fatalf("unexpected values after move %v", got)
+	}
+
+	if v := l.Remove(b); v != "b" || l.Len() != 3 {
+		t.Fatalf("expected to remove b but got %s, len %d", v, l.Len())
+	}
+
+	// 不属于该链表的节点不会被修改
+	l.Remove(b)
+	l.MoveToFront(b)
+	if l.Len() != 3 || l.InsertAfter("x", b) != nil {
+		t.Fatalf("element outside the list should be ignored")
+	}
+}
+
+func TestList_PushFrontElement(t *testing.T) {
+	from, to := New[int](), New[int]()
+	e := from.PushFront(1)
+	from.PushFront(2)
+
+	// 节点在链表之间移动时保持同一个地址
+	from.Remove(e)
+	if to.PushFrontElement(e) != e || to.Front() != e || from.Len() != 1 || to.Len() != 1 {
+		t.Fatalf("element should be moved to the other list")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("pushing an element that is already in a list should panic")
+		}
+	}()
+	from.PushFrontElement(e)
+}
+
+func TestList_Allocs(t *testing.T) {
+	l := New[int]()
+	e := l.PushFront(1)
+	l.PushFront(2)
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		l.MoveToFront(e)
+		l.Remove(e)
+		l.PushFrontElement(e)
+	}); allocs != 0 {
+		t.Fatalf("expected moving elements to allocate nothing but got %v allocs", allocs)
+	}
+}
@@ -0,0 +1,136 @@
+package lru
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// 异步回调的队列已满时的处理方式
+type Backpressure int
+
+const (
+	// 阻塞写入直到队列有空位，不会丢失回调，但慢回调会拖慢写入
+	BlockOnFull Backpressure = iota
+
+	// 丢弃新的回调，写入不会被阻塞，丢弃的数量可以通过DroppedEvictions获取
+	DropOnFull
+)
+
+// 开启异步回调：OnEvicted与WithEvictionReason设置的回调不再在持有锁的情况下执行，
+// 而是放入容量为size的队列，由后台goroutine依次执行，慢回调不会拖慢写入。
+// 开启后需调用Stop停止后台goroutine，停止后回调恢复为在持有锁的情况下同步执行
+func WithAsyncEviction(size int, backpressure Backpressure) Option {
+	return func(c *Cache) {
+		c.dispatcher = &dispatcher{
+			queue:        make(chan eviction, max(size, 1)),
+			backpressure: backpressure,
+			stop:         make(chan struct{}),
+			done:         make(chan struct{}),
+		}
+	}
+}
+
+// 等待执行的回调
+type eviction struct {
+	key    string
+	value  Value
+	reason EvictionReason
+
+	// 是否由Clear产生，Clear不调用OnEvicted
+	cleared bool
+}
+
+// 在后台goroutine中执行回调的调度器
+type dispatcher struct {
+	// 等待执行的回调
+	queue chan eviction
+
+	// 队列已满时的处理方式
+	backpressure Backpressure
+
+	// 被丢弃的回调数量
+	dropped atomic.Int64
+
+	// 关闭时通知goroutine退出
+	stop chan struct{}
+
+	// goroutine执行完队列中剩余的回调后关闭
+	done chan struct{}
+
+	// 保证stop只被关闭一次
+	once sync.Once
+}
+
+// 将回调放入队列，调度器已停止时直接执行，调用方需持有锁
+func (d *dispatcher) dispatch(c *Cache, e eviction) {
+	select {
+	case <-d.stop:
+		c.notify(e)
+		return
+	default:
+	}
+
+	if d.backpressure == DropOnFull {
+		select {
+		case d.queue <- e:
+		default:
+			d.dropped.Add(1)
+		}
+		return
+	}
+
+	select {
+	case d.queue <- e:
+	case <-d.stop:
+		c.notify(e)
+	}
+}
+
+// 依次执行队列中的回调，stop被关闭后执行完剩余的回调再退出
+func (d *dispatcher) run(c *Cache) {
+	defer close(d.done)
+
+	for {
+		select {
+		case e := <-d.queue:
+			c.notify(e)
+		case <-d.stop:
+			for {
+				select {
+				case e := <-d.queue:
+					c.notify(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// 开启异步回调时将回调放入队列，否则直接执行，调用方需持有锁
+func (c *Cache) emit(e eviction) {
+	if c.dispatcher != nil {
+		c.dispatcher.dispatch(c, e)
+	} else {
+		c.notify(e)
+	}
+}
+
+// 执行回调
+func (c *Cache) notify(e eviction) {
+	if c.OnEvicted != nil && !e.cleared {
+		c.OnEvicted(e.key, e.value)
+	}
+	if c.onRemoved != nil {
+		c.onRemoved(e.key, e.value, e.reason)
+	}
+}
+
+// 获取开启异步回调且使用DropOnFull时被丢弃的回调数量
+func (c *Cache) DroppedEvictions() int64 {
+	if c.dispatcher == nil {
+		return 0
+	}
+
+	return c.dispatcher.dropped.Load()
+}
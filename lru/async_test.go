@@ -0,0 +1,56 @@
+package lru
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCache_AsyncEviction(t *testing.T) {
+	var mu sync.Mutex
+	keys := make([]string, 0)
+	lru := New(int64(4), func(key string, value Value) {
+		mu.Lock()
+		keys = append(keys, key)
+		mu.Unlock()
+	}, WithAsyncEviction(16, BlockOnFull))
+
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	lru.Add("k3", String("v3"))
+	lru.Stop()
+
+	if !reflect.DeepEqual([]string{"k1", "k2"}, keys) {
+		t.Fatalf("expected k1 and k2 to be evicted but got %s", keys)
+	}
+
+	// 停止后回调同步执行
+	lru.Add("k4", String("v4"))
+	if len(keys) != 3 {
+		t.Fatalf("expected synchronous callback after Stop but got %s", keys)
+	}
+}
+
+func TestCache_AsyncEvictionDrop(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	lru := New(int64(4), func(key string, value Value) {
+		started <- struct{}{}
+		<-release
+	}, WithAsyncEviction(1, DropOnFull))
+
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	<-started
+
+	// 后台goroutine阻塞在k1的回调中，队列只能再容纳一个回调
+	lru.Add("k3", String("v3"))
+	lru.Add("k4", String("v4"))
+	lru.Add("k5", String("v5"))
+	close(release)
+	lru.Stop()
+
+	if n := lru.DroppedEvictions(); n != 2 {
+		t.Fatalf("expected 2 dropped callbacks but got %d", n)
+	}
+}
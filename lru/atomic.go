@@ -0,0 +1,75 @@
+package lru
+
+import "time"
+
+// 查找key对应的value，未命中时在持有锁的情况下调用compute计算value并加入缓存，
+// 因此查找与加入之间不会有其他写入。loaded表示返回的是已存在的value。
+// compute不能调用Cache的方法，耗时较长的加载应使用GetOrLoad
+func (c *Cache) GetOrAdd(key string, compute func() Value) (value Value, loaded bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if keyValue, ok := c.lookup(key, c.now()); ok {
+		c.policy.OnGet(key)
+		c.hit(keyValue, keyValue.value)
+		return keyValue.value, true
+	}
+	c.miss(key)
+
+	value = compute()
+	c.add(key, value, time.Time{})
+
+	return value, false
+}
+
+// 只有key不存在时才加入value并设置其存活时间，ttl小于等于0时表示永不过期，返回是否加入成功。
+// 可用于实现锁与租约
+func (c *Cache) SetIfAbsent(key string, value Value, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, ok := c.lookup(key, now); ok {
+		return false
+	}
+
+	return c.add(key, value, expireAt(now, ttl)) == nil
+}
+
+// 只有key存在时才将其修改为value并设置其存活时间，ttl小于等于0时表示永不过期，返回是否修改成功。
+// 可避免复活已被删除的条目
+func (c *Cache) SetIfPresent(key string, value Value, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, ok := c.lookup(key, now); !ok {
+		return false
+	}
+
+	return c.add(key, value, expireAt(now, ttl)) == nil
+}
+
+// 计算存活时间为ttl的条目的过期时间，ttl小于等于0时返回零值
+func expireAt(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(ttl)
+}
+
+// 查找key对应的未过期条目，已过期的条目被直接删除，不通知淘汰策略也不记录命中，调用方需持有写锁
+func (c *Cache) lookup(key string, now time.Time) (*entry, bool) {
+	keyValue, ok := c.cache[key]
+	if !ok {
+		return nil, false
+	}
+
+	if keyValue.expired(now) {
+		c.removeEntry(keyValue, EvictedExpired)
+		return nil, false
+	}
+
+	return keyValue, true
+}
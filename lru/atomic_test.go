@@ -0,0 +1,73 @@
+package lru
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache_GetOrAdd(t *testing.T) {
+	lru := New(int64(0), nil)
+
+	var computed int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			lru.GetOrAdd("k1", func() Value {
+				mu.Lock()
+				computed++
+				mu.Unlock()
+				return String("v" + strconv.Itoa(i))
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	if computed != 1 {
+		t.Fatalf("expected compute to be called once but got %d", computed)
+	}
+
+	v, loaded := lru.GetOrAdd("k1", func() Value { return String("other") })
+	if !loaded || v == String("other") {
+		t.Fatalf("expected existing value but got %v", v)
+	}
+	if v, loaded := lru.GetOrAdd("k2", func() Value { return String("v2") }); loaded || v != String("v2") {
+		t.Fatalf("expected computed value but got %v", v)
+	}
+}
+
+func TestCache_SetIfAbsent(t *testing.T) {
+	lru := New(int64(0), nil)
+	if !lru.SetIfAbsent("lock", String("owner1"), time.Hour) || lru.SetIfAbsent("lock", String("owner2"), time.Hour) {
+		t.Fatalf("expected only the first SetIfAbsent to succeed")
+	}
+	if v, _ := lru.Get("lock"); v != String("owner1") {
+		t.Fatalf("expected owner1 but got %v", v)
+	}
+
+	// 过期后可以重新获取
+	lru.SetIfPresent("lock", String("owner1"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if !lru.SetIfAbsent("lock", String("owner2"), 0) {
+		t.Fatalf("expected SetIfAbsent to succeed after expiration")
+	}
+}
+
+func TestCache_SetIfPresent(t *testing.T) {
+	lru := New(int64(0), nil)
+	if lru.SetIfPresent("k1", String("v1"), 0) || lru.Contains("k1") {
+		t.Fatalf("expected SetIfPresent on missing key to fail")
+	}
+
+	lru.Add("k1", String("v1"))
+	if !lru.SetIfPresent("k1", String("v2"), 0) {
+		t.Fatalf("expected SetIfPresent on existing key to succeed")
+	}
+	if v, _ := lru.Get("k1"); v != String("v2") {
+		t.Fatalf("expected v2 but got %v", v)
+	}
+}
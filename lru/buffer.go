@@ -0,0 +1,131 @@
+package lru
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// 开启访问缓冲：Get只需持有读锁，命中的key先记录在缓冲区中，
+// 缓冲区写满size个key后才在写锁下批量通知淘汰策略。
+// 淘汰顺序因此只是近似的，但热点key的读取几乎不会产生锁竞争
+func WithAccessBuffer(size int) Option {
+	return func(c *Cache) {
+		c.buffer = newAccessBuffer(max(size, 1))
+	}
+}
+
+// 访问缓冲区，由多个条带组成以分散并发记录时的竞争
+type accessBuffer struct {
+	// 用于轮流选择条带
+	next atomic.Uint64
+
+	// 条带
+	stripes []accessStripe
+
+	// 每个条带写满时的key数量
+	size int
+}
+
+// 访问缓冲区的条带
+type accessStripe struct {
+	mu   sync.Mutex
+	keys []string
+
+	// 已被处理完的缓冲区，条带写满时优先复用，避免每次写满都分配新的缓冲区
+	spare []string
+}
+
+// 实例化访问缓冲区，条带数量与可同时运行的goroutine数量一致
+func newAccessBuffer(size int) *accessBuffer {
+	b := &accessBuffer{
+		stripes: make([]accessStripe, runtime.GOMAXPROCS(0)),
+		size:    size,
+	}
+	for i := range b.stripes {
+		b.stripes[i].keys = make([]string, 0, size)
+	}
+
+	return b
+}
+
+// 记录一次访问，条带写满时返回条带与其中的所有key并清空条带，
+// 调用方处理完这些key之后需调用recycle归还缓冲区
+func (b *accessBuffer) record(key string) (*accessStripe, []string) {
+	stripe := &b.stripes[b.next.Add(1)%uint64(len(b.stripes))]
+
+	stripe.mu.Lock()
+	defer stripe.mu.Unlock()
+
+	stripe.keys = append(stripe.keys, key)
+	if len(stripe.keys) < b.size {
+		return nil, nil
+	}
+
+	keys := stripe.keys
+	if stripe.spare != nil {
+		stripe.keys, stripe.spare = stripe.spare, nil
+	} else {
+		stripe.keys = make([]string, 0, b.size)
+	}
+
+	return stripe, keys
+}
+
+// 归还record返回的缓冲区，清除其中的key以免阻止key被回收
+func (s *accessStripe) recycle(keys []string) {
+	clear(keys)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.spare == nil {
+		s.spare = keys[:0]
+	}
+}
+
+// 开启访问缓冲时的查找功能
+func (c *Cache) getBuffered(key string) (value Value, ok bool) {
+	now := c.now()
+
+	c.mu.RLock()
+	keyValue, ok := c.cache[key]
+	expired := ok && keyValue.expired(now)
+	if ok && !expired {
+		// 条目被删除后会被复用，因此必须在读锁下记录命中
+		value = keyValue.value
+		c.hit(keyValue, value)
+	}
+	c.mu.RUnlock()
+
+	if !ok {
+		c.miss(key)
+		return nil, false
+	}
+
+	// 已过期的条目需要在写锁下重新检查后再删除
+	if expired {
+		c.mu.Lock()
+		if keyValue, ok := c.cache[key]; ok && keyValue.expired(now) {
+			c.removeEntry(keyValue, EvictedExpired)
+		}
+		c.mu.Unlock()
+
+		c.miss(key)
+		return nil, false
+	}
+
+	// 缓冲区写满时批量通知淘汰策略
+	if stripe, keys := c.buffer.record(key); keys != nil {
+		c.mu.Lock()
+		for _, key := range keys {
+			if _, ok := c.cache[key]; ok {
+				c.policy.OnGet(key)
+			}
+		}
+		c.mu.Unlock()
+		stripe.recycle(keys)
+	}
+
+	return value, true
+}
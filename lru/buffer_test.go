@@ -0,0 +1,65 @@
+package lru
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache_AccessBuffer(t *testing.T) {
+	lru := New(int64(12), nil, WithAccessBuffer(2))
+	lru.buffer.stripes = lru.buffer.stripes[:1]
+	lru.Add("k1", String("vv"))
+	lru.Add("k2", String("vv"))
+	lru.Add("k3", String("vv"))
+
+	// 缓冲区未写满时不调整淘汰顺序
+	lru.Get("k1")
+	if keys := lru.Keys(); keys[0] != "k3" {
+		t.Fatalf("access should be buffered, got keys %s", keys)
+	}
+
+	// 缓冲区写满后批量调整淘汰顺序
+	lru.Get("k1")
+	if keys := lru.Keys(); keys[0] != "k1" || keys[1] != "k3" {
+		t.Fatalf("buffered accesses should be applied, got keys %s", keys)
+	}
+
+	lru.Add("k4", String("vv"))
+	if lru.Contains("k2") || !lru.Contains("k1") {
+		t.Fatalf("k2 should be evicted")
+	}
+}
+
+func TestCache_AccessBufferExpired(t *testing.T) {
+	lru := New(int64(0), nil, WithAccessBuffer(16))
+	lru.AddWithTTL("k1", String("vv"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := lru.Get("k1"); ok || lru.Len() != 0 {
+		t.Fatalf("expired entry should be removed")
+	}
+}
+
+func TestCache_AccessBufferConcurrent(t *testing.T) {
+	lru := New(int64(100), nil, WithAccessBuffer(8))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				key := strconv.Itoa(i*1000 + j)
+				lru.Add(key, String("v"))
+				lru.Get(key)
+				lru.Get(strconv.Itoa(j))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if lru.size > 100 {
+		t.Fatalf("expected size <= 100 but got %d", lru.size)
+	}
+}
@@ -0,0 +1,50 @@
+package lru
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// key不存在
+	ErrNotFound = errors.New("lru: key not found")
+
+	// key对应的value不是Int64Value
+	ErrNotInteger = errors.New("lru: value is not an Int64Value")
+)
+
+// 将key对应的Int64Value原子地加上delta并返回新的值，保留原来的过期时间。
+// key不存在时，create为true则以delta为初始值加入，否则返回ErrNotFound。
+// 新的值过大或配额不足而没有写入时返回ErrTooLarge或ErrQuotaExceeded，原来的值保持不变
+func (c *Cache) IncrBy(key string, delta int64, create bool) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keyValue, ok := c.lookup(key, c.now())
+	if !ok {
+		if !create {
+			return 0, ErrNotFound
+		}
+		if err := c.update(key, Int64Value(delta), time.Time{}); err != nil {
+			return 0, err
+		}
+		return delta, nil
+	}
+
+	n, ok := keyValue.value.(Int64Value)
+	if !ok {
+		return 0, ErrNotInteger
+	}
+
+	n += Int64Value(delta)
+	if err := c.update(key, n, keyValue.expire); err != nil {
+		return 0, err
+	}
+
+	return int64(n), nil
+}
+
+// 将key对应的Int64Value原子地减去delta并返回新的值，与IncrBy(key, -delta, create)相同
+func (c *Cache) DecrBy(key string, delta int64, create bool) (int64, error) {
+	return c.IncrBy(key, -delta, create)
+}
@@ -0,0 +1,35 @@
+package lru
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestCache_IncrBy(t *testing.T) {
+	lru := New(int64(0), nil)
+	if _, err := lru.IncrBy("hits", 1, false); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound but got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				lru.IncrBy("hits", 2, true)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n, err := lru.DecrBy("hits", 600, false); err != nil || n != 1000 {
+		t.Fatalf("expected 1000 but got %d, %v", n, err)
+	}
+
+	lru.Add("name", String("Tom"))
+	if _, err := lru.IncrBy("name", 1, true); !errors.Is(err, ErrNotInteger) {
+		t.Fatalf("expected ErrNotInteger but got %v", err)
+	}
+}
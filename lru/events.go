@@ -0,0 +1,91 @@
+package lru
+
+// 事件类型
+type EventType int
+
+const (
+	// 新条目加入
+	EventAdded EventType = iota
+
+	// 已有条目被修改
+	EventUpdated
+
+	// 条目因容量不足被淘汰，包括调整容量后被淘汰的情况
+	EventEvicted
+
+	// 条目因过期被清除
+	EventExpired
+
+	// 条目被显式删除或被清空
+	EventRemoved
+)
+
+// 获取事件类型的名称
+func (t EventType) String() string {
+	switch t {
+	case EventAdded:
+		return "added"
+	case EventUpdated:
+		return "updated"
+	case EventEvicted:
+		return "evicted"
+	case EventExpired:
+		return "expired"
+	case EventRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// 缓存条目的变化
+type Event struct {
+	Type EventType
+	Key  string
+
+	// 条目占用的字节数，对于离开缓存的条目为离开前的大小
+	Size int64
+}
+
+// 开启事件订阅：条目的变化以Event的形式发送到容量为size的通道中，通过Events获取。
+// 发送不会阻塞写入，通道已满时事件被丢弃，丢弃的数量可以通过DroppedEvents获取
+func WithEvents(size int) Option {
+	return func(c *Cache) {
+		c.events = make(chan Event, max(size, 1))
+	}
+}
+
+// 获取事件通道，未开启事件订阅时返回nil。通道不会被关闭
+func (c *Cache) Events() <-chan Event {
+	return c.events
+}
+
+// 获取因事件通道已满而被丢弃的事件数量
+func (c *Cache) DroppedEvents() int64 {
+	return c.droppedEvents.Load()
+}
+
+// 发送事件，未开启事件订阅时不做任何事，调用方需持有锁
+func (c *Cache) publish(t EventType, key string, size int64) {
+	if c.events == nil {
+		return
+	}
+
+	select {
+	case c.events <- Event{Type: t, Key: key, Size: size}:
+	default:
+		c.droppedEvents.Add(1)
+	}
+}
+
+// 获取离开原因对应的事件类型
+func eventTypeOf(reason EvictionReason) EventType {
+	switch reason {
+	case EvictedExpired:
+		return EventExpired
+	case EvictedRemoved, EvictedCleared:
+		return EventRemoved
+	default:
+		return EventEvicted
+	}
+}
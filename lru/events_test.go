@@ -0,0 +1,48 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCache_Events(t *testing.T) {
+	lru := New(int64(8), nil, WithEvents(16))
+	lru.Add("k1", String("v1"))
+	lru.Add("k1", String("v11"))
+	lru.Add("k2", String("v2"))
+	lru.AddWithTTL("k3", String("v3"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	lru.Get("k3")
+	lru.Remove("k2")
+
+	expect := []Event{
+		{EventAdded, "k1", 4},
+		{EventUpdated, "k1", 5},
+		{EventAdded, "k2", 4},
+		{EventEvicted, "k1", 5},
+		{EventAdded, "k3", 4},
+		{EventExpired, "k3", 4},
+		{EventRemoved, "k2", 4},
+	}
+	events := make([]Event, 0)
+	for len(lru.Events()) > 0 {
+		events = append(events, <-lru.Events())
+	}
+	if !reflect.DeepEqual(expect, events) {
+		t.Fatalf("expected %v but got %v", expect, events)
+	}
+}
+
+func TestCache_EventsDropped(t *testing.T) {
+	lru := New(int64(0), nil, WithEvents(1))
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+
+	if n := lru.DroppedEvents(); n != 1 || len(lru.Events()) != 1 {
+		t.Fatalf("expected 1 dropped event but got %d", n)
+	}
+	if New(int64(0), nil).Events() != nil {
+		t.Fatalf("expected nil channel without WithEvents")
+	}
+}
@@ -0,0 +1,25 @@
+package lru
+
+import "expvar"
+
+// 将缓存的统计信息发布到expvar，变量名为cache.<name>.hits、cache.<name>.misses等，
+// 每次读取变量时实时获取统计信息。与expvar.Publish一致，name重复时会panic
+func (c *Cache) Publish(name string) {
+	prefix := "cache." + name + "."
+	vars := map[string]func(Stats) int64{
+		"hits":      func(s Stats) int64 { return s.Hits },
+		"misses":    func(s Stats) int64 { return s.Misses },
+		"adds":      func(s Stats) int64 { return s.Adds },
+		"updates":   func(s Stats) int64 { return s.Updates },
+		"evictions": func(s Stats) int64 { return s.Evictions },
+		"bytes":     func(s Stats) int64 { return s.Bytes },
+		"entries":   func(s Stats) int64 { return s.Entries },
+	}
+
+	for suffix, field := range vars {
+		field := field
+		expvar.Publish(prefix+suffix, expvar.Func(func() any {
+			return field(c.Stats())
+		}))
+	}
+}
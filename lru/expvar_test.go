@@ -0,0 +1,26 @@
+package lru
+
+import (
+	"expvar"
+	"testing"
+)
+
+func TestCache_Publish(t *testing.T) {
+	lru := New(int64(0), nil)
+	lru.Publish("test")
+	lru.Add("k1", String("vv"))
+	lru.Get("k1")
+	lru.Get("k2")
+
+	expect := map[string]string{
+		"cache.test.hits":    "1",
+		"cache.test.misses":  "1",
+		"cache.test.bytes":   "4",
+		"cache.test.entries": "1",
+	}
+	for name, value := range expect {
+		if v := expvar.Get(name); v == nil || v.String() != value {
+			t.Fatalf("expected %s to be %s but got %v", name, value, v)
+		}
+	}
+}
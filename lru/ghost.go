@@ -0,0 +1,23 @@
+package lru
+
+import "cache/ghost"
+
+// 记录最近因容量不足被淘汰的n个key（不保存value），未命中的key仍在记录中时计为一次幽灵命中，
+// 可用于估算增大容量能够多命中多少次
+func WithGhosts(n int) Option {
+	return func(c *Cache) {
+		c.ghosts = ghost.New(n)
+	}
+}
+
+// 判断key是否最近因容量不足被淘汰且尚未重新加入缓存，未开启幽灵记录时返回false
+func (c *Cache) Ghost(key string) bool {
+	return c.ghosts != nil && c.ghosts.Contains(key)
+}
+
+// 记录被淘汰的key，只有因容量不足被淘汰的key才说明更大的容量本可以命中
+func (c *Cache) bury(key string, reason EvictionReason) {
+	if c.ghosts != nil && (reason == EvictedCapacity || reason == EvictedResized) {
+		c.ghosts.Add(key)
+	}
+}
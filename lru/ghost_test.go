@@ -0,0 +1,39 @@
+package lru
+
+import "testing"
+
+func TestCache_WithGhosts(t *testing.T) {
+	lru := New(int64(4), nil, WithGhosts(1))
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	lru.Add("k3", String("v3"))
+
+	// 只记录最近被淘汰的一个key
+	if lru.Ghost("k1") || !lru.Ghost("k2") {
+		t.Fatalf("expected only k2 to be a ghost")
+	}
+	lru.Get("k2")
+	lru.Get("k1")
+	if stats := lru.Stats(); stats.Misses != 2 || stats.GhostHits != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	// 重新加入缓存后不再是幽灵，主动删除的key不会被记录
+	lru.Add("k2", String("v2"))
+	if lru.Ghost("k2") || !lru.Ghost("k3") {
+		t.Fatalf("expected only k3 to be a ghost")
+	}
+	lru.Remove("k2")
+	if lru.Ghost("k2") {
+		t.Fatalf("expected removed key not to be a ghost")
+	}
+
+	// 未开启幽灵记录时不统计
+	lru = New(int64(4), nil)
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	lru.Get("k1")
+	if lru.Ghost("k1") || lru.Stats().GhostHits != 0 {
+		t.Fatalf("expected ghosts to be disabled")
+	}
+}
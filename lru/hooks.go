@@ -0,0 +1,45 @@
+package lru
+
+// 生命周期回调函数，可用于记录日志、同步到其他存储或维护二级索引，未设置的回调不会被调用。
+// 回调可能在持有锁的情况下执行，因此不能在回调中再调用Cache的方法
+type Hooks struct {
+	// 新条目加入时调用
+	OnAdd func(key string, value Value)
+
+	// 已有条目被修改时调用，old为修改前的value
+	OnUpdate func(key string, old, value Value)
+
+	// 查找命中时调用
+	OnGet func(key string, value Value)
+
+	// 查找未命中时调用，包括查找到已过期条目的情况
+	OnMiss func(key string)
+}
+
+// 设置生命周期回调函数，条目离开缓存时的回调仍通过OnEvicted或WithEvictionReason设置
+func WithHooks(hooks Hooks) Option {
+	return func(c *Cache) {
+		c.hooks = hooks
+	}
+}
+
+// 记录一次命中，value为命中时读取的keyValue.value
+func (c *Cache) hit(keyValue *entry, value Value) {
+	c.counters.hits.Add(1)
+	keyValue.hits.Add(1)
+	keyValue.accessed.Store(c.now().UnixNano())
+	if c.hooks.OnGet != nil {
+		c.hooks.OnGet(keyValue.key, value)
+	}
+}
+
+// 记录一次未命中
+func (c *Cache) miss(key string) {
+	c.counters.misses.Add(1)
+	if c.Ghost(key) {
+		c.counters.ghostHits.Add(1)
+	}
+	if c.hooks.OnMiss != nil {
+		c.hooks.OnMiss(key)
+	}
+}
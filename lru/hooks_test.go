@@ -0,0 +1,35 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCache_Hooks(t *testing.T) {
+	events := make([]string, 0)
+	lru := New(int64(0), nil, WithHooks(Hooks{
+		OnAdd: func(key string, value Value) {
+			events = append(events, "add "+key+"="+string(value.(String)))
+		},
+		OnUpdate: func(key string, old, value Value) {
+			events = append(events, "update "+key+" "+string(old.(String))+"->"+string(value.(String)))
+		},
+		OnGet: func(key string, value Value) {
+			events = append(events, "get "+key)
+		},
+		OnMiss: func(key string) {
+			events = append(events, "miss "+key)
+		},
+	}))
+
+	lru.Add("k1", String("v1"))
+	lru.Add("k1", String("v2"))
+	lru.Get("k1")
+	lru.Get("k2")
+	lru.GetMulti([]string{"k1", "k3"})
+
+	expect := []string{"add k1=v1", "update k1 v1->v2", "get k1", "miss k2", "get k1", "miss k3"}
+	if !reflect.DeepEqual(expect, events) {
+		t.Fatalf("expected %s but got %s", expect, events)
+	}
+}
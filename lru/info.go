@@ -0,0 +1,88 @@
+package lru
+
+import "time"
+
+// 条目的元数据，用于调试与研究准入策略
+type EntryInfo struct {
+	// 首次加入缓存的时间
+	Created time.Time
+
+	// 最近一次命中的时间，零值表示从未命中
+	LastAccess time.Time
+
+	// 命中次数
+	Hits uint64
+
+	// 条目占用的字节数
+	Size int64
+
+	// 剩余存活时间，为0时表示永不过期
+	TTL time.Duration
+
+	// 条目在淘汰策略中所在的段，例如SLRU的probation与protected，淘汰策略不分段时为空
+	Segment string
+
+	// 是否被固定
+	Pinned bool
+
+	// 优先级
+	Priority int
+
+	// 版本号
+	Version uint64
+}
+
+// 获取key对应条目的元数据，不会影响条目的淘汰顺序，也不记录命中
+func (c *Cache) EntryInfo(key string) (EntryInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+	keyValue, ok := c.cache[key]
+	if !ok || keyValue.expired(now) {
+		return EntryInfo{}, false
+	}
+
+	info := EntryInfo{
+		Created:  keyValue.created,
+		Hits:     keyValue.hits.Load(),
+		Size:     keyValue.cost(),
+		Pinned:   keyValue.pinned,
+		Priority: keyValue.priority,
+		Version:  keyValue.version,
+	}
+	if accessed := keyValue.accessed.Load(); accessed != 0 {
+		info.LastAccess = time.Unix(0, accessed)
+	}
+	if !keyValue.expire.IsZero() {
+		info.TTL = keyValue.expire.Sub(now)
+	}
+	if segmenter, ok := c.policy.(interface{ segment(string) string }); ok {
+		info.Segment = segmenter.segment(key)
+	}
+
+	return info, true
+}
+
+// 获取条目所在的段
+func (p *slruPolicy) segment(key string) string {
+	element, ok := p.elements[key]
+	if !ok {
+		return ""
+	}
+	if element.Value.protected {
+		return "protected"
+	}
+
+	return "probation"
+}
+
+// 获取条目在所在优先级的子策略中的段
+func (p *priorityPolicy) segment(key string) string {
+	// 在读锁下调用，不能使用会创建子策略的class
+	if segmenter, ok := p.classes[p.priorities[key]].(interface{ segment(string) string }); ok {
+		return segmenter.segment(key)
+	}
+
+	return ""
+}
@@ -0,0 +1,36 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache_EntryInfo(t *testing.T) {
+	lru := NewSLRU(int64(0), 0.8, nil)
+	before := time.Now()
+	lru.AddWithTTL("k1", String("v1"), time.Hour)
+
+	info, ok := lru.EntryInfo("k1")
+	if !ok || info.Created.Before(before) || !info.LastAccess.IsZero() || info.Hits != 0 || info.Size != 4 || info.Segment != "probation" {
+		t.Fatalf("unexpected info %+v", info)
+	}
+	if info.TTL <= 59*time.Minute || info.TTL > time.Hour {
+		t.Fatalf("unexpected TTL %v", info.TTL)
+	}
+
+	lru.Get("k1")
+	lru.Get("k1")
+	info, _ = lru.EntryInfo("k1")
+	if info.Hits != 2 || info.LastAccess.Before(info.Created) || info.Segment != "protected" {
+		t.Fatalf("unexpected info after hits %+v", info)
+	}
+
+	if _, ok := lru.EntryInfo("unknown"); ok {
+		t.Fatalf("expected no info for missing key")
+	}
+	plain := New(int64(0), nil)
+	plain.Add("k1", String("v1"))
+	if info, ok := plain.EntryInfo("k1"); !ok || info.Segment != "" || info.TTL != 0 {
+		t.Fatalf("expected no segment for LRU policy but got %+v", info)
+	}
+}
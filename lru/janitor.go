@@ -0,0 +1,59 @@
+package lru
+
+import (
+	"sync"
+	"time"
+)
+
+// 开启后台清理：每隔interval清除一次已过期的条目，使从未再被读取的过期条目也能及时释放内存。
+// 开启后需调用Stop停止后台goroutine
+func WithJanitor(interval time.Duration) Option {
+	return func(c *Cache) {
+		if interval > 0 {
+			c.janitor = &janitor{interval: interval, stop: make(chan struct{})}
+		}
+	}
+}
+
+// 定期清除过期条目的后台goroutine
+type janitor struct {
+	// 两次清理之间的间隔
+	interval time.Duration
+
+	// 关闭时通知goroutine退出
+	stop chan struct{}
+
+	// 保证stop只被关闭一次
+	once sync.Once
+}
+
+// 每隔interval清除一次c中已过期的条目，直到stop被关闭
+func (j *janitor) run(c *Cache) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.RemoveExpired()
+		case <-j.stop:
+			return
+		}
+	}
+}
+
+// 停止后台清理与异步回调，并等待队列中剩余的回调执行完毕，都未开启时不做任何事，可以多次调用
+func (c *Cache) Stop() {
+	if c.janitor != nil {
+		c.janitor.once.Do(func() {
+			close(c.janitor.stop)
+		})
+	}
+
+	if c.dispatcher != nil {
+		c.dispatcher.once.Do(func() {
+			close(c.dispatcher.stop)
+		})
+		<-c.dispatcher.done
+	}
+}
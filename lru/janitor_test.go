@@ -0,0 +1,45 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache_Janitor(t *testing.T) {
+	var mu sync.Mutex
+	evicted := make([]string, 0)
+	lru := New(int64(0), func(key string, value Value) {
+		mu.Lock()
+		defer mu.Unlock()
+		evicted = append(evicted, key)
+	}, WithJanitor(time.Millisecond))
+	defer lru.Stop()
+
+	lru.AddWithTTL("key1", String("value1"), time.Millisecond)
+	lru.Add("key2", String("value2"))
+
+	// 不读取key1，等待后台清理
+	deadline := time.Now().Add(time.Second)
+	for lru.Len() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected key1 to be reaped but got %d entries", lru.Len())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(evicted) != 1 || evicted[0] != "key1" {
+		t.Fatalf("expected key1 evicted but got %s", evicted)
+	}
+}
+
+func TestCache_Stop(t *testing.T) {
+	// 未开启后台清理或多次调用Stop都不会出错
+	New(int64(0), nil).Stop()
+
+	lru := New(int64(0), nil, WithJanitor(time.Millisecond))
+	lru.Stop()
+	lru.Stop()
+}
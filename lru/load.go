@@ -0,0 +1,31 @@
+package lru
+
+import "context"
+
+// 查找key对应的value，未命中时调用loader加载并放入缓存。同一个key的并发加载只会调用一次loader，
+// 等待中的调用方共享同一个结果，loader返回错误时不放入缓存
+func (c *Cache) GetOrLoad(ctx context.Context, key string, loader func(ctx context.Context) (Value, error)) (Value, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	value, err := c.loads.Do(key, func() (interface{}, error) {
+		// 等待获取执行权期间其他调用方可能已经加载完成
+		if value, ok := c.Peek(key); ok {
+			return value, nil
+		}
+
+		value, err := loader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.Add(key, value)
+
+		return value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(Value), nil
+}
@@ -0,0 +1,52 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_GetOrLoad(t *testing.T) {
+	lru := New(int64(0), nil)
+	ctx := context.Background()
+
+	var loads int32
+	loader := func(ctx context.Context) (Value, error) {
+		atomic.AddInt32(&loads, 1)
+		time.Sleep(10 * time.Millisecond)
+		return String("630"), nil
+	}
+
+	// 并发加载同一个key只会调用一次loader
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := lru.GetOrLoad(ctx, "Tom", loader); err != nil || v.(String) != "630" {
+				t.Errorf("failed to load Tom: %v, %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&loads); n != 1 {
+		t.Fatalf("expected 1 load but got %d", n)
+	}
+
+	// 已缓存的key不再调用loader
+	if _, err := lru.GetOrLoad(ctx, "Tom", loader); err != nil || atomic.LoadInt32(&loads) != 1 {
+		t.Fatalf("expected cache hit for Tom")
+	}
+
+	// 加载失败时不放入缓存
+	_, err := lru.GetOrLoad(ctx, "Jack", func(ctx context.Context) (Value, error) {
+		return nil, errors.New("unavailable")
+	})
+	if err == nil || lru.Contains("Jack") {
+		t.Fatalf("expected error and no cached value for Jack")
+	}
+}
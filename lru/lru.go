@@ -1,124 +1,499 @@
 package lru
 
-import "container/list"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cache/ghost"
+	"cache/quota"
+	"cache/singleflight"
+	"cache/timesource"
+)
 
 // Value接口使用len函数计算其占用的字节
 type Value interface {
 	Len() int
 }
 
-// 链表节点
+// 缓存条目
 type entry struct {
 	key   string
 	value Value
+
+	// 过期时间，零值表示永不过期
+	expire time.Time
+
+	// 加入缓存时计算的开销
+	size int64
+
+	// 是否被固定，被固定的条目不会被淘汰
+	pinned bool
+
+	// 优先级，淘汰策略支持优先级时优先淘汰优先级低的条目
+	priority int
+
+	// 版本号，每次新增或修改时递增
+	version uint64
+
+	// 首次加入缓存的时间
+	created time.Time
+
+	// 最近一次命中的时间（UnixNano），为0时表示从未命中，开启访问缓冲时在读锁下更新
+	accessed atomic.Int64
+
+	// 命中次数
+	hits atomic.Uint64
+}
+
+// 复用被删除的条目，减少频繁新增与淘汰时的内存分配
+var entryPool = sync.Pool{
+	New: func() any {
+		return new(entry)
+	},
+}
+
+// 从entryPool中获取一个条目
+func newEntry(key string, value Value, expire time.Time) *entry {
+	e := entryPool.Get().(*entry)
+	e.key = key
+	e.value = value
+	e.expire = expire
+
+	return e
+}
+
+// 清空条目并放回entryPool，调用方需持有写锁且条目已从哈希表中删除，
+// 之后不能再访问该条目
+func releaseEntry(e *entry) {
+	*e = entry{}
+	entryPool.Put(e)
 }
 
-// LRU cache
+// 条目占用的字节数
+func (e *entry) cost() int64 {
+	return e.size
+}
+
+// 缓存，负责存储条目与统计缓存大小，条目的淘汰顺序由淘汰策略决定，默认使用LRU策略。
+// 可安全地被多个goroutine并发使用
 type Cache struct {
+	// 保护以下所有字段的读写锁，Get会调整淘汰策略的状态，因此默认需要持有写锁
+	mu sync.RWMutex
+
 	// 缓存的最大容量（单位为字节）
 	capacity int64
 
 	// 已使用的缓存空间（单位为字节）
 	size int64
 
-	// 双向链表
-	doubleLinkedList *list.List
+	// 最近一次分配的版本号
+	version uint64
+
+	// 缓存的最大条目数量，为0时表示不限制
+	maxEntries int
+
+	// 计算条目开销的函数，为nil时使用key的长度与Value.Len之和
+	costFunc func(key string, value any) int64
+
+	// 每个条目额外计入的固定开销（单位为字节），用于计入哈希表、链表节点与条目本身占用的内存
+	overhead int64
+
+	// 单个条目的最大开销，为0时表示只受capacity限制
+	maxValueBytes int64
+
+	// 与其他Cache共享溢出池的配额，为nil时表示只受capacity限制
+	quota *quota.Quota
+
+	// 判断条目是否过期时使用的时钟，为nil时使用系统时间
+	clock timesource.Clock
+
+	// 淘汰策略
+	policy Policy
+
+	// 存储key与条目映射关系的哈希表
+	cache map[string]*entry
+
+	// 统计信息计数器
+	counters counters
+
+	// 访问缓冲区，为nil时表示未开启访问缓冲
+	buffer *accessBuffer
+
+	// 后台清理过期条目的goroutine，为nil时表示未开启后台清理
+	janitor *janitor
+
+	// 异步执行回调的调度器，为nil时表示在持有锁的情况下同步执行回调
+	dispatcher *dispatcher
+
+	// 最近因容量不足被淘汰的key，为nil时表示未开启幽灵记录
+	ghosts *ghost.List
 
-	// 存储key与链表节点映射关系的哈希表
-	cache map[string]*list.Element
+	// 可选的生命周期回调函数
+	hooks Hooks
+
+	// 事件通道，为nil时表示未开启事件订阅
+	events chan Event
+
+	// 因事件通道已满而被丢弃的事件数量
+	droppedEvents atomic.Int64
+
+	// 保证GetOrLoad对同一个key同一时刻只加载一次
+	loads singleflight.Group
+
+	// 可选的回调函数，在条目离开缓存时被执行并带有离开的原因，包括被Clear清空的情况
+	onRemoved func(key string, value Value, reason EvictionReason)
 
 	// 可选的回调函数，在发生缓存条目清除时被执行
+	// 回调在持有锁的情况下执行，因此不能在回调中再调用Cache的方法
 	OnEvicted func(key string, value Value)
 }
 
 // 实例化LRU cache
-func New(capacity int64, onEvicted func(string, Value)) *Cache {
-	return &Cache{
-		capacity:         capacity,
-		doubleLinkedList: list.New(),
-		cache:            make(map[string]*list.Element),
-		OnEvicted:        onEvicted,
+func New(capacity int64, onEvicted func(string, Value), opts ...Option) *Cache {
+	return NewWithPolicy(capacity, newLRUPolicy(), onEvicted, opts...)
+}
+
+// 使用自定义的淘汰策略实例化cache
+func NewWithPolicy(capacity int64, policy Policy, onEvicted func(string, Value), opts ...Option) *Cache {
+	c := &Cache{
+		capacity:  capacity,
+		policy:    policy,
+		cache:     make(map[string]*entry),
+		OnEvicted: onEvicted,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.janitor != nil {
+		go c.janitor.run(c)
+	}
+	if c.dispatcher != nil {
+		go c.dispatcher.run(c)
 	}
+
+	return c
 }
 
-// 实现查找功能
+// 实现查找功能，命中与未命中时都不分配内存
 func (c *Cache) Get(key string) (value Value, ok bool) {
+	if c.buffer != nil {
+		return c.getBuffered(key)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	// 如果在哈希表中查找到了key
-	if element, ok := c.cache[key]; ok {
-		// 将对应的链表节点移动到链表最前面
-		c.doubleLinkedList.MoveToFront(element)
+	if keyValue, ok := c.cache[key]; ok {
+		// 已过期的条目视为未命中，并直接删除
+		if keyValue.expired(c.now()) {
+			c.removeEntry(keyValue, EvictedExpired)
+			c.miss(key)
+			return nil, false
+		}
 
-		// 获取链表节点存储的键值对
-		keyValue := element.Value.(*entry)
+		// 通知淘汰策略条目被访问
+		c.policy.OnGet(key)
+		c.hit(keyValue, keyValue.value)
 
 		// 返回value
 		return keyValue.value, true
 	}
 
+	c.miss(key)
+
 	return
 }
 
+// 查找key对应的value，但不通知淘汰策略，因此不会影响条目的淘汰顺序
+func (c *Cache) Peek(key string) (value Value, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// 已过期的条目视为未命中
+	if keyValue, ok := c.cache[key]; ok && !keyValue.expired(c.now()) {
+		return keyValue.value, true
+	}
+
+	return
+}
+
+// 判断key是否存在于缓存中，不会影响条目的淘汰顺序
+func (c *Cache) Contains(key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keyValue, ok := c.cache[key]
+
+	return ok && !keyValue.expired(c.now())
+}
+
 // 实现缓存淘汰功能
 func (c *Cache) RemoveOldest() {
-	// 获取尾节点
-	oldest := c.doubleLinkedList.Back()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	if oldest != nil {
-		// 从链表中删除节点
-		c.doubleLinkedList.Remove(oldest)
+	c.removeOldest(EvictedCapacity)
+}
 
-		// 获取链表节点存储的键值对
-		keyValue := oldest.Value.(*entry)
+// 移除淘汰策略选出的条目，跳过被固定的条目，没有可淘汰的条目时返回false，调用方需持有锁
+func (c *Cache) removeOldest(reason EvictionReason) bool {
+	var skipped []*entry
+	defer func() {
+		// 被跳过的条目按原来的相对顺序重新交给淘汰策略
+		for _, keyValue := range skipped {
+			c.policy.OnAdd(keyValue.key, keyValue.cost())
+			c.prioritize(keyValue)
+		}
+	}()
 
-		// 获取key
-		key := keyValue.key
+	for {
+		key, ok := c.policy.Victim()
+		if !ok {
+			return false
+		}
 
-		// 从哈希表中删除key对应的记录
-		delete(c.cache, key)
+		keyValue := c.cache[key]
+		if keyValue.pinned {
+			c.policy.Remove(key)
+			skipped = append(skipped, keyValue)
+			continue
+		}
 
-		// 更新缓存大小
-		c.size -= int64(len(keyValue.key)) + int64(keyValue.value.Len())
+		c.removeEntry(keyValue, reason)
+		c.counters.evictions.Add(1)
+		return true
+	}
+}
 
-		// 调用回调函数
-		if c.OnEvicted != nil {
-			c.OnEvicted(key, keyValue.value)
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if keyValue, ok := c.cache[key]; ok {
+		c.removeEntry(keyValue, EvictedRemoved)
+		return true
+	}
+
+	return false
+}
+
+// 清空缓存，不调用OnEvicted，但会以EvictedCleared为原因调用WithEvictionReason设置的回调函数
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, keyValue := range c.cache {
+		c.policy.Remove(key)
+		c.publish(EventRemoved, key, keyValue.cost())
+		if c.onRemoved != nil {
+			c.emit(eviction{key: key, value: keyValue.value, reason: EvictedCleared, cleared: true})
 		}
+		releaseEntry(keyValue)
 	}
+
+	c.cache = make(map[string]*entry)
+	c.unclaim(c.size)
+	c.size = 0
+}
+
+// 清空缓存，并按淘汰顺序对每个条目调用回调函数
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.cache) > 0 {
+		key, ok := c.policy.Victim()
+		if !ok {
+			break
+		}
+		c.removeEntry(c.cache[key], EvictedCleared)
+	}
+}
+
+// 从淘汰策略与哈希表中删除条目并调用回调函数，调用方需持有锁
+func (c *Cache) removeEntry(keyValue *entry, reason EvictionReason) {
+	// 获取key
+	key := keyValue.key
+
+	// 从淘汰策略与哈希表中删除key对应的记录
+	c.policy.Remove(key)
+	delete(c.cache, key)
+
+	// 更新缓存大小
+	c.size -= keyValue.cost()
+	c.unclaim(keyValue.cost())
+	c.publish(eventTypeOf(reason), key, keyValue.cost())
+	c.bury(key, reason)
+
+	// 调用回调函数，回调只会得到key与value的副本，因此之后可以复用条目
+	if c.OnEvicted != nil || c.onRemoved != nil {
+		c.emit(eviction{key: key, value: keyValue.value, reason: reason})
+	}
+	releaseEntry(keyValue)
 }
 
 // 实现新增与修改功能
 func (c *Cache) Add(key string, value Value) {
-	// 如果在哈希表中查找到了key
-	if element, ok := c.cache[key]; ok {
-		// 将链表节点移动到链表最前面
-		c.doubleLinkedList.MoveToFront(element)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.add(key, value, time.Time{})
+}
+
+// 新增或修改条目，expire为零值时表示永不过期，条目过大时返回ErrTooLarge，调用方需持有锁
+func (c *Cache) add(key string, value Value, expire time.Time) error {
+	if err := c.set(key, value, expire); err != nil {
+		return err
+	}
+	c.evict(EvictedCapacity)
+
+	return nil
+}
 
-		// 获取链表节点对应的键值对
-		keyValue := element.Value.(*entry)
+// 与add相同，但条目过大或配额不足时保留key原有的条目，用于基于原有value的条件修改，调用方需持有锁
+func (c *Cache) update(key string, value Value, expire time.Time) error {
+	if err := c.put(key, value, expire); err != nil {
+		return err
+	}
+	c.evict(EvictedCapacity)
+
+	return nil
+}
+
+// 新增或修改条目但不淘汰条目，只有配额不足时才会淘汰其他条目。条目过大或配额不足时不加入，
+// 并删除key原有的条目，返回ErrTooLarge或ErrQuotaExceeded，调用方需持有锁
+func (c *Cache) set(key string, value Value, expire time.Time) error {
+	err := c.put(key, value, expire)
+	if err != nil {
+		// 原有的value已被新的value代替，不能继续留在缓存中
+		if keyValue, ok := c.cache[key]; ok {
+			c.removeEntry(keyValue, EvictedRemoved)
+		}
+	}
+
+	return err
+}
 
+// 与set相同，但条目过大或配额不足时不修改key原有的条目，调用方需持有锁
+func (c *Cache) put(key string, value Value, expire time.Time) error {
+	size := c.costOf(key, value)
+	err := ErrTooLarge
+	if !c.tooLarge(size) {
+		err = c.claim(key, size)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 如果在哈希表中查找到了key
+	if keyValue, ok := c.cache[key]; ok {
 		// 更新缓存大小
-		c.size = c.size - int64(keyValue.value.Len()) + int64(value.Len())
+		c.size = c.size - keyValue.size + size
 
 		// 更新键值对
+		old := keyValue.value
 		keyValue.value = value
-	} else {
-		// 如果没有在哈希表中查找到key，则先新建一个节点并插入到链表最前面
-		element := c.doubleLinkedList.PushFront(&entry{key: key, value: value})
+		keyValue.expire = expire
+		keyValue.size = size
+		c.version++
+		keyValue.version = c.version
 
-		// 在哈希表中建立映射关系
-		c.cache[key] = element
+		// 通知淘汰策略条目被修改
+		c.policy.OnAdd(key, keyValue.cost())
+		c.counters.updates.Add(1)
+		if c.hooks.OnUpdate != nil {
+			c.hooks.OnUpdate(key, old, value)
+		}
+		c.publish(EventUpdated, key, size)
+	} else {
+		// 如果没有在哈希表中查找到key，则新建一个条目并在哈希表中建立映射关系
+		c.version++
+		keyValue := newEntry(key, value, expire)
+		keyValue.size = size
+		keyValue.version = c.version
+		keyValue.created = c.now()
+		c.cache[key] = keyValue
+		if c.ghosts != nil {
+			c.ghosts.Remove(key)
+		}
 
 		// 更新缓存大小
-		c.size += int64(len(key)) + int64(value.Len())
+		c.size += keyValue.cost()
+
+		// 通知淘汰策略新条目加入
+		c.policy.OnAdd(key, keyValue.cost())
+		c.counters.adds.Add(1)
+		if c.hooks.OnAdd != nil {
+			c.hooks.OnAdd(key, value)
+		}
+		c.publish(EventAdded, key, keyValue.cost())
+	}
+
+	return nil
+}
+
+// 获取当前时间
+func (c *Cache) now() time.Time {
+	if c.clock != nil {
+		return c.clock.Now()
+	}
+
+	return time.Now()
+}
+
+// 计算条目的开销，包括固定开销，调用方需持有锁
+func (c *Cache) costOf(key string, value Value) int64 {
+	if c.costFunc != nil {
+		return c.costFunc(key, value) + c.overhead
+	}
+
+	return int64(len(key)) + int64(value.Len()) + c.overhead
+}
+
+// 如果缓存大小大于缓存容量或条目数量大于最大条目数量，则持续移除淘汰策略选出的条目，
+// reason为传给回调函数的原因，返回被淘汰的条目数量，调用方需持有锁
+func (c *Cache) evict(reason EvictionReason) int {
+	evicted := 0
+	for c.overflow() && c.removeOldest(reason) {
+		evicted++
 	}
 
-	// 如果缓存大小大于缓存容量，则持续移除最近最少访问的节点
-	for c.capacity != 0 && c.capacity < c.size {
-		c.RemoveOldest()
+	return evicted
+}
+
+// 判断缓存大小或条目数量是否超出限制，调用方需持有锁
+func (c *Cache) overflow() bool {
+	return c.capacity != 0 && c.capacity < c.size || c.maxEntries != 0 && c.maxEntries < len(c.cache)
+}
+
+// 调整缓存的最大容量，并立即淘汰条目直到缓存大小不超过新的容量，返回被淘汰的条目数量
+func (c *Cache) Resize(capacity int64) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.capacity = capacity
+	if resizer, ok := c.policy.(interface{ resize(int64) }); ok {
+		resizer.resize(capacity)
 	}
+
+	return c.evict(EvictedResized)
 }
 
-// 获取缓存的条目数量
+// 获取缓存的条目数量，包括已过期但尚未被清除的条目
 func (c *Cache) Len() int {
-	return c.doubleLinkedList.Len()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.cache)
+}
+
+// 获取已使用的缓存空间（单位为字节），包括已过期但尚未被清除的条目
+func (c *Cache) Size() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.size
 }
@@ -2,7 +2,10 @@ package lru
 
 import (
 	"reflect"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 )
 
 type String string
@@ -65,3 +68,281 @@ func TestCache_Add(t *testing.T) {
 		t.Fatal("expected 6 but got", lru.size)
 	}
 }
+
+func TestCache_Concurrent(t *testing.T) {
+	lru := New(int64(100), nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				key := strconv.Itoa(i*1000 + j)
+				lru.Add(key, String("v"))
+				lru.Get(key)
+				if j%10 == 0 {
+					lru.RemoveOldest()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if lru.size > 100 {
+		t.Fatalf("expected size <= 100 but got %d", lru.size)
+	}
+}
+
+func TestCache_Remove(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	lru := New(int64(0), callback)
+	lru.Add("key1", String("value1"))
+	lru.Add("key2", String("value2"))
+
+	if !lru.Remove("key1") {
+		t.Fatalf("Remove key1 failed")
+	}
+	if lru.Remove("key1") {
+		t.Fatalf("Remove missing key1 should return false")
+	}
+	if _, ok := lru.Get("key1"); ok || lru.Len() != 1 {
+		t.Fatalf("key1 should not be in cache after Remove")
+	}
+	if lru.size != int64(len("key2")+len("value2")) {
+		t.Fatal("expected 10 but got", lru.size)
+	}
+	if !reflect.DeepEqual([]string{"key1"}, keys) {
+		t.Fatalf("Call OnEvicted failed, expect keys equals to %s", []string{"key1"})
+	}
+}
+
+func TestCache_Peek(t *testing.T) {
+	lru := New(int64(12), nil)
+	lru.Add("k1", String("vv"))
+	lru.Add("k2", String("vv"))
+	lru.Add("k3", String("vv"))
+
+	// Peek不会调整淘汰顺序，k1仍然最先被淘汰
+	if v, ok := lru.Peek("k1"); !ok || string(v.(String)) != "vv" {
+		t.Fatalf("Peek k1 failed")
+	}
+	lru.Add("k4", String("vv"))
+
+	if _, ok := lru.Peek("k1"); ok {
+		t.Fatalf("k1 should be evicted")
+	}
+}
+
+func TestCache_Contains(t *testing.T) {
+	lru := New(int64(12), nil)
+	lru.Add("k1", String("vv"))
+	lru.Add("k2", String("vv"))
+	lru.Add("k3", String("vv"))
+
+	// Contains不会调整淘汰顺序，k1仍然最先被淘汰
+	if !lru.Contains("k1") || lru.Contains("k4") {
+		t.Fatalf("Contains failed")
+	}
+	lru.Add("k4", String("vv"))
+
+	if lru.Contains("k1") {
+		t.Fatalf("k1 should be evicted")
+	}
+}
+
+func TestCache_Clear(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	lru := New(int64(12), callback)
+	lru.Add("k1", String("vv"))
+	lru.Add("k2", String("vv"))
+	lru.Clear()
+
+	if lru.Len() != 0 || lru.size != 0 || len(keys) != 0 {
+		t.Fatalf("Clear failed")
+	}
+
+	// 清空后缓存仍可以继续使用，容量与回调函数保持不变
+	lru.Add("k1", String("vv"))
+	lru.Add("k2", String("vv"))
+	lru.Add("k3", String("vv"))
+	lru.Add("k4", String("vv"))
+	if lru.Len() != 3 || !reflect.DeepEqual([]string{"k1"}, keys) {
+		t.Fatalf("cache should keep capacity and callback after Clear")
+	}
+}
+
+func TestCache_Purge(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	lru := New(int64(0), callback)
+	lru.Add("k1", String("vv"))
+	lru.Add("k2", String("vv"))
+	lru.Get("k1")
+	lru.Purge()
+
+	if lru.Len() != 0 || lru.size != 0 || !reflect.DeepEqual([]string{"k2", "k1"}, keys) {
+		t.Fatalf("Purge failed, got keys %s", keys)
+	}
+}
+
+func TestCache_Resize(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	lru := New(int64(0), callback)
+	lru.Add("k1", String("vv"))
+	lru.Add("k2", String("vv"))
+	lru.Add("k3", String("vv"))
+
+	if n := lru.Resize(int64(8)); n != 1 || !reflect.DeepEqual([]string{"k1"}, keys) {
+		t.Fatalf("expected k1 to be evicted but got %s", keys)
+	}
+
+	// 扩容后不会淘汰条目
+	if n := lru.Resize(int64(12)); n != 0 {
+		t.Fatalf("expected no evictions but got %d", n)
+	}
+	lru.Add("k4", String("vv"))
+	if lru.Len() != 3 || lru.size != 12 {
+		t.Fatalf("expected 3 entries of 12 bytes but got %d entries of %d bytes", lru.Len(), lru.size)
+	}
+}
+
+func TestCache_MaxEntries(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	lru := New(int64(0), callback, WithMaxEntries(2))
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	lru.Add("k3", String("v3"))
+
+	if lru.Len() != 2 || !reflect.DeepEqual([]string{"k1"}, keys) {
+		t.Fatalf("expected k1 to be evicted but got %s", keys)
+	}
+
+	// 字节容量先超出限制时同样会淘汰条目
+	lru = New(int64(8), nil, WithMaxEntries(3))
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	lru.Add("k3", String("v3"))
+	if lru.Len() != 2 {
+		t.Fatalf("expected 2 entries but got %d", lru.Len())
+	}
+}
+
+func TestCache_WithCost(t *testing.T) {
+	cost := func(key string, value any) int64 {
+		return int64(len(value.(String))) * 2
+	}
+	lru := New(int64(10), nil, WithCost(cost))
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	lru.Add("k2", String("v22"))
+
+	if lru.Size() != 10 || lru.Len() != 2 {
+		t.Fatalf("expected 2 entries of 10 bytes but got %d entries of %d bytes", lru.Len(), lru.Size())
+	}
+
+	lru.Add("k3", String("v3"))
+	if _, ok := lru.Get("k1"); ok || lru.Size() != 10 {
+		t.Fatalf("expected k1 to be evicted but got size %d", lru.Size())
+	}
+}
+
+func TestCache_ReuseEntry(t *testing.T) {
+	lru := New(int64(0), nil)
+	lru.AddWithPriority("k1", String("v1"), 3, time.Hour)
+	lru.Pin("k1")
+	lru.Get("k1")
+	lru.Unpin("k1")
+	lru.Remove("k1")
+
+	// 复用的条目不会保留之前条目的状态
+	lru.Add("k2", String("v2"))
+	info, ok := lru.EntryInfo("k2")
+	if !ok || info.Hits != 0 || info.Pinned || info.Priority != 0 || info.TTL != 0 || !info.LastAccess.IsZero() {
+		t.Fatalf("reused entry should be reset but got %+v", info)
+	}
+}
+
+func BenchmarkCache_AddEvict(b *testing.B) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	lru := New(int64(0), nil, WithMaxEntries(len(keys)/2))
+
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		lru.Add(keys[i%len(keys)], String("v"))
+	}
+}
+
+func TestCache_GetAllocs(t *testing.T) {
+	lru := New(int64(0), nil)
+	lru.Add("k1", String("v1"))
+
+	if allocs := testing.AllocsPerRun(100, func() { lru.Get("k1") }); allocs != 0 {
+		t.Fatalf("expected Get hit to allocate nothing but got %v allocs", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { lru.Get("k2") }); allocs != 0 {
+		t.Fatalf("expected Get miss to allocate nothing but got %v allocs", allocs)
+	}
+
+	// 开启访问缓冲时，缓冲区写满后同样不分配内存
+	lru = New(int64(0), nil, WithAccessBuffer(4))
+	lru.buffer.stripes = lru.buffer.stripes[:1]
+	lru.Add("k1", String("v1"))
+	get := func() {
+		for range 4 {
+			lru.Get("k1")
+		}
+	}
+	get()
+	if allocs := testing.AllocsPerRun(100, get); allocs != 0 {
+		t.Fatalf("expected buffered Get to allocate nothing but got %v allocs", allocs)
+	}
+}
+
+func BenchmarkCache_Get(b *testing.B) {
+	lru := New(int64(0), nil)
+	lru.Add("k1", String("v1"))
+
+	b.ReportAllocs()
+	for b.Loop() {
+		lru.Get("k1")
+	}
+}
+
+func TestCache_WithEntryOverhead(t *testing.T) {
+	lru := New(int64(30), nil, WithEntryOverhead(10))
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	if lru.Size() != 28 {
+		t.Fatalf("expected size 28 but got %d", lru.Size())
+	}
+
+	// 固定开销同样计入容量，第三个条目使最早的条目被淘汰
+	lru.Add("k3", String("v3"))
+	if lru.Len() != 2 || lru.Contains("k1") {
+		t.Fatalf("expected k1 to be evicted but got %s", lru.Keys())
+	}
+
+	// 与WithCost同时使用时加在cost的返回值上
+	lru = New(int64(0), nil, WithCost(func(key string, value any) int64 { return 1 }), WithEntryOverhead(EntryOverhead))
+	lru.Add("k1", String("v1"))
+	if lru.Size() != 1+EntryOverhead {
+		t.Fatalf("expected size %d but got %d", 1+EntryOverhead, lru.Size())
+	}
+}
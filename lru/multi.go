@@ -0,0 +1,84 @@
+package lru
+
+import "time"
+
+// 批量查找keys对应的value，只获取一次锁，命中的条目都会通知淘汰策略。
+// 返回命中的条目与未命中的key，未命中的key按keys中的顺序排列
+func (c *Cache) GetMulti(keys []string) (hits map[string]Value, misses []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	hits = make(map[string]Value, len(keys))
+
+	for _, key := range keys {
+		keyValue, ok := c.cache[key]
+		if !ok {
+			misses = append(misses, key)
+			continue
+		}
+
+		// 已过期的条目视为未命中，并直接删除
+		if keyValue.expired(now) {
+			c.removeEntry(keyValue, EvictedExpired)
+			misses = append(misses, key)
+			continue
+		}
+
+		c.policy.OnGet(key)
+		c.hit(keyValue, keyValue.value)
+		hits[key] = keyValue.value
+	}
+
+	for _, key := range misses {
+		c.miss(key)
+	}
+
+	return hits, misses
+}
+
+// 批量新增或修改条目，只获取一次锁，所有条目加入之后才统一淘汰条目。
+// 因此当entries的总大小超过容量时，本次加入的条目也可能被淘汰
+func (c *Cache) AddMulti(entries map[string]Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, value := range entries {
+		c.set(key, value, time.Time{})
+	}
+
+	c.evict(EvictedCapacity)
+}
+
+// 批量删除keys对应的条目，只获取一次锁，返回被删除的条目数量
+func (c *Cache) RemoveMulti(keys []string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for _, key := range keys {
+		if keyValue, ok := c.cache[key]; ok {
+			c.removeEntry(keyValue, EvictedRemoved)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+// 删除所有key满足pred的条目，返回被删除的条目数量。
+// pred在持有锁的情况下执行，因此不能在pred中再调用Cache的方法
+func (c *Cache) RemoveFunc(pred func(key string) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, keyValue := range c.cache {
+		if pred(key) {
+			c.removeEntry(keyValue, EvictedRemoved)
+			removed++
+		}
+	}
+
+	return removed
+}
@@ -0,0 +1,95 @@
+package lru
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCache_GetMulti(t *testing.T) {
+	lru := New(int64(0), nil)
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	lru.Add("k3", String("v3"))
+	lru.AddWithTTL("k4", String("v4"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	hits, misses := lru.GetMulti([]string{"k1", "k4", "k3", "k5"})
+
+	expectHits := map[string]Value{"k1": String("v1"), "k3": String("v3")}
+	if !reflect.DeepEqual(expectHits, hits) {
+		t.Fatalf("expect hits equals to %v but got %v", expectHits, hits)
+	}
+
+	expectMisses := []string{"k4", "k5"}
+	if !reflect.DeepEqual(expectMisses, misses) {
+		t.Fatalf("expect misses equals to %s but got %s", expectMisses, misses)
+	}
+
+	// 命中的条目被移动到最近访问的位置
+	expectKeys := []string{"k3", "k1", "k2"}
+	if keys := lru.Keys(); !reflect.DeepEqual(expectKeys, keys) {
+		t.Fatalf("expect keys equals to %s but got %s", expectKeys, keys)
+	}
+
+	if stats := lru.Stats(); stats.Hits != 2 || stats.Misses != 2 {
+		t.Fatalf("expect 2 hits and 2 misses but got %+v", stats)
+	}
+}
+
+func TestCache_AddMulti(t *testing.T) {
+	evicted := make([]string, 0)
+	lru := New(int64(8), func(key string, value Value) {
+		evicted = append(evicted, key)
+	})
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+
+	// 加入k3、k4之后缓存大小为16，统一淘汰最久未访问的k1、k2
+	lru.AddMulti(map[string]Value{"k3": String("v3"), "k4": String("v4")})
+
+	if expect := []string{"k1", "k2"}; !reflect.DeepEqual(expect, evicted) {
+		t.Fatalf("expect evicted keys equals to %s but got %s", expect, evicted)
+	}
+
+	if lru.Len() != 2 || !lru.Contains("k3") || !lru.Contains("k4") {
+		t.Fatalf("expect k3 and k4 in cache but got %s", lru.Keys())
+	}
+}
+
+func TestCache_RemoveMulti(t *testing.T) {
+	lru := New(int64(0), nil)
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	lru.Add("k3", String("v3"))
+
+	if removed := lru.RemoveMulti([]string{"k1", "k3", "k4"}); removed != 2 {
+		t.Fatalf("expect 2 removed entries but got %d", removed)
+	}
+
+	if expect := []string{"k2"}; !reflect.DeepEqual(expect, lru.Keys()) {
+		t.Fatalf("expect keys equals to %s but got %s", expect, lru.Keys())
+	}
+}
+
+func TestCache_RemoveFunc(t *testing.T) {
+	evicted := make(map[string]bool)
+	lru := New(int64(0), func(key string, value Value) {
+		evicted[key] = true
+	})
+	lru.Add("user:1:name", String("Tom"))
+	lru.Add("user:1:age", String("18"))
+	lru.Add("user:2:name", String("Jack"))
+
+	removed := lru.RemoveFunc(func(key string) bool {
+		return strings.HasPrefix(key, "user:1:")
+	})
+	if removed != 2 || !evicted["user:1:name"] || !evicted["user:1:age"] {
+		t.Fatalf("expect user:1 keys removed but got %d, %v", removed, evicted)
+	}
+
+	if lru.Len() != 1 || !lru.Contains("user:2:name") {
+		t.Fatalf("expect only user:2:name in cache but got %s", lru.Keys())
+	}
+}
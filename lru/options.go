@@ -0,0 +1,49 @@
+package lru
+
+import (
+	"unsafe"
+
+	"cache/linkedlist"
+	"cache/timesource"
+)
+
+// 哈希表中一个string到指针映射占用的估计字节数，包括key、value、控制字节以及负载因子带来的空闲槽位
+const mapSlotOverhead = 32
+
+// 使用默认的LRU淘汰策略时每个条目的估计内部开销（单位为字节）：条目本身、
+// Cache与淘汰策略各自的哈希表槽位以及淘汰策略的链表节点，不包括key与value指向的数据
+const EntryOverhead = int64(unsafe.Sizeof(entry{})) + int64(unsafe.Sizeof(linkedlist.Element[string]{})) + 2*mapSlotOverhead
+
+// 实例化cache时的可选配置
+type Option func(c *Cache)
+
+// 限制缓存的最大条目数量，字节容量或条目数量任一超出限制时都会淘汰条目，n为0时表示不限制
+func WithMaxEntries(n int) Option {
+	return func(c *Cache) {
+		c.maxEntries = n
+	}
+}
+
+// 使用cost计算条目的开销，代替默认的key长度与Value.Len之和。
+// 开启后不再调用Value.Len，capacity的单位与cost的返回值一致
+func WithCost(cost func(key string, value any) int64) Option {
+	return func(c *Cache) {
+		c.costFunc = cost
+	}
+}
+
+// 在每个条目的开销上加上overhead字节，使容量更接近缓存实际占用的内存，
+// 例如传入EntryOverhead。与WithCost同时使用时加在cost的返回值上
+func WithEntryOverhead(overhead int64) Option {
+	return func(c *Cache) {
+		c.overhead = overhead
+	}
+}
+
+// 使用clock代替系统时间判断条目是否过期，测试中可以传入timesource.Fake推进时间。
+// 后台清理的间隔仍然使用系统时间
+func WithClock(clock timesource.Clock) Option {
+	return func(c *Cache) {
+		c.clock = clock
+	}
+}
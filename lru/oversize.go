@@ -0,0 +1,31 @@
+package lru
+
+import (
+	"errors"
+	"time"
+)
+
+// 条目的开销超过单个条目的上限或缓存的容量
+var ErrTooLarge = errors.New("lru: value too large")
+
+// 限制单个条目的最大开销，单位与capacity一致，n为0时表示只受capacity限制。
+// 超过限制的条目不会加入缓存，避免为了容纳一个很大的条目而淘汰整个缓存
+func WithMaxValueBytes(n int64) Option {
+	return func(c *Cache) {
+		c.maxValueBytes = n
+	}
+}
+
+// 与AddWithTTL相同，条目的开销超过WithMaxValueBytes设置的上限或缓存的容量时不加入缓存并返回ErrTooLarge，
+// 开启WithQuota且配额不足时返回ErrQuotaExceeded。此时key原有的条目会被删除，Add等不返回错误的方法同样不会加入
+func (c *Cache) TryAdd(key string, value Value, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.add(key, value, expireAt(c.now(), ttl))
+}
+
+// 判断开销为size的条目是否过大，调用方需持有锁
+func (c *Cache) tooLarge(size int64) bool {
+	return c.maxValueBytes != 0 && size > c.maxValueBytes || c.capacity != 0 && size > c.capacity
+}
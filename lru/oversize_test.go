@@ -0,0 +1,76 @@
+package lru
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCache_TryAdd(t *testing.T) {
+	lru := New(int64(16), nil, WithMaxValueBytes(8))
+	lru.Add("k1", String("v1"))
+	if err := lru.TryAdd("k2", String("v2"), 0); err != nil {
+		t.Fatalf("TryAdd k2 failed: %v", err)
+	}
+
+	// 超过单个条目上限的value不会淘汰其他条目，并删除key原有的条目
+	if err := lru.TryAdd("k2", String("0123456789"), 0); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge but got %v", err)
+	}
+	if _, ok := lru.Get("k1"); !ok || lru.Contains("k2") || lru.Len() != 1 {
+		t.Fatalf("expected only k1 to remain")
+	}
+
+	// 没有单个条目上限时，超过容量的value同样被拒绝
+	lru = New(int64(8), nil)
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("0123456789"))
+	if _, ok := lru.Get("k1"); !ok || lru.Contains("k2") || lru.Stats().Evictions != 0 {
+		t.Fatalf("expected oversized value to be rejected")
+	}
+	if err := lru.TryAdd("k3", String("0123456789"), 0); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge but got %v", err)
+	}
+}
+
+func TestCache_ConditionalWriteTooLarge(t *testing.T) {
+	lru := New(int64(0), nil, WithMaxValueBytes(10))
+
+	// 写入失败时CompareAndSwap返回false，并保留原有的条目
+	_, version, _ := lru.GetWithVersion("k1")
+	if version, ok := lru.CompareAndSwap("k1", version, String("v1")); !ok || version == 0 {
+		t.Fatalf("CompareAndSwap k1 failed")
+	}
+	_, version, _ = lru.GetWithVersion("k1")
+	if _, ok := lru.CompareAndSwap("k1", version, String("0123456789")); ok {
+		t.Fatalf("expected CompareAndSwap with oversized value to fail")
+	}
+	if v, ok := lru.Get("k1"); !ok || v.(String) != "v1" {
+		t.Fatalf("expected k1 to keep v1")
+	}
+
+	// 写入失败时IncrBy返回错误且不加入条目
+	if _, err := lru.IncrBy("c", 1, true); err != nil {
+		t.Fatalf("IncrBy failed: %v", err)
+	}
+	if _, err := lru.IncrBy("long-counter", 1, true); !errors.Is(err, ErrTooLarge) || lru.Contains("long-counter") {
+		t.Fatalf("expected ErrTooLarge but got %v", err)
+	}
+}
+
+func TestCache_LoadTooLarge(t *testing.T) {
+	lru := New(int64(0), nil)
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("0123456789"))
+
+	var buf bytes.Buffer
+	if err := lru.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// 过大的条目不计入加载的数量
+	restored := New(int64(0), nil, WithMaxValueBytes(10))
+	if n, err := restored.Load(&buf, decodeString); err != nil || n != 1 || restored.Contains("k2") {
+		t.Fatalf("expected 1 entry to be loaded but got %d, %v", n, err)
+	}
+}
@@ -0,0 +1,40 @@
+package lru
+
+// 固定key对应的条目，被固定的条目不会被淘汰，但仍计入缓存大小，过期后仍会被清除。
+// 返回key是否存在于缓存中
+func (c *Cache) Pin(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keyValue, ok := c.cache[key]
+	if ok {
+		keyValue.pinned = true
+	}
+
+	return ok
+}
+
+// 取消固定key对应的条目，缓存大小超过容量时立即淘汰条目，返回key是否存在于缓存中
+func (c *Cache) Unpin(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keyValue, ok := c.cache[key]
+	if !ok {
+		return false
+	}
+	keyValue.pinned = false
+	c.evict(EvictedCapacity)
+
+	return true
+}
+
+// 判断key对应的条目是否被固定
+func (c *Cache) Pinned(key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keyValue, ok := c.cache[key]
+
+	return ok && keyValue.pinned
+}
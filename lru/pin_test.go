@@ -0,0 +1,41 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCache_Pin(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	lru := New(int64(8), callback)
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	if !lru.Pin("k1") || lru.Pin("unknown") || !lru.Pinned("k1") {
+		t.Fatalf("Pin failed")
+	}
+
+	// 被固定的k1不会被淘汰
+	lru.Add("k3", String("v3"))
+	lru.Add("k4", String("v4"))
+	if !reflect.DeepEqual([]string{"k2", "k3"}, keys) || !lru.Contains("k1") {
+		t.Fatalf("expected k2 and k3 to be evicted but got %s", keys)
+	}
+
+	// 所有条目都被固定时缓存大小可以超过容量
+	lru.Pin("k4")
+	if n := lru.Resize(int64(4)); n != 0 || lru.Len() != 2 || lru.Size() != 8 {
+		t.Fatalf("expected 2 pinned entries to survive Resize but got %d", lru.Len())
+	}
+
+	// 取消固定后立即淘汰
+	keys = keys[:0]
+	if !lru.Unpin("k1") || lru.Contains("k1") || !reflect.DeepEqual([]string{"k1"}, keys) {
+		t.Fatalf("expected k1 to be evicted after Unpin but got %s", keys)
+	}
+	if !reflect.DeepEqual([]string{"k4"}, lru.Keys()) {
+		t.Fatalf("expected only k4 to remain but got %s", lru.Keys())
+	}
+}
@@ -0,0 +1,71 @@
+package lru
+
+import "cache/linkedlist"
+
+// 淘汰策略，决定缓存条目的淘汰顺序。Cache负责存储条目与统计缓存大小，
+// 并在持有锁的情况下调用淘汰策略的方法，因此淘汰策略的实现无需考虑并发安全
+type Policy interface {
+	// 条目被访问时调用
+	OnGet(key string)
+
+	// 新条目加入或已有条目被修改时调用，cost为条目当前占用的字节数
+	OnAdd(key string, cost int64)
+
+	// 返回下一个应被淘汰的key，没有可淘汰的条目时ok为false
+	Victim() (key string, ok bool)
+
+	// 条目离开缓存时调用，包括被淘汰、被删除与过期
+	Remove(key string)
+}
+
+// LRU淘汰策略，淘汰最近最少访问的条目
+type lruPolicy struct {
+	// 双向链表，越靠前越是最近访问的
+	doubleLinkedList *linkedlist.List[string]
+
+	// 存储key与链表节点映射关系的哈希表
+	elements map[string]*linkedlist.Element[string]
+}
+
+// 实例化LRU淘汰策略
+func newLRUPolicy() *lruPolicy {
+	return &lruPolicy{
+		doubleLinkedList: linkedlist.New[string](),
+		elements:         make(map[string]*linkedlist.Element[string]),
+	}
+}
+
+// 将对应的链表节点移动到链表最前面
+func (p *lruPolicy) OnGet(key string) {
+	if element, ok := p.elements[key]; ok {
+		p.doubleLinkedList.MoveToFront(element)
+	}
+}
+
+// 新条目插入到链表最前面，被修改的条目移动到链表最前面
+func (p *lruPolicy) OnAdd(key string, cost int64) {
+	if element, ok := p.elements[key]; ok {
+		p.doubleLinkedList.MoveToFront(element)
+		return
+	}
+
+	p.elements[key] = p.doubleLinkedList.PushFront(key)
+}
+
+// 返回尾节点对应的key
+func (p *lruPolicy) Victim() (string, bool) {
+	oldest := p.doubleLinkedList.Back()
+	if oldest == nil {
+		return "", false
+	}
+
+	return oldest.Value, true
+}
+
+// 从链表与哈希表中删除节点
+func (p *lruPolicy) Remove(key string) {
+	if element, ok := p.elements[key]; ok {
+		p.doubleLinkedList.Remove(element)
+		delete(p.elements, key)
+	}
+}
@@ -0,0 +1,62 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 按加入顺序淘汰条目的淘汰策略，用于测试自定义淘汰策略
+type fifoPolicy struct {
+	keys []string
+}
+
+func (p *fifoPolicy) OnGet(key string) {}
+
+func (p *fifoPolicy) OnAdd(key string, cost int64) {
+	for _, k := range p.keys {
+		if k == key {
+			return
+		}
+	}
+	p.keys = append(p.keys, key)
+}
+
+func (p *fifoPolicy) Victim() (string, bool) {
+	if len(p.keys) == 0 {
+		return "", false
+	}
+
+	return p.keys[0], true
+}
+
+func (p *fifoPolicy) Remove(key string) {
+	for i, k := range p.keys {
+		if k == key {
+			p.keys = append(p.keys[:i], p.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+func TestNewWithPolicy(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	c := NewWithPolicy(int64(12), &fifoPolicy{}, callback)
+	c.Add("k1", String("vv"))
+	c.Add("k2", String("vv"))
+	c.Add("k3", String("vv"))
+
+	// 自定义淘汰策略不考虑访问，k1仍然最先被淘汰
+	c.Get("k1")
+	c.Add("k4", String("vv"))
+	c.Remove("k3")
+
+	if !reflect.DeepEqual([]string{"k1", "k3"}, keys) {
+		t.Fatalf("expected k1 and k3 to be evicted but got %s", keys)
+	}
+	if c.Len() != 2 || c.size != 8 {
+		t.Fatalf("expected 2 entries of 8 bytes but got %d entries of %d bytes", c.Len(), c.size)
+	}
+}
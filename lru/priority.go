@@ -0,0 +1,136 @@
+package lru
+
+import (
+	"sort"
+	"time"
+)
+
+// 新增或修改条目，并设置其优先级与存活时间，ttl小于等于0时表示永不过期。
+// 只有使用NewPriorityPolicy创建的淘汰策略会考虑优先级，Add与AddWithTTL修改已有条目时保留其优先级
+func (c *Cache) AddWithPriority(key string, value Value, priority int, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expire time.Time
+	if ttl > 0 {
+		expire = c.now().Add(ttl)
+	}
+
+	if c.set(key, value, expire) != nil {
+		return
+	}
+	keyValue := c.cache[key]
+	keyValue.priority = priority
+	c.prioritize(keyValue)
+	c.evict(EvictedCapacity)
+}
+
+// 通知淘汰策略条目的优先级，调用方需持有锁
+func (c *Cache) prioritize(keyValue *entry) {
+	if p, ok := c.policy.(interface{ setPriority(string, int64, int) }); ok {
+		p.setPriority(keyValue.key, keyValue.cost(), keyValue.priority)
+	}
+}
+
+// 按优先级分级的淘汰策略，每个优先级使用独立的子策略，只有优先级更低的条目都被淘汰后才淘汰优先级更高的条目
+type priorityPolicy struct {
+	// 创建子策略的函数
+	newPolicy func() Policy
+
+	// 存储优先级与子策略映射关系的哈希表
+	classes map[int]Policy
+
+	// 从低到高排序的优先级
+	order []int
+
+	// 存储key与优先级映射关系的哈希表，只包含优先级不为0的key
+	priorities map[string]int
+}
+
+// 实例化按优先级分级的淘汰策略，newPolicy用于为每个优先级创建子策略，为nil时使用LRU策略。
+// 通过AddWithPriority设置条目的优先级，其他方式加入的条目优先级为0
+func NewPriorityPolicy(newPolicy func() Policy) Policy {
+	if newPolicy == nil {
+		newPolicy = func() Policy { return newLRUPolicy() }
+	}
+
+	return &priorityPolicy{
+		newPolicy:  newPolicy,
+		classes:    make(map[int]Policy),
+		priorities: make(map[string]int),
+	}
+}
+
+// 获取优先级对应的子策略，不存在时创建
+func (p *priorityPolicy) class(priority int) Policy {
+	if class, ok := p.classes[priority]; ok {
+		return class
+	}
+
+	class := p.newPolicy()
+	p.classes[priority] = class
+	i := sort.SearchInts(p.order, priority)
+	p.order = append(p.order, 0)
+	copy(p.order[i+1:], p.order[i:])
+	p.order[i] = priority
+
+	return class
+}
+
+// 通知条目所在的子策略
+func (p *priorityPolicy) OnGet(key string) {
+	p.class(p.priorities[key]).OnGet(key)
+}
+
+// 新条目加入优先级为0的子策略，被修改的条目保留在原来的子策略中
+func (p *priorityPolicy) OnAdd(key string, cost int64) {
+	p.class(p.priorities[key]).OnAdd(key, cost)
+}
+
+// 将条目移动到priority对应的子策略
+func (p *priorityPolicy) setPriority(key string, cost int64, priority int) {
+	if old := p.priorities[key]; old != priority {
+		p.class(old).Remove(key)
+		p.class(priority).OnAdd(key, cost)
+	}
+
+	if priority == 0 {
+		delete(p.priorities, key)
+	} else {
+		p.priorities[key] = priority
+	}
+}
+
+// 从优先级最低的子策略开始选择被淘汰的条目
+func (p *priorityPolicy) Victim() (string, bool) {
+	for _, priority := range p.order {
+		if key, ok := p.classes[priority].Victim(); ok {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+// 从条目所在的子策略中删除
+func (p *priorityPolicy) Remove(key string) {
+	p.class(p.priorities[key]).Remove(key)
+	delete(p.priorities, key)
+}
+
+// 从优先级最高的子策略开始遍历，与淘汰顺序相反
+func (p *priorityPolicy) Range(f func(key string) bool) {
+	stopped := false
+	visit := func(key string) bool {
+		if !f(key) {
+			stopped = true
+		}
+		return !stopped
+	}
+
+	for i := len(p.order) - 1; i >= 0 && !stopped; i-- {
+		if ranger, ok := p.classes[p.order[i]].(Ranger); ok {
+			ranger.Range(visit)
+		}
+	}
+}
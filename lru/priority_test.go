@@ -0,0 +1,36 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCache_AddWithPriority(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	lru := NewWithPolicy(int64(12), NewPriorityPolicy(nil), callback)
+	lru.AddWithPriority("k1", String("v1"), 2, 0)
+	lru.AddWithPriority("k2", String("v2"), 1, 0)
+	lru.Add("k3", String("v3"))
+
+	// 优先级低的条目先被淘汰，即使它是最近加入的
+	lru.Add("k4", String("v4"))
+	lru.Add("k5", String("v5"))
+	if !reflect.DeepEqual([]string{"k3", "k4"}, keys) {
+		t.Fatalf("expected k3 and k4 to be evicted but got %s", keys)
+	}
+
+	// 没有优先级更低的条目时才淘汰优先级更高的条目
+	lru.AddWithPriority("k5", String("v5"), 3, 0)
+	lru.Add("k6", String("v6"))
+	lru.AddWithPriority("k7", String("v7"), 1, 0)
+	if !reflect.DeepEqual([]string{"k3", "k4", "k6", "k2"}, keys) {
+		t.Fatalf("expected k6 and k2 to be evicted but got %s", keys)
+	}
+
+	if !reflect.DeepEqual([]string{"k5", "k1", "k7"}, lru.Keys()) {
+		t.Fatalf("expected keys ordered by priority but got %s", lru.Keys())
+	}
+}
@@ -0,0 +1,56 @@
+package lru
+
+import (
+	"errors"
+
+	"cache/quota"
+)
+
+// 配额与共享的溢出池都不足，且淘汰其他条目后仍然无法容纳
+var ErrQuotaExceeded = errors.New("lru: quota exceeded")
+
+// 加入或修改条目时从q中申请增加的字节数，不足时按淘汰顺序淘汰本缓存中的其他条目直到申请成功，
+// 删除条目时归还。多个Cache可以使用同一个q，共享同一份配额
+func WithQuota(q *quota.Quota) Option {
+	return func(c *Cache) {
+		c.quota = q
+	}
+}
+
+// 为key申请开销为size的条目所需的配额，key已存在时只申请增加的部分。
+// 淘汰其他条目时固定key原有的条目，避免在修改前将其淘汰，调用方需持有锁
+func (c *Cache) claim(key string, size int64) error {
+	if c.quota == nil {
+		return nil
+	}
+
+	delta := size
+	keyValue, ok := c.cache[key]
+	if ok {
+		delta -= keyValue.cost()
+	}
+	if delta <= 0 {
+		c.quota.Release(-delta)
+		return nil
+	}
+
+	if ok {
+		pinned := keyValue.pinned
+		keyValue.pinned = true
+		defer func() { keyValue.pinned = pinned }()
+	}
+	for !c.quota.Acquire(delta) {
+		if !c.removeOldest(EvictedCapacity) {
+			return ErrQuotaExceeded
+		}
+	}
+
+	return nil
+}
+
+// 归还条目占用的配额，调用方需持有锁
+func (c *Cache) unclaim(size int64) {
+	if c.quota != nil {
+		c.quota.Release(size)
+	}
+}
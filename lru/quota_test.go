@@ -0,0 +1,78 @@
+package lru
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"cache/quota"
+)
+
+func TestCache_WithQuota(t *testing.T) {
+	pool := quota.NewPool(4)
+	noisy := New(0, nil, WithQuota(pool.NewQuota(4)))
+	quiet := New(0, nil, WithQuota(pool.NewQuota(4)))
+
+	// 超出独享配额的部分从溢出池中借用，溢出池耗尽后只淘汰自己的条目
+	noisy.Add("k1", String("v1"))
+	noisy.Add("k2", String("v2"))
+	noisy.Add("k3", String("v3"))
+	if noisy.Contains("k1") || noisy.Len() != 2 || pool.Free() != 0 {
+		t.Fatalf("expected noisy cache to evict its own entries")
+	}
+	quiet.Add("k1", String("v1"))
+	if !quiet.Contains("k1") {
+		t.Fatalf("expected reserved quota to be available")
+	}
+
+	// 修改条目时不会淘汰条目自身
+	if err := noisy.TryAdd("k3", String("v333"), 0); err != nil || noisy.Contains("k2") || !noisy.Contains("k3") {
+		t.Fatalf("expected k2 to be evicted for k3 but got %v", err)
+	}
+
+	// 淘汰所有其他条目后仍然无法容纳时返回ErrQuotaExceeded
+	if err := noisy.TryAdd("k4", String("0123456789"), 0); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded but got %v", err)
+	}
+
+	// 删除与清空条目时归还配额
+	noisy.Clear()
+	quiet.Remove("k1")
+	if pool.Free() != 4 {
+		t.Fatalf("expected all borrowed bytes to be returned but free is %d", pool.Free())
+	}
+}
+
+func TestCache_ConditionalWriteQuotaExceeded(t *testing.T) {
+	pool := quota.NewPool(0)
+	lru := New(0, nil, WithQuota(pool.NewQuota(4)))
+	lru.Add("k1", String("v1"))
+
+	// 配额不足时CompareAndSwap返回false，并保留原有的条目
+	_, version, _ := lru.GetWithVersion("k1")
+	if _, ok := lru.CompareAndSwap("k1", version, String("v123")); ok {
+		t.Fatalf("expected CompareAndSwap to fail")
+	}
+	if v, ok := lru.Get("k1"); !ok || v.(String) != "v1" {
+		t.Fatalf("expected k1 to keep v1")
+	}
+
+	// 配额不足时IncrBy返回ErrQuotaExceeded
+	counters := New(0, nil, WithQuota(pool.NewQuota(4)))
+	if _, err := counters.IncrBy("n", 1, true); !errors.Is(err, ErrQuotaExceeded) || counters.Contains("n") {
+		t.Fatalf("expected ErrQuotaExceeded but got %v", err)
+	}
+
+	// 配额不足的条目不计入加载的数量
+	var buf bytes.Buffer
+	source := New(0, nil)
+	source.Add("k2", String("v2"))
+	source.Add("k3", String("0123456789"))
+	if err := source.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	restored := New(0, nil, WithQuota(quota.NewPool(0).NewQuota(4)))
+	if n, err := restored.Load(&buf, decodeString); err != nil || n != 1 || restored.Contains("k3") {
+		t.Fatalf("expected 1 entry to be loaded but got %d, %v", n, err)
+	}
+}
@@ -0,0 +1,68 @@
+package lru
+
+import "cache/linkedlist"
+
+// 淘汰策略可以选择实现的接口，按从最近访问到最久未访问的顺序遍历key，f返回false时停止遍历。
+// 淘汰策略未实现该接口时，Cache按哈希表的顺序遍历条目
+type Ranger interface {
+	Range(f func(key string) bool)
+}
+
+// 按从最近访问到最久未访问的顺序遍历未过期的条目，f返回false时停止遍历。
+// 遍历过程中持有锁，因此不能在f中再调用Cache的方法
+func (c *Cache) Range(f func(key string, value Value) bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+	visit := func(key string) bool {
+		keyValue := c.cache[key]
+		if keyValue.expired(now) {
+			return true
+		}
+
+		return f(key, keyValue.value)
+	}
+
+	if ranger, ok := c.policy.(Ranger); ok {
+		ranger.Range(visit)
+		return
+	}
+
+	for key := range c.cache {
+		if !visit(key) {
+			return
+		}
+	}
+}
+
+// 按从最近访问到最久未访问的顺序获取所有未过期条目的key
+func (c *Cache) Keys() []string {
+	keys := make([]string, 0, c.Len())
+	c.Range(func(key string, value Value) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	return keys
+}
+
+// 从链表最前面开始遍历
+func (p *lruPolicy) Range(f func(key string) bool) {
+	for element := p.doubleLinkedList.Front(); element != nil; element = element.Next() {
+		if !f(element.Value) {
+			return
+		}
+	}
+}
+
+// 先遍历受保护段，再遍历试用段，与淘汰顺序相反
+func (p *slruPolicy) Range(f func(key string) bool) {
+	for _, segment := range []*linkedlist.List[segmentEntry]{p.protected, p.probation} {
+		for element := segment.Front(); element != nil; element = element.Next() {
+			if !f(element.Value.key) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCache_Keys(t *testing.T) {
+	lru := New(int64(0), nil)
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	lru.Add("k3", String("v3"))
+	lru.Get("k1")
+	lru.AddWithTTL("k4", String("v4"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	expect := []string{"k1", "k3", "k2"}
+	if keys := lru.Keys(); !reflect.DeepEqual(expect, keys) {
+		t.Fatalf("expect keys equals to %s but got %s", expect, keys)
+	}
+}
+
+func TestCache_Range(t *testing.T) {
+	lru := NewSLRU(int64(0), DefaultProtectedRatio, nil)
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	lru.Add("k3", String("v3"))
+	lru.Get("k2")
+
+	// 受保护段的条目排在试用段之前，遍历可以提前停止
+	keys := make([]string, 0)
+	lru.Range(func(key string, value Value) bool {
+		keys = append(keys, key+string(value.(String)))
+		return len(keys) < 2
+	})
+
+	expect := []string{"k2v2", "k3v3"}
+	if !reflect.DeepEqual(expect, keys) {
+		t.Fatalf("expect keys equals to %s but got %s", expect, keys)
+	}
+}
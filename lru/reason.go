@@ -0,0 +1,58 @@
+package lru
+
+// 条目离开缓存的原因
+type EvictionReason int
+
+const (
+	// 缓存大小或条目数量超出限制
+	EvictedCapacity EvictionReason = iota
+
+	// 被Remove等方法显式删除
+	EvictedRemoved
+
+	// 存活时间已过
+	EvictedExpired
+
+	// 被Clear或Purge清空
+	EvictedCleared
+
+	// 调整容量后被淘汰
+	EvictedResized
+)
+
+// 获取原因的名称
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictedCapacity:
+		return "capacity"
+	case EvictedRemoved:
+		return "removed"
+	case EvictedExpired:
+		return "expired"
+	case EvictedCleared:
+		return "cleared"
+	case EvictedResized:
+		return "resized"
+	default:
+		return "unknown"
+	}
+}
+
+// 设置带有离开原因的回调函数，与OnEvicted相互独立，两者都设置时都会被调用。
+// 与OnEvicted不同，Clear清空缓存时也会调用该回调函数。回调在持有锁的情况下执行，因此不能在回调中再调用Cache的方法
+func WithEvictionReason(onRemoved func(key string, value Value, reason EvictionReason)) Option {
+	return func(c *Cache) {
+		c.onRemoved = onRemoved
+	}
+}
+
+// 替换带有离开原因的回调函数并返回原有的回调函数，用于在已经实例化的Cache上组合功能，应在使用Cache之前调用
+func (c *Cache) SwapEvictionReason(onRemoved func(key string, value Value, reason EvictionReason)) func(key string, value Value, reason EvictionReason) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old := c.onRemoved
+	c.onRemoved = onRemoved
+
+	return old
+}
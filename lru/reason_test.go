@@ -0,0 +1,59 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCache_EvictionReason(t *testing.T) {
+	reasons := make(map[string]EvictionReason)
+	lru := New(int64(8), nil, WithEvictionReason(func(key string, value Value, reason EvictionReason) {
+		reasons[key] = reason
+	}))
+
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	lru.Add("k3", String("v3"))
+	lru.Remove("k2")
+	lru.AddWithTTL("k4", String("v4"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	lru.Get("k4")
+	lru.Add("k5", String("v5"))
+	lru.Resize(int64(4))
+	lru.Add("k6", String("v"))
+	lru.Clear()
+
+	expect := map[string]EvictionReason{
+		"k1": EvictedCapacity,
+		"k2": EvictedRemoved,
+		"k4": EvictedExpired,
+		"k3": EvictedResized,
+		"k5": EvictedCapacity,
+		"k6": EvictedCleared,
+	}
+	if !reflect.DeepEqual(expect, reasons) {
+		t.Fatalf("expected %v but got %v", expect, reasons)
+	}
+	if EvictedExpired.String() != "expired" {
+		t.Fatalf("unexpected name %s", EvictedExpired)
+	}
+}
+
+func TestCache_SwapEvictionReason(t *testing.T) {
+	var first, second []string
+	lru := New(int64(4), nil, WithEvictionReason(func(key string, value Value, reason EvictionReason) {
+		first = append(first, key)
+	}))
+
+	// 替换后只调用新的回调函数，并返回原有的回调函数
+	old := lru.SwapEvictionReason(func(key string, value Value, reason EvictionReason) {
+		second = append(second, key)
+	})
+	lru.Add("k1", String("v1"))
+	lru.Remove("k1")
+	old("k0", nil, EvictedRemoved)
+	if !reflect.DeepEqual(first, []string{"k0"}) || !reflect.DeepEqual(second, []string{"k1"}) {
+		t.Fatalf("unexpected callbacks %v, %v", first, second)
+	}
+}
@@ -0,0 +1,142 @@
+package lru
+
+import "cache/linkedlist"
+
+// 受保护段默认占缓存容量的比例
+const DefaultProtectedRatio = 0.8
+
+// 实例化分段LRU（SLRU）cache，新条目进入试用段，再次被访问时才晋升到受保护段，
+// protectedRatio为受保护段占缓存容量的比例
+func NewSLRU(capacity int64, protectedRatio float64, onEvicted func(string, Value), opts ...Option) *Cache {
+	return NewWithPolicy(capacity, newSLRUPolicy(capacity, protectedRatio), onEvicted, opts...)
+}
+
+// 分段LRU的链表节点
+type segmentEntry struct {
+	key string
+
+	// 条目占用的字节数
+	cost int64
+
+	// 条目是否位于受保护段
+	protected bool
+}
+
+// 分段LRU淘汰策略，优先淘汰试用段中最近最少访问的条目
+type slruPolicy struct {
+	// 试用段，越靠前越是最近访问的
+	probation *linkedlist.List[segmentEntry]
+
+	// 受保护段，越靠前越是最近访问的
+	protected *linkedlist.List[segmentEntry]
+
+	// 受保护段占缓存容量的比例
+	protectedRatio float64
+
+	// 受保护段的最大容量（单位为字节）
+	protectedCapacity int64
+
+	// 受保护段已使用的空间（单位为字节）
+	protectedSize int64
+
+	// 存储key与链表节点映射关系的哈希表
+	elements map[string]*linkedlist.Element[segmentEntry]
+}
+
+// 实例化分段LRU淘汰策略
+func newSLRUPolicy(capacity int64, protectedRatio float64) *slruPolicy {
+	return &slruPolicy{
+		probation:         linkedlist.New[segmentEntry](),
+		protected:         linkedlist.New[segmentEntry](),
+		protectedRatio:    protectedRatio,
+		protectedCapacity: int64(float64(capacity) * protectedRatio),
+		elements:          make(map[string]*linkedlist.Element[segmentEntry]),
+	}
+}
+
+// 试用段的节点晋升到受保护段，受保护段的节点移动到链表最前面
+func (p *slruPolicy) OnGet(key string) {
+	if element, ok := p.elements[key]; ok {
+		p.touch(element)
+	}
+}
+
+// 新条目插入到试用段最前面，被修改的条目视为一次访问
+func (p *slruPolicy) OnAdd(key string, cost int64) {
+	if element, ok := p.elements[key]; ok {
+		node := &element.Value
+		if node.protected {
+			p.protectedSize += cost - node.cost
+		}
+		node.cost = cost
+		p.touch(element)
+		return
+	}
+
+	p.elements[key] = p.probation.PushFront(segmentEntry{key: key, cost: cost})
+}
+
+// 试用段为空时才淘汰受保护段的条目
+func (p *slruPolicy) Victim() (string, bool) {
+	oldest := p.probation.Back()
+	if oldest == nil {
+		oldest = p.protected.Back()
+	}
+	if oldest == nil {
+		return "", false
+	}
+
+	return oldest.Value.key, true
+}
+
+// 从所在段与哈希表中删除节点
+func (p *slruPolicy) Remove(key string) {
+	element, ok := p.elements[key]
+	if !ok {
+		return
+	}
+
+	node := &element.Value
+	if node.protected {
+		p.protected.Remove(element)
+		p.protectedSize -= node.cost
+	} else {
+		p.probation.Remove(element)
+	}
+	delete(p.elements, key)
+}
+
+// 处理被访问的节点
+func (p *slruPolicy) touch(element *linkedlist.Element[segmentEntry]) {
+	node := &element.Value
+	if node.protected {
+		p.protected.MoveToFront(element)
+		return
+	}
+
+	// 从试用段晋升到受保护段
+	p.probation.Remove(element)
+	node.protected = true
+	p.protectedSize += node.cost
+	p.protected.PushFrontElement(element)
+
+	p.demote()
+}
+
+// 按新的缓存容量调整受保护段的容量
+func (p *slruPolicy) resize(capacity int64) {
+	p.protectedCapacity = int64(float64(capacity) * p.protectedRatio)
+	p.demote()
+}
+
+// 受保护段超过容量时，将其最久未访问的节点降级回试用段的最前面
+func (p *slruPolicy) demote() {
+	for p.protected.Len() > 1 && p.protectedSize > p.protectedCapacity {
+		oldest := p.protected.Back()
+		demoted := &oldest.Value
+		p.protected.Remove(oldest)
+		demoted.protected = false
+		p.protectedSize -= demoted.cost
+		p.probation.PushFrontElement(oldest)
+	}
+}
@@ -0,0 +1,71 @@
+package lru
+
+import (
+	"strconv"
+	"testing"
+)
+
+// 获取分段LRU淘汰策略中key对应的节点
+func segmentOf(c *Cache, key string) *segmentEntry {
+	return &c.policy.(*slruPolicy).elements[key].Value
+}
+
+func TestSLRU_Promotion(t *testing.T) {
+	slru := NewSLRU(int64(0), DefaultProtectedRatio, nil)
+	slru.Add("key1", String("value1"))
+
+	if segmentOf(slru, "key1").protected {
+		t.Fatalf("new entry should land in probation")
+	}
+
+	slru.Get("key1")
+	if !segmentOf(slru, "key1").protected || slru.policy.(*slruPolicy).protectedSize != int64(len("key1value1")) {
+		t.Fatalf("key1 should be promoted to protected on second hit")
+	}
+	if slru.Len() != 1 {
+		t.Fatalf("expected 1 entry but got %d", slru.Len())
+	}
+}
+
+func TestSLRU_ScanResistance(t *testing.T) {
+	slru := NewSLRU(int64(40), 0.5, nil)
+
+	// k1与k2被再次访问，晋升到受保护段
+	for _, key := range []string{"k1", "k2"} {
+		slru.Add(key, String("vv"))
+		slru.Get(key)
+	}
+
+	// 扫描key只会在试用段中相互淘汰
+	for i := 0; i < 20; i++ {
+		slru.Add("s"+strconv.Itoa(i), String("v"))
+	}
+
+	for _, key := range []string{"k1", "k2"} {
+		if _, ok := slru.Get(key); !ok {
+			t.Fatalf("protected key %s should survive the scan", key)
+		}
+	}
+	if slru.size > 40 {
+		t.Fatalf("expected size <= 40 but got %d", slru.size)
+	}
+}
+
+func TestSLRU_Demotion(t *testing.T) {
+	slru := NewSLRU(int64(0), 0, nil)
+	slru.policy.(*slruPolicy).protectedCapacity = 8
+	for _, key := range []string{"k1", "k2", "k3"} {
+		slru.Add(key, String("vv"))
+		slru.Get(key)
+	}
+
+	// 受保护段只能容纳两个条目，k1被降级回试用段
+	if segmentOf(slru, "k1").protected || slru.policy.(*slruPolicy).protectedSize != 8 {
+		t.Fatalf("k1 should be demoted to probation")
+	}
+
+	slru.Remove("k2")
+	if slru.policy.(*slruPolicy).protectedSize != 4 || slru.size != 8 {
+		t.Fatalf("expected protected size 4 and size 8 but got %d and %d", slru.policy.(*slruPolicy).protectedSize, slru.size)
+	}
+}
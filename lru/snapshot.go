@@ -0,0 +1,213 @@
+package lru
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 快照文件的魔数与版本
+const (
+	snapshotMagic   = "LRUS"
+	snapshotVersion = 1
+
+	// 快照中单个key或value的最大字节数，避免损坏的快照导致分配过多内存
+	maxSnapshotBytes = 1 << 30
+)
+
+// 快照文件格式不正确
+var ErrBadSnapshot = errors.New("lru: bad snapshot")
+
+// 快照中的一个条目
+type snapshotRecord struct {
+	key    string
+	value  []byte
+	expire time.Time
+}
+
+// 将所有未过期的条目写入path，按从最久未访问到最近访问的顺序写入，加载时可以恢复淘汰顺序。
+// value需要实现encoding.BinaryMarshaler接口。先写入临时文件再重命名，写入失败时不会破坏已有的快照
+func (c *Cache) SaveToFile(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := c.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// 从path加载快照，decode将value的字节转换为Value，返回加载的条目数量
+func (c *Cache) LoadFromFile(path string, decode func(data []byte) (Value, error)) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return c.Load(f, decode)
+}
+
+// 将所有未过期的条目写入w，格式与SaveToFile相同
+func (c *Cache) Save(w io.Writer) error {
+	records, err := c.snapshot()
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(snapshotMagic)
+	bw.WriteByte(snapshotVersion)
+
+	buf := make([]byte, 0, binary.MaxVarintLen64)
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+
+		var expire int64
+		if !record.expire.IsZero() {
+			expire = record.expire.UnixNano()
+		}
+
+		bw.Write(binary.AppendUvarint(buf[:0], uint64(len(record.key))))
+		bw.WriteString(record.key)
+		bw.Write(binary.AppendUvarint(buf[:0], uint64(len(record.value))))
+		bw.Write(record.value)
+		bw.Write(binary.AppendVarint(buf[:0], expire))
+	}
+
+	return bw.Flush()
+}
+
+// 按从最近访问到最久未访问的顺序获取所有未过期条目的快照
+func (c *Cache) snapshot() ([]snapshotRecord, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+	records := make([]snapshotRecord, 0, len(c.cache))
+
+	var err error
+	visit := func(key string) bool {
+		keyValue := c.cache[key]
+		if keyValue.expired(now) {
+			return true
+		}
+
+		marshaler, ok := keyValue.value.(encoding.BinaryMarshaler)
+		if !ok {
+			err = fmt.Errorf("lru: value of %q does not implement encoding.BinaryMarshaler", key)
+			return false
+		}
+
+		var data []byte
+		if data, err = marshaler.MarshalBinary(); err != nil {
+			return false
+		}
+		records = append(records, snapshotRecord{key: key, value: data, expire: keyValue.expire})
+
+		return true
+	}
+
+	if ranger, ok := c.policy.(Ranger); ok {
+		ranger.Range(visit)
+	} else {
+		for key := range c.cache {
+			if !visit(key) {
+				break
+			}
+		}
+	}
+
+	return records, err
+}
+
+// 从r加载快照并加入缓存，已过期与过大或配额不足而无法加入的条目被跳过，返回加载的条目数量
+func (c *Cache) Load(r io.Reader, decode func(data []byte) (Value, error)) (int, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(snapshotMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil || string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return 0, ErrBadSnapshot
+	}
+	if header[len(snapshotMagic)] != snapshotVersion {
+		return 0, fmt.Errorf("%w: unsupported version %d", ErrBadSnapshot, header[len(snapshotMagic)])
+	}
+
+	now := c.now()
+	loaded := 0
+	for {
+		key, err := readBytes(br)
+		if err == io.EOF {
+			return loaded, nil
+		}
+		if err != nil {
+			return loaded, err
+		}
+
+		data, err := readBytes(br)
+		if err != nil {
+			return loaded, ErrBadSnapshot
+		}
+
+		expireNano, err := binary.ReadVarint(br)
+		if err != nil {
+			return loaded, ErrBadSnapshot
+		}
+
+		var expire time.Time
+		if expireNano != 0 {
+			expire = time.Unix(0, expireNano)
+			if now.After(expire) {
+				continue
+			}
+		}
+
+		value, err := decode(data)
+		if err != nil {
+			return loaded, err
+		}
+
+		c.mu.Lock()
+		err = c.add(string(key), value, expire)
+		c.mu.Unlock()
+		if err == nil {
+			loaded++
+		}
+	}
+}
+
+// 读取带有长度前缀的字节序列，在条目开始处读到文件末尾时返回io.EOF
+func readBytes(br *bufio.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(br)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil || n > maxSnapshotBytes {
+		return nil, ErrBadSnapshot
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(br, b); err != nil {
+		return nil, ErrBadSnapshot
+	}
+
+	return b, nil
+}
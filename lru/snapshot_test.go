@@ -0,0 +1,85 @@
+package lru
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 实现encoding.BinaryMarshaler接口
+func (s String) MarshalBinary() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// 将快照中的字节转换为String
+func decodeString(data []byte) (Value, error) {
+	return String(data), nil
+}
+
+func TestCache_SaveToFile(t *testing.T) {
+	lru := New(int64(0), nil)
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("v2"))
+	lru.AddWithTTL("k3", String("v3"), time.Hour)
+	lru.AddWithTTL("k4", String("v4"), time.Nanosecond)
+	lru.Get("k1")
+	time.Sleep(time.Millisecond)
+
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	if err := lru.SaveToFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := New(int64(0), nil)
+	n, err := restored.LoadFromFile(path, decodeString)
+	if err != nil || n != 3 {
+		t.Fatalf("expected 3 loaded entries but got %d, %v", n, err)
+	}
+
+	// 恢复后的淘汰顺序与保存时一致，过期条目被跳过
+	expect := []string{"k1", "k3", "k2"}
+	if keys := restored.Keys(); !reflect.DeepEqual(expect, keys) {
+		t.Fatalf("expect keys equals to %s but got %s", expect, keys)
+	}
+	if v, ok := restored.Get("k3"); !ok || v.(String) != "v3" {
+		t.Fatalf("expected k3 to be restored")
+	}
+}
+
+func TestCache_LoadBadSnapshot(t *testing.T) {
+	lru := New(int64(0), nil)
+	lru.Add("k1", String("v1"))
+
+	var buf bytes.Buffer
+	if err := lru.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// 截断的快照与错误的魔数都会返回ErrBadSnapshot
+	truncated := buf.Bytes()[:buf.Len()-2]
+	if _, err := New(int64(0), nil).Load(bytes.NewReader(truncated), decodeString); !errors.Is(err, ErrBadSnapshot) {
+		t.Fatalf("expected ErrBadSnapshot but got %v", err)
+	}
+	if _, err := New(int64(0), nil).Load(bytes.NewReader([]byte("XXXX\x01")), decodeString); !errors.Is(err, ErrBadSnapshot) {
+		t.Fatalf("expected ErrBadSnapshot but got %v", err)
+	}
+}
+
+// 未实现encoding.BinaryMarshaler接口的value
+type opaque int
+
+func (opaque) Len() int {
+	return 8
+}
+
+func TestCache_SaveUnmarshalable(t *testing.T) {
+	lru := New(int64(0), nil)
+	lru.Add("k1", opaque(1))
+
+	if err := lru.Save(&bytes.Buffer{}); err == nil {
+		t.Fatalf("expected an error for values without MarshalBinary")
+	}
+}
@@ -0,0 +1,68 @@
+package lru
+
+import "sync/atomic"
+
+// 缓存的统计信息
+type Stats struct {
+	// 命中次数
+	Hits int64
+
+	// 未命中次数，包括查找到已过期条目的情况
+	Misses int64
+
+	// 新增条目的次数
+	Adds int64
+
+	// 修改已有条目的次数
+	Updates int64
+
+	// 因缓存容量不足而被淘汰的条目数量
+	Evictions int64
+
+	// 未命中的key最近因容量不足被淘汰的次数，即更大的容量本可以多命中的次数，需开启WithGhosts
+	GhostHits int64
+
+	// 已使用的缓存空间（单位为字节）
+	Bytes int64
+
+	// 缓存的条目数量
+	Entries int64
+}
+
+// 命中率，没有查找记录时返回0
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(total)
+}
+
+// 使用原子操作维护的计数器，读取时无需持有锁
+type counters struct {
+	hits      atomic.Int64
+	misses    atomic.Int64
+	adds      atomic.Int64
+	updates   atomic.Int64
+	evictions atomic.Int64
+	ghostHits atomic.Int64
+}
+
+// 获取缓存的统计信息
+func (c *Cache) Stats() Stats {
+	c.mu.RLock()
+	bytes, entries := c.size, int64(len(c.cache))
+	c.mu.RUnlock()
+
+	return Stats{
+		Hits:      c.counters.hits.Load(),
+		Misses:    c.counters.misses.Load(),
+		Adds:      c.counters.adds.Load(),
+		Updates:   c.counters.updates.Load(),
+		Evictions: c.counters.evictions.Load(),
+		GhostHits: c.counters.ghostHits.Load(),
+		Bytes:     bytes,
+		Entries:   entries,
+	}
+}
@@ -0,0 +1,33 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCache_Stats(t *testing.T) {
+	lru := New(int64(8), nil)
+	lru.Add("k1", String("vv"))
+	lru.Add("k1", String("v1"))
+	lru.Add("k2", String("vv"))
+	lru.Add("k3", String("vv"))
+	lru.Get("k3")
+	lru.Get("k1")
+	lru.Peek("k2")
+
+	expect := Stats{Hits: 1, Misses: 1, Adds: 3, Updates: 1, Evictions: 1, Bytes: 8, Entries: 2}
+	stats := lru.Stats()
+	if !reflect.DeepEqual(expect, stats) {
+		t.Fatalf("expect stats %+v but got %+v", expect, stats)
+	}
+	if stats.HitRatio() != 0.5 {
+		t.Fatalf("expect hit ratio 0.5 but got %f", stats.HitRatio())
+	}
+
+	// 主动删除与清空不计入淘汰次数
+	lru.Remove("k2")
+	lru.Purge()
+	if stats := lru.Stats(); stats.Evictions != 1 || stats.Entries != 0 || stats.Bytes != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
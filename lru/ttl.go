@@ -0,0 +1,99 @@
+package lru
+
+import "time"
+
+// 新增或修改条目，并设置其存活时间，ttl小于等于0时表示永不过期
+func (c *Cache) AddWithTTL(key string, value Value, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.add(key, value, expireAt(c.now(), ttl))
+}
+
+// 与Get相同，同时返回条目的过期时间，零值表示永不过期
+func (c *Cache) GetWithExpiration(key string) (value Value, expire time.Time, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keyValue, ok := c.lookup(key, c.now())
+	if !ok {
+		c.miss(key)
+		return nil, time.Time{}, false
+	}
+
+	c.policy.OnGet(key)
+	c.hit(keyValue, keyValue.value)
+
+	return keyValue.value, keyValue.expire, true
+}
+
+// 重新设置key对应条目的存活时间而不修改value，ttl小于等于0时表示永不过期，
+// promote为true时同时视为一次访问。返回key是否存在于缓存中且未过期
+func (c *Cache) Touch(key string, ttl time.Duration, promote bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	keyValue, ok := c.lookup(key, now)
+	if !ok {
+		return false
+	}
+
+	keyValue.expire = expireAt(now, ttl)
+	if promote {
+		c.policy.OnGet(key)
+	}
+
+	return true
+}
+
+// 清除所有已过期的条目，返回被清除的条目数量
+func (c *Cache) RemoveExpired() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	removed := 0
+
+	for _, keyValue := range c.cache {
+		if keyValue.expired(now) {
+			c.removeEntry(keyValue, EvictedExpired)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+// 获取未过期的条目数量，需要遍历所有条目但不会清除过期条目
+func (c *Cache) LiveLen() int {
+	n, _ := c.live()
+	return n
+}
+
+// 获取未过期的条目占用的缓存空间（单位为字节），需要遍历所有条目但不会清除过期条目
+func (c *Cache) LiveSize() int64 {
+	_, size := c.live()
+	return size
+}
+
+// 统计未过期的条目数量与占用的缓存空间
+func (c *Cache) live() (n int, size int64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+	for _, keyValue := range c.cache {
+		if !keyValue.expired(now) {
+			n++
+			size += keyValue.cost()
+		}
+	}
+
+	return n, size
+}
+
+// 判断条目在now时刻是否已过期
+func (e *entry) expired(now time.Time) bool {
+	return !e.expire.IsZero() && now.After(e.expire)
+}
@@ -0,0 +1,110 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"cache/timesource"
+)
+
+func TestCache_AddWithTTL(t *testing.T) {
+	clock := timesource.NewFake(time.Now())
+	lru := New(int64(0), nil, WithClock(clock))
+	lru.AddWithTTL("key1", String("value1"), time.Millisecond)
+	lru.AddWithTTL("key2", String("value2"), time.Hour)
+	lru.Add("key3", String("value3"))
+
+	if _, ok := lru.Get("key1"); !ok {
+		t.Fatalf("cache hit key1 failed")
+	}
+
+	clock.Advance(5 * time.Millisecond)
+
+	if _, ok := lru.Get("key1"); ok {
+		t.Fatalf("key1 should be expired")
+	}
+	if _, ok := lru.Get("key2"); !ok {
+		t.Fatalf("cache hit key2 failed")
+	}
+	if lru.Len() != 2 || lru.size != int64(len("key2value2key3value3")) {
+		t.Fatalf("expected 2 entries of 20 bytes but got %d entries of %d bytes", lru.Len(), lru.size)
+	}
+}
+
+func TestCache_RemoveExpired(t *testing.T) {
+	clock := timesource.NewFake(time.Now())
+	lru := New(int64(0), nil, WithClock(clock))
+	lru.AddWithTTL("key1", String("value1"), time.Millisecond)
+	lru.AddWithTTL("key2", String("value2"), time.Millisecond)
+	lru.AddWithTTL("key3", String("value3"), time.Hour)
+
+	clock.Advance(5 * time.Millisecond)
+
+	if n := lru.RemoveExpired(); n != 2 {
+		t.Fatalf("expected 2 expired entries but got %d", n)
+	}
+	if lru.Len() != 1 || lru.size != int64(len("key3value3")) {
+		t.Fatalf("expected 1 entry of 10 bytes but got %d entries of %d bytes", lru.Len(), lru.size)
+	}
+}
+
+func TestCache_LiveLen(t *testing.T) {
+	clock := timesource.NewFake(time.Now())
+	lru := New(int64(0), nil, WithClock(clock))
+	lru.AddWithTTL("key1", String("value1"), time.Nanosecond)
+	lru.Add("key2", String("value2"))
+	clock.Advance(time.Millisecond)
+
+	// 过期条目尚未被清除，但不计入LiveLen与LiveSize
+	if lru.Len() != 2 || lru.Size() != int64(len("key1value1key2value2")) {
+		t.Fatalf("expected 2 entries of 20 bytes but got %d entries of %d bytes", lru.Len(), lru.Size())
+	}
+	if lru.LiveLen() != 1 || lru.LiveSize() != int64(len("key2value2")) {
+		t.Fatalf("expected 1 live entry of 10 bytes but got %d entries of %d bytes", lru.LiveLen(), lru.LiveSize())
+	}
+}
+
+func TestCache_Touch(t *testing.T) {
+	clock := timesource.NewFake(time.Now())
+	lru := New(int64(8), nil, WithClock(clock))
+	lru.AddWithTTL("k1", String("v1"), 10*time.Millisecond)
+	lru.Add("k2", String("v2"))
+
+	// 延长存活时间并提升为最近访问
+	if !lru.Touch("k1", time.Hour, true) || lru.Touch("unknown", time.Hour, true) {
+		t.Fatalf("Touch failed")
+	}
+	clock.Advance(20 * time.Millisecond)
+	if _, ok := lru.Peek("k1"); !ok {
+		t.Fatalf("expected k1 to live after Touch")
+	}
+
+	lru.Add("k3", String("v3"))
+	if _, ok := lru.Peek("k2"); ok {
+		t.Fatalf("expected k2 to be evicted after k1 was promoted")
+	}
+
+	lru.Touch("k1", time.Nanosecond, false)
+	clock.Advance(time.Millisecond)
+	if lru.Touch("k1", time.Hour, false) {
+		t.Fatalf("expected expired k1 not to be touched")
+	}
+}
+
+func TestCache_GetWithExpiration(t *testing.T) {
+	lru := New(int64(0), nil)
+	before := time.Now()
+	lru.AddWithTTL("k1", String("v1"), time.Hour)
+	lru.Add("k2", String("v2"))
+
+	v, expire, ok := lru.GetWithExpiration("k1")
+	if !ok || v != String("v1") || expire.Before(before.Add(time.Hour)) || expire.After(time.Now().Add(time.Hour)) {
+		t.Fatalf("unexpected expiration %v", expire)
+	}
+	if _, expire, ok := lru.GetWithExpiration("k2"); !ok || !expire.IsZero() {
+		t.Fatalf("expected zero expiration but got %v", expire)
+	}
+	if _, _, ok := lru.GetWithExpiration("unknown"); ok {
+		t.Fatalf("cache miss failed")
+	}
+}
@@ -0,0 +1,115 @@
+package lru
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+)
+
+// 字节切片类型的Value
+type BytesValue []byte
+
+// 获取数据的长度
+func (v BytesValue) Len() int {
+	return len(v)
+}
+
+// 实现encoding.BinaryMarshaler接口，用于保存快照
+func (v BytesValue) MarshalBinary() ([]byte, error) {
+	return v, nil
+}
+
+// 字符串类型的Value
+type StringValue string
+
+// 获取字符串的长度
+func (v StringValue) Len() int {
+	return len(v)
+}
+
+// 实现encoding.BinaryMarshaler接口，用于保存快照
+func (v StringValue) MarshalBinary() ([]byte, error) {
+	return []byte(v), nil
+}
+
+// 整数类型的Value，固定占用8个字节
+type Int64Value int64
+
+// 获取整数占用的字节数
+func (v Int64Value) Len() int {
+	return 8
+}
+
+// 实现encoding.BinaryMarshaler接口，用于保存快照
+func (v Int64Value) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint64(nil, uint64(v)), nil
+}
+
+// 包装任意可被gob编码的值的Value，以gob编码后的长度作为开销
+type GobValue struct {
+	// 被包装的值
+	V any
+
+	// gob编码后的长度
+	size int
+}
+
+// 包装v，实例化时编码一次以计算开销，v无法被gob编码时返回错误
+func NewGobValue(v any) (*GobValue, error) {
+	data, err := encodeGob(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GobValue{V: v, size: len(data)}, nil
+}
+
+// 获取gob编码后的长度
+func (v *GobValue) Len() int {
+	return v.size
+}
+
+// 实现encoding.BinaryMarshaler接口，用于保存快照
+func (v *GobValue) MarshalBinary() ([]byte, error) {
+	return encodeGob(v.V)
+}
+
+// 使用gob编码v
+func encodeGob(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// 将快照中的字节转换为BytesValue，可作为LoadFromFile的decode参数
+func DecodeBytes(data []byte) (Value, error) {
+	return BytesValue(data), nil
+}
+
+// 将快照中的字节转换为StringValue
+func DecodeString(data []byte) (Value, error) {
+	return StringValue(data), nil
+}
+
+// 将快照中的字节转换为Int64Value
+func DecodeInt64(data []byte) (Value, error) {
+	if len(data) != 8 {
+		return nil, fmt.Errorf("lru: expected 8 bytes for Int64Value but got %d", len(data))
+	}
+
+	return Int64Value(binary.BigEndian.Uint64(data)), nil
+}
+
+// 将快照中的字节解码为T并包装为GobValue，例如LoadFromFile(path, DecodeGob[User])
+func DecodeGob[T any](data []byte) (Value, error) {
+	var v T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return &GobValue{V: v, size: len(data)}, nil
+}
@@ -0,0 +1,60 @@
+package lru
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestValues_Len(t *testing.T) {
+	lru := New(int64(0), nil)
+	lru.Add("b", BytesValue("123"))
+	lru.Add("s", StringValue("12345"))
+	lru.Add("i", Int64Value(42))
+
+	if lru.Size() != int64(1+3+1+5+1+8) {
+		t.Fatalf("unexpected size %d", lru.Size())
+	}
+}
+
+type user struct {
+	Name string
+	Age  int
+}
+
+func TestGobValue(t *testing.T) {
+	v, err := NewGobValue(user{Name: "Tom", Age: 30})
+	if err != nil || v.Len() == 0 {
+		t.Fatalf("failed to wrap value: %v", err)
+	}
+
+	if _, err := NewGobValue(func() {}); err == nil {
+		t.Fatalf("expected error for value that cannot be gob encoded")
+	}
+
+	lru := New(int64(0), nil)
+	lru.Add("tom", v)
+	lru.Add("n", Int64Value(-7))
+
+	var buf bytes.Buffer
+	if err := lru.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	decode := func(data []byte) (Value, error) {
+		if len(data) == 8 {
+			return DecodeInt64(data)
+		}
+		return DecodeGob[user](data)
+	}
+	restored := New(int64(0), nil)
+	if _, err := restored.Load(&buf, decode); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := restored.Get("tom"); !ok || got.(*GobValue).V != (user{Name: "Tom", Age: 30}) || got.Len() != v.Len() {
+		t.Fatalf("unexpected restored value %v", got)
+	}
+	if got, ok := restored.Get("n"); !ok || got.(Int64Value) != -7 {
+		t.Fatalf("unexpected restored value %v", got)
+	}
+}
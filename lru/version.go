@@ -0,0 +1,49 @@
+package lru
+
+import "time"
+
+// 查找key对应的value及其版本号，条目每次被新增或修改时都会获得一个更大的版本号
+func (c *Cache) GetWithVersion(key string) (value Value, version uint64, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keyValue, ok := c.lookup(key, c.now())
+	if !ok {
+		c.miss(key)
+		return nil, 0, false
+	}
+
+	c.policy.OnGet(key)
+	c.hit(keyValue, keyValue.value)
+
+	return keyValue.value, keyValue.version, true
+}
+
+// 只有key当前的版本号等于expected时才将其修改为value，并保留原来的过期时间；
+// expected为0时只有key不存在才加入value。成功时返回新的版本号，ok为false表示版本号不匹配，
+// 或value过大、配额不足而没有写入，此时key原有的条目保持不变
+func (c *Cache) CompareAndSwap(key string, expected uint64, value Value) (version uint64, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	keyValue, exists := c.lookup(key, c.now())
+	switch {
+	case !exists && expected == 0:
+		err = c.update(key, value, time.Time{})
+	case exists && keyValue.version == expected:
+		err = c.update(key, value, keyValue.expire)
+	default:
+		return 0, false
+	}
+	if err != nil {
+		return 0, false
+	}
+
+	// 加入的条目可能立即被淘汰
+	if keyValue, ok := c.cache[key]; ok {
+		return keyValue.version, true
+	}
+
+	return c.version, true
+}
@@ -0,0 +1,61 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCache_CompareAndSwap(t *testing.T) {
+	lru := New(int64(0), nil)
+	if _, ok := lru.CompareAndSwap("k1", 1, String("v1")); ok {
+		t.Fatalf("expected CompareAndSwap on missing key with non-zero version to fail")
+	}
+
+	version, ok := lru.CompareAndSwap("k1", 0, String("v1"))
+	if !ok {
+		t.Fatalf("expected insert with version 0 to succeed")
+	}
+	if _, ok := lru.CompareAndSwap("k1", 0, String("v1")); ok {
+		t.Fatalf("expected insert on existing key to fail")
+	}
+
+	v, got, ok := lru.GetWithVersion("k1")
+	if !ok || got != version || v != String("v1") {
+		t.Fatalf("expected version %d but got %d", version, got)
+	}
+
+	next, ok := lru.CompareAndSwap("k1", version, String("v2"))
+	if !ok || next <= version {
+		t.Fatalf("expected version to increase from %d but got %d", version, next)
+	}
+	if _, ok := lru.CompareAndSwap("k1", version, String("v3")); ok {
+		t.Fatalf("expected stale version to be rejected")
+	}
+}
+
+func TestCache_CompareAndSwapConcurrent(t *testing.T) {
+	lru := New(int64(0), nil)
+	lru.Add("counter", String(""))
+
+	// 每个goroutine乐观地追加一个字符，冲突时重试
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				for {
+					v, version, _ := lru.GetWithVersion("counter")
+					if _, ok := lru.CompareAndSwap("counter", version, v.(String)+"x"); ok {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if v, _ := lru.Get("counter"); len(v.(String)) != 800 {
+		t.Fatalf("expected 800 appends but got %d", len(v.(String)))
+	}
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"cache/lru"
+)
+
+// 可提供统计信息的缓存，lru.Cache实现了该接口
+type StatsSource interface {
+	Stats() lru.Stats
+}
+
+// 将缓存的统计信息导出为Prometheus指标，每次采集时读取一次统计信息
+type Collector struct {
+	source StatsSource
+
+	hits      *prometheus.Desc
+	misses    *prometheus.Desc
+	evictions *prometheus.Desc
+	hitRatio  *prometheus.Desc
+	bytes     *prometheus.Desc
+	entries   *prometheus.Desc
+}
+
+// 实例化Collector，name作为cache标签的值，用于区分同一进程中的多个缓存
+func NewCollector(name string, source StatsSource) *Collector {
+	labels := prometheus.Labels{"cache": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("cache", "", metric), help, nil, labels)
+	}
+
+	return &Collector{
+		source:    source,
+		hits:      desc("hits_total", "Number of cache hits."),
+		misses:    desc("misses_total", "Number of cache misses."),
+		evictions: desc("evictions_total", "Number of entries evicted due to capacity."),
+		hitRatio:  desc("hit_ratio", "Ratio of hits to lookups since the cache was created."),
+		bytes:     desc("bytes", "Bytes currently used by cached entries."),
+		entries:   desc("entries", "Number of entries currently in the cache."),
+	}
+}
+
+// 实现prometheus.Collector接口
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.evictions
+	ch <- c.hitRatio
+	ch <- c.bytes
+	ch <- c.entries
+}
+
+// 实现prometheus.Collector接口，淘汰速率可通过对evictions_total使用rate()得到
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.source.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions))
+	ch <- prometheus.MustNewConstMetric(c.hitRatio, prometheus.GaugeValue, stats.HitRatio())
+	ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.GaugeValue, float64(stats.Bytes))
+	ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(stats.Entries))
+}
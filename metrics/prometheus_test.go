@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"cache/lru"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+func TestCollector(t *testing.T) {
+	c := lru.New(int64(0), nil)
+	c.Add("k1", String("vv"))
+	c.Get("k1")
+	c.Get("k2")
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewCollector("test", c))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		metric := family.GetMetric()[0]
+		if metric.GetLabel()[0].GetValue() != "test" {
+			t.Fatalf("expected cache label test on %s", family.GetName())
+		}
+		if counter := metric.GetCounter(); counter != nil {
+			values[family.GetName()] = counter.GetValue()
+		} else {
+			values[family.GetName()] = metric.GetGauge().GetValue()
+		}
+	}
+
+	expect := map[string]float64{
+		"cache_hits_total":      1,
+		"cache_misses_total":    1,
+		"cache_evictions_total": 0,
+		"cache_hit_ratio":       0.5,
+		"cache_bytes":           4,
+		"cache_entries":         1,
+	}
+	for name, value := range expect {
+		if values[name] != value {
+			t.Fatalf("expected %s to be %v but got %v", name, value, values[name])
+		}
+	}
+}
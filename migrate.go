@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cache/cachepb"
+)
+
+// 将本地缓存中不再属于本节点的key推送给新的所属节点，并从本地缓存中移除，返回推送成功的条目数量。
+// 应在节点变化（例如替换PeerPicker中的节点）后调用，使新的所属节点无需从数据源重新加载。
+// 所属节点不支持PeerPusher或推送失败的key同样会被移除，避免本节点继续返回旧的数据
+func (g *Group) Migrate(ctx context.Context) (int, error) {
+	if g.peers == nil {
+		return 0, nil
+	}
+
+	var errs []error
+	n := 0
+	for _, key := range g.mainCache.Keys() {
+		peer, ok := g.peers.PickPeer(key)
+		if !ok {
+			continue
+		}
+
+		if pusher, ok := peer.(PeerPusher); ok {
+			if err := g.push(ctx, pusher, key); err != nil {
+				errs = append(errs, fmt.Errorf("migrate %s: %w", key, err))
+			} else {
+				n++
+			}
+		}
+		g.mainCache.Remove(key)
+	}
+
+	return n, errors.Join(errs...)
+}
+
+// 将key对应的数据及其剩余存活时间推送给pusher
+func (g *Group) push(ctx context.Context, pusher PeerPusher, key string) error {
+	cached, ok := g.mainCache.Peek(key)
+	if !ok {
+		return nil
+	}
+	view, ok := g.viewOf(cached.(*cacheValue))
+	if !ok {
+		return nil
+	}
+
+	out := &cachepb.Response{Value: view.b}
+	if info, ok := g.mainCache.EntryInfo(key); ok && info.TTL > 0 {
+		out.TtlMs = max(info.TTL.Milliseconds(), 1)
+	}
+
+	return pusher.Push(ctx, &cachepb.Request{Group: g.name, Key: key}, out)
+}
+
+// 接收其他节点迁移过来的数据并放入本地缓存，ttl为数据剩余的存活时间，为0时按WithTTL等选项的设置加入
+func (g *Group) Receive(key string, value []byte, ttl time.Duration) {
+	g.forget(key)
+	g.populateWithTTL(key, NewByteView(value), ttl)
+}
+
+// 以ttl为存活时间将数据放入本地缓存，ttl小于等于0时与populateMain相同
+func (g *Group) populateWithTTL(key string, value ByteView, ttl time.Duration) {
+	if ttl <= 0 {
+		g.populateMain(key, value)
+		return
+	}
+
+	if v, ok := g.newCacheValue(value); ok {
+		g.mainCache.AddWithTTL(key, v, ttl)
+	}
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cache/cachepb"
+)
+
+// 记录推送数据的远程节点
+type fakePusher struct {
+	fakePeer
+	pushed map[string]*cachepb.Response
+}
+
+func (p *fakePusher) Push(ctx context.Context, in *cachepb.Request, value *cachepb.Response) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.pushed[in.Key] = value
+
+	return nil
+}
+
+// 将owners中的key分配给对应的远程节点，其余key属于本节点
+type ownerPicker struct {
+	owners map[string]PeerGetter
+}
+
+func (p *ownerPicker) PickPeer(key string) (PeerGetter, bool) {
+	peer, ok := p.owners[key]
+	return peer, ok
+}
+
+func TestGroup_Migrate(t *testing.T) {
+	ctx := context.Background()
+	picker := &ownerPicker{owners: map[string]PeerGetter{}}
+	g := NewGroup("scores-migrate", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("local:" + key), nil
+	}), WithTTL(time.Minute))
+	g.RegisterPeers(picker)
+
+	for _, key := range []string{"Tom", "Jack", "Sam"} {
+		g.Get(ctx, key)
+	}
+
+	// 节点变化后Tom属于支持推送的节点，Jack属于不支持推送的节点
+	pusher := &fakePusher{pushed: map[string]*cachepb.Response{}}
+	picker.owners["Tom"] = pusher
+	picker.owners["Jack"] = &fakePeer{}
+
+	if n, err := g.Migrate(ctx); n != 1 || err != nil {
+		t.Fatalf("expected 1 migrated key but got %d, %v", n, err)
+	}
+	out := pusher.pushed["Tom"]
+	if out == nil || string(out.Value) != "local:Tom" || out.TTL() <= 0 || out.TTL() > time.Minute {
+		t.Fatalf("unexpected pushed value %+v", out)
+	}
+	for key, want := range map[string]bool{"Tom": false, "Jack": false, "Sam": true} {
+		if _, ok := g.Peek(key); ok != want {
+			t.Fatalf("expected %s cached to be %v", key, want)
+		}
+	}
+
+	// 推送失败的key同样从本地移除
+	g.Get(ctx, "Bob")
+	pusher.err = errors.New("unavailable")
+	picker.owners["Bob"] = pusher
+	if n, err := g.Migrate(ctx); n != 0 || err == nil {
+		t.Fatalf("expected push error but got %d, %v", n, err)
+	}
+	if _, ok := g.Peek("Bob"); ok {
+		t.Fatalf("Bob should be removed after a failed push")
+	}
+}
+
+func TestGroup_Receive(t *testing.T) {
+	g := NewGroup("scores-receive", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return nil, errors.New("not found")
+	}))
+
+	g.Receive("Tom", []byte("630"), 0)
+	g.Receive("Jack", []byte("589"), time.Millisecond)
+	if view, ok := g.Peek("Tom"); !ok || view.String() != "630" {
+		t.Fatalf("failed to receive Tom: %s, %v", view, ok)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := g.Peek("Jack"); ok {
+		t.Fatalf("Jack should expire with the received ttl")
+	}
+}
@@ -0,0 +1,178 @@
+package namespace
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"cache/lru"
+)
+
+// 与lru包共用Value接口
+type Value = lru.Value
+
+// 多个命名空间共用一份容量的缓存，每个命名空间拥有独立的淘汰顺序、回调函数与可选的容量上限。
+// 总容量不足时从占用空间最多的命名空间中淘汰条目，因此占用较少的命名空间不会被其他命名空间挤出。
+// 可安全地被多个goroutine并发使用
+type Cache struct {
+	// 保护namespaces的互斥锁，同时保证同一时刻只有一个goroutine在淘汰条目
+	mu sync.Mutex
+
+	// 所有命名空间共用的最大容量（单位为字节），为0时表示不限制
+	capacity int64
+
+	// 存储名称与命名空间映射关系的哈希表
+	namespaces map[string]*Namespace
+}
+
+// 命名空间，条目存储在独立的lru.Cache中
+type Namespace struct {
+	name   string
+	parent *Cache
+	cache  *lru.Cache
+}
+
+// 实例化共用容量的缓存，capacity为所有命名空间的总容量（单位为字节）
+func New(capacity int64) *Cache {
+	return &Cache{
+		capacity:   capacity,
+		namespaces: make(map[string]*Namespace),
+	}
+}
+
+// 创建命名空间，quota为该命名空间的容量上限（单位为字节），为0时只受总容量限制。
+// onEvicted在该命名空间的条目被清除时执行，名称已存在时panic
+func (c *Cache) NewNamespace(name string, quota int64, onEvicted func(string, Value)) *Namespace {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.namespaces[name]; ok {
+		panic(fmt.Sprintf("namespace %q already exists", name))
+	}
+
+	ns := &Namespace{
+		name:   name,
+		parent: c,
+		cache:  lru.New(quota, onEvicted),
+	}
+	c.namespaces[name] = ns
+
+	return ns
+}
+
+// 获取名称为name的命名空间，不存在时返回nil
+func (c *Cache) Namespace(name string) *Namespace {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.namespaces[name]
+}
+
+// 按名称排序获取所有命名空间的名称
+func (c *Cache) Names() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, 0, len(c.namespaces))
+	for name := range c.namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// 获取所有命名空间已使用的缓存空间（单位为字节）
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.size()
+}
+
+// 统计所有命名空间已使用的缓存空间，调用方需持有锁
+func (c *Cache) size() int64 {
+	var size int64
+	for _, ns := range c.namespaces {
+		size += ns.cache.Size()
+	}
+
+	return size
+}
+
+// 如果总大小大于总容量，则持续从占用空间最多的命名空间中淘汰条目
+func (c *Cache) evict() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capacity == 0 {
+		return
+	}
+
+	for c.size() > c.capacity {
+		var largest *Namespace
+		var largestSize int64
+		for _, ns := range c.namespaces {
+			if size := ns.cache.Size(); size > largestSize {
+				largest, largestSize = ns, size
+			}
+		}
+		if largest == nil {
+			return
+		}
+		largest.cache.RemoveOldest()
+	}
+}
+
+// 获取命名空间的名称
+func (ns *Namespace) Name() string {
+	return ns.name
+}
+
+// 实现查找功能
+func (ns *Namespace) Get(key string) (Value, bool) {
+	return ns.cache.Get(key)
+}
+
+// 查找key对应的value，不会影响条目的淘汰顺序
+func (ns *Namespace) Peek(key string) (Value, bool) {
+	return ns.cache.Peek(key)
+}
+
+// 实现新增与修改功能
+func (ns *Namespace) Add(key string, value Value) {
+	ns.cache.Add(key, value)
+	ns.parent.evict()
+}
+
+// 新增或修改条目，并设置其存活时间
+func (ns *Namespace) AddWithTTL(key string, value Value, ttl time.Duration) {
+	ns.cache.AddWithTTL(key, value, ttl)
+	ns.parent.evict()
+}
+
+// 实现删除功能，返回key是否存在于命名空间中
+func (ns *Namespace) Remove(key string) bool {
+	return ns.cache.Remove(key)
+}
+
+// 清空命名空间，不调用回调函数
+func (ns *Namespace) Clear() {
+	ns.cache.Clear()
+}
+
+// 获取命名空间的条目数量
+func (ns *Namespace) Len() int {
+	return ns.cache.Len()
+}
+
+// 获取命名空间已使用的缓存空间（单位为字节）
+func (ns *Namespace) Size() int64 {
+	return ns.cache.Size()
+}
+
+// 获取命名空间的统计信息
+func (ns *Namespace) Stats() lru.Stats {
+	return ns.cache.Stats()
+}
@@ -0,0 +1,88 @@
+package namespace
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+func TestNamespace_Isolated(t *testing.T) {
+	c := New(0)
+	sessions := c.NewNamespace("sessions", 0, nil)
+	thumbnails := c.NewNamespace("thumbnails", 0, nil)
+
+	sessions.Add("k", String("session"))
+	thumbnails.Add("k", String("thumbnail"))
+	if v, ok := sessions.Get("k"); !ok || v.(String) != "session" {
+		t.Fatalf("expected session but got %v", v)
+	}
+	if v, ok := thumbnails.Get("k"); !ok || v.(String) != "thumbnail" {
+		t.Fatalf("expected thumbnail but got %v", v)
+	}
+
+	if c.Namespace("sessions") != sessions || c.Namespace("unknown") != nil {
+		t.Fatalf("Namespace lookup failed")
+	}
+	if names := c.Names(); !reflect.DeepEqual(names, []string{"sessions", "thumbnails"}) {
+		t.Fatalf("unexpected names %v", names)
+	}
+}
+
+func TestNamespace_Quota(t *testing.T) {
+	var evicted []string
+	c := New(0)
+	ns := c.NewNamespace("sessions", 10, func(key string, value Value) {
+		evicted = append(evicted, key)
+	})
+
+	ns.Add("k1", String("v1"))
+	ns.Add("k2", String("v2"))
+	ns.Add("k3", String("v3"))
+	if ns.Len() != 2 || !reflect.DeepEqual(evicted, []string{"k1"}) {
+		t.Fatalf("expected k1 to be evicted but got %v", evicted)
+	}
+}
+
+func TestCache_SharedBudget(t *testing.T) {
+	var evicted []string
+	c := New(40)
+	sessions := c.NewNamespace("sessions", 0, nil)
+	thumbnails := c.NewNamespace("thumbnails", 0, func(key string, value Value) {
+		evicted = append(evicted, key)
+	})
+
+	sessions.Add("s1", String("v1"))
+	sessions.Add("s2", String("v2"))
+	for i := 0; i < 10; i++ {
+		thumbnails.Add("t"+strconv.Itoa(i), String("v"+strconv.Itoa(i)))
+	}
+
+	if c.Size() > 40 {
+		t.Fatalf("expected size <= 40 but got %d", c.Size())
+	}
+	if sessions.Len() != 2 {
+		t.Fatalf("expected sessions to keep 2 entries but got %d", sessions.Len())
+	}
+	if !reflect.DeepEqual(evicted, []string{"t0", "t1"}) {
+		t.Fatalf("expected t0 and t1 to be evicted but got %v", evicted)
+	}
+}
+
+func TestCache_DuplicateNamespace(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic on duplicate namespace")
+		}
+	}()
+
+	c := New(0)
+	c.NewNamespace("sessions", 0, nil)
+	c.NewNamespace("sessions", 0, nil)
+}
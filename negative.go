@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// 数据源中不存在key时Getter应返回的错误，可以使用fmt.Errorf的%w包装
+var ErrNotFound = errors.New("cache: key not found")
+
+// 开启负缓存：数据源返回ErrNotFound时将结果缓存ttl时间，期间对该key的请求直接返回相同的错误，
+// 不再访问数据源。负缓存额外占用八分之一的缓存容量
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(g *Group) {
+		g.negativeTTL = ttl
+	}
+}
+
+// 负缓存中存储的value，记录数据源返回的错误
+type missValue struct {
+	err error
+}
+
+// 只计算key占用的字节
+func (missValue) Len() int {
+	return 0
+}
+
+// 查找负缓存，key被记录为不存在时返回数据源当时返回的错误
+func (g *Group) lookupMissing(key string) error {
+	if g.negCache == nil {
+		return nil
+	}
+
+	if value, ok := g.negCache.Get(key); ok {
+		return value.(missValue).err
+	}
+
+	return nil
+}
+
+// 数据源返回ErrNotFound时将结果放入负缓存
+func (g *Group) populateMissing(key string, err error) {
+	if g.negCache != nil && errors.Is(err, ErrNotFound) {
+		g.negCache.AddWithTTL(key, missValue{err: err}, g.negativeTTL)
+	}
+}
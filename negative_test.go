@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cache/timesource"
+)
+
+func TestGroup_NegativeTTL(t *testing.T) {
+	ctx := context.Background()
+	var loads int32
+	clock := timesource.NewFake(time.Now())
+	g := NewGroup("scores-negative", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		atomic.AddInt32(&loads, 1)
+		if key == "Nobody" {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+		}
+		return nil, errors.New("unavailable")
+	}), WithNegativeTTL(20*time.Millisecond), WithClock(clock))
+
+	// 不存在的key在存活时间内只访问一次数据源
+	for i := 0; i < 3; i++ {
+		if _, err := g.Get(ctx, "Nobody"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound but got %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&loads); n != 1 {
+		t.Fatalf("expected 1 load but got %d", n)
+	}
+
+	// 过期后重新访问数据源
+	clock.Advance(30 * time.Millisecond)
+	g.Get(ctx, "Nobody")
+	if n := atomic.LoadInt32(&loads); n != 2 {
+		t.Fatalf("expected 2 loads but got %d", n)
+	}
+
+	// 其他错误不会被缓存
+	g.Get(ctx, "Tom")
+	g.Get(ctx, "Tom")
+	if n := atomic.LoadInt32(&loads); n != 4 {
+		t.Fatalf("expected 4 loads but got %d", n)
+	}
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"time"
+
+	"cache/hotkeys"
+	"cache/quota"
+	"cache/timesource"
+)
+
+// 实例化Group时的可选配置
+type Option func(g *Group)
+
+// 设置本地缓存中条目的存活时间，过期后重新从数据源加载，ttl小于等于0时表示永不过期
+func WithTTL(ttl time.Duration) Option {
+	return func(g *Group) {
+		g.ttl = ttl
+	}
+}
+
+// 开启热点key统计：记录每个窗口内请求次数最多的k个key，通过HottestKeys获取。
+// 统计只计入Get与GetMulti的请求，不包括来自其他节点的请求
+func WithHotKeys(k int, window time.Duration) Option {
+	return func(g *Group) {
+		g.hotKeys = hotkeys.New(k, window, hotkeys.WithClock(groupClock{g}))
+	}
+}
+
+// 开启热点复制：窗口内请求次数不小于threshold的key从其所属的前n个节点中随机选择一个加载，
+// 避免单个节点承受全部的请求。需要同时开启WithHotKeys，且PeerPicker需要实现ReplicaPicker
+func WithHotKeyReplication(n int, threshold uint64) Option {
+	return func(g *Group) {
+		g.replicas = n
+		g.replicaThreshold = threshold
+	}
+}
+
+// 在本地缓存每个条目的开销上加上overhead字节，使cacheBytes更接近实际占用的内存，例如传入lru.EntryOverhead
+func WithEntryOverhead(overhead int64) Option {
+	return func(g *Group) {
+		g.entryOverhead = overhead
+	}
+}
+
+// 限制本地缓存中单个条目的最大字节数，超过限制的数据仍会返回给调用方但不会被缓存，n为0时表示只受cacheBytes限制
+func WithMaxValueBytes(n int64) Option {
+	return func(g *Group) {
+		g.maxValueBytes = n
+	}
+}
+
+// 本地缓存、热点缓存与空值缓存共用配额q，超出独享配额的部分从共享的溢出池中借用，
+// 溢出池耗尽后只淘汰本命名空间的条目。q通常由quota.Pool为每个命名空间分别创建
+func WithQuota(q *quota.Quota) Option {
+	return func(g *Group) {
+		g.quota = q
+	}
+}
+
+// 使用clock代替系统时间判断条目的过期、刷新与热点统计的窗口，测试中可以传入timesource.Fake推进时间
+func WithClock(clock timesource.Clock) Option {
+	return func(g *Group) {
+		g.clock = clock
+	}
+}
+
+// 读取Group当前设置的时钟，使选项的顺序不影响缓存与热点统计使用的时钟
+type groupClock struct {
+	g *Group
+}
+
+func (c groupClock) Now() time.Time {
+	return c.g.now()
+}
+
+// 获取当前时间
+func (g *Group) now() time.Time {
+	if g.clock != nil {
+		return g.clock.Now()
+	}
+
+	return time.Now()
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"cache/breaker"
+)
+
+// 重试前的最长等待时间
+const maxRetryBackoff = 10 * time.Second
+
+// 从远程节点获取数据的超时与重试配置，字段为零值时使用默认值
+type PeerFetch struct {
+	// 每次请求的超时时间，默认为1秒
+	Timeout time.Duration
+
+	// 请求失败时的最大重试次数，默认为2，小于0时表示不重试
+	MaxRetries int
+
+	// 第一次重试前的最长等待时间，之后每次重试翻倍但不超过10秒，实际等待时间在其中随机选择，默认为20毫秒
+	RetryBackoff time.Duration
+}
+
+// 为从远程节点获取数据的请求设置超时与重试，重试都失败时从数据源加载，避免单个缓慢的节点拖慢用户的请求。
+// 未开启时每次只请求一次且只受调用方ctx的限制
+func WithPeerFetch(config PeerFetch) Option {
+	return func(g *Group) {
+		if config.Timeout <= 0 {
+			config.Timeout = time.Second
+		}
+		if config.MaxRetries == 0 {
+			config.MaxRetries = 2
+		}
+		if config.RetryBackoff <= 0 {
+			config.RetryBackoff = 20 * time.Millisecond
+		}
+
+		g.peerFetch = &config
+	}
+}
+
+// 调用fetch向远程节点peer发起请求，开启熔断且peer的熔断器打开时返回breaker.ErrOpen并且不发起请求。
+// 只有超时与传输失败计入熔断统计，远程节点已经作出回答时视为成功，调用方的ctx结束时不记录结果
+func (g *Group) callPeer(ctx context.Context, peer PeerGetter, fetch func(ctx context.Context) error) error {
+	b := g.breakerOf(peer)
+	if b == nil {
+		return g.fetchWithRetry(ctx, fetch)
+	}
+
+	if !b.Allow() {
+		return breaker.ErrOpen
+	}
+	err := g.fetchWithRetry(ctx, fetch)
+	switch {
+	case peerAnswered(err):
+		b.Done(nil)
+	case err != nil && ctx.Err() != nil:
+		b.Ignore()
+	default:
+		b.Done(err)
+	}
+
+	return err
+}
+
+// 调用fetch，开启WithPeerFetch时每次请求使用独立的超时时间，
+// 超时或传输失败后按带随机抖动的指数退避重试，远程节点已经作出回答或调用方的ctx结束后不再重试
+func (g *Group) fetchWithRetry(ctx context.Context, fetch func(ctx context.Context) error) error {
+	config := g.peerFetch
+	if config == nil {
+		return fetch(ctx)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := callWithTimeout(ctx, config.Timeout, fetch)
+		if err == nil || peerAnswered(err) || attempt >= config.MaxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		// 多个节点同时重试时随机等待，避免重试请求集中到达
+		timer := time.NewTimer(rand.N(backoff(config.RetryBackoff, attempt)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// 获取第attempt次重试前的最长等待时间，翻倍后超过maxRetryBackoff时返回maxRetryBackoff，base需大于0
+func backoff(base time.Duration, attempt int) time.Duration {
+	shift := min(attempt, 30)
+	if base > maxRetryBackoff>>shift {
+		return maxRetryBackoff
+	}
+
+	return base << shift
+}
+
+// 使用超时时间为timeout的ctx调用fetch
+func callWithTimeout(ctx context.Context, timeout time.Duration, fetch func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return fetch(ctx)
+}
+
+// 远程节点确认key不存在或其数据源加载失败，说明请求已被正常处理
+func peerAnswered(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, ErrPeerGetter)
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"cache/cachepb"
+)
+
+// 前failures次请求失败的远程节点
+type flakyPeer struct {
+	fakePeer
+	failures int
+}
+
+func (p *flakyPeer) Get(ctx context.Context, in *cachepb.Request, out *cachepb.Response) error {
+	if p.failures > 0 {
+		p.failures--
+		p.calls++
+		return errors.New("unavailable")
+	}
+
+	return p.fakePeer.Get(ctx, in, out)
+}
+
+// 直到ctx结束才返回的远程节点
+type slowPeer struct{}
+
+func (slowPeer) Get(ctx context.Context, in *cachepb.Request, out *cachepb.Response) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestGroup_WithPeerFetchRetry(t *testing.T) {
+	ctx := context.Background()
+	peer := &flakyPeer{failures: 2}
+	g := NewGroup("scores-peer-retry", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("local:" + key), nil
+	}), WithPeerFetch(PeerFetch{RetryBackoff: time.Millisecond}))
+	g.RegisterPeers(&fakePicker{peer: peer})
+
+	// 失败后重试，直到从远程节点获取到数据
+	if view, err := g.Get(ctx, "Tom"); err != nil || view.String() != "peer:scores-peer-retry/Tom" {
+		t.Fatalf("expected value from peer but got %q, %v", view.String(), err)
+	}
+	if peer.calls != 3 {
+		t.Fatalf("expected 3 calls but got %d", peer.calls)
+	}
+
+	// 重试都失败时从数据源加载
+	peer.failures = 3
+	if view, err := g.Get(ctx, "Jack"); err != nil || view.String() != "local:Jack" {
+		t.Fatalf("expected local value but got %q, %v", view.String(), err)
+	}
+}
+
+func TestGroup_WithPeerFetchTimeout(t *testing.T) {
+	ctx := context.Background()
+	g := NewGroup("scores-peer-timeout", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("local:" + key), nil
+	}), WithPeerFetch(PeerFetch{Timeout: 10 * time.Millisecond, MaxRetries: -1}))
+	g.RegisterPeers(&fakePicker{peer: slowPeer{}})
+
+	// 缓慢的节点超时后立即从数据源加载
+	start := time.Now()
+	if view, err := g.Get(ctx, "Tom"); err != nil || view.String() != "local:Tom" {
+		t.Fatalf("expected local value but got %q, %v", view.String(), err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected peer fetch to time out but took %v", elapsed)
+	}
+}
+
+func TestGroup_WithPeerFetchAnswered(t *testing.T) {
+	ctx := context.Background()
+	peer := &fakePeer{err: fmt.Errorf("%w: Tom", ErrNotFound)}
+	g := NewGroup("scores-peer-answered", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("local:" + key), nil
+	}), WithPeerFetch(PeerFetch{RetryBackoff: time.Millisecond}))
+	g.RegisterPeers(&fakePicker{peer: peer})
+
+	// 远程节点确认key不存在时不重试，直接返回ErrNotFound
+	if _, err := g.Get(ctx, "Tom"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound but got %v", err)
+	}
+	if peer.calls != 1 {
+		t.Fatalf("expected 1 call but got %d", peer.calls)
+	}
+
+	// 远程节点的数据源出错时不重试，从本节点的数据源加载
+	peer.err = fmt.Errorf("%w: Jack", ErrPeerGetter)
+	if view, err := g.Get(ctx, "Jack"); err != nil || view.String() != "local:Jack" {
+		t.Fatalf("expected local value but got %q, %v", view.String(), err)
+	}
+	if peer.calls != 2 {
+		t.Fatalf("expected 2 calls but got %d", peer.calls)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := []struct {
+		base    time.Duration
+		attempt int
+		expect  time.Duration
+	}{
+		{20 * time.Millisecond, 0, 20 * time.Millisecond},
+		{20 * time.Millisecond, 2, 80 * time.Millisecond},
+		{20 * time.Millisecond, 100, maxRetryBackoff},
+		{time.Duration(1) << 62, 1, maxRetryBackoff},
+	}
+	for _, tc := range cases {
+		if got := backoff(tc.base, tc.attempt); got != tc.expect {
+			t.Fatalf("backoff(%v, %d) = %v; want %v", tc.base, tc.attempt, got, tc.expect)
+		}
+	}
+}
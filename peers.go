@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"cache/cachepb"
+)
+
+// 远程节点的数据源加载失败时PeerGetter返回的错误，可以使用fmt.Errorf的%w包装，
+// 表示节点本身可以正常响应，重试不会得到不同的结果。GetLocalResponse使用它包装数据源返回的错误
+var ErrPeerGetter = errors.New("cache: peer getter failed")
+
+// 根据key选择节点
+type PeerPicker interface {
+	// 返回key所属的远程节点，key属于本节点时ok为false
+	PickPeer(key string) (peer PeerGetter, ok bool)
+}
+
+// 从远程节点获取数据的客户端
+type PeerGetter interface {
+	Get(ctx context.Context, in *cachepb.Request, out *cachepb.Response) error
+}
+
+// 可以返回远程节点地址的PeerGetter，节点列表更新后PeerGetter可能被重新创建，
+// 熔断器等按节点记录的状态以地址区分节点
+type PeerAddresser interface {
+	PeerGetter
+
+	Addr() string
+}
+
+// 可以在一次请求中获取多个key的PeerGetter，返回的响应与请求一一对应
+type MultiPeerGetter interface {
+	PeerGetter
+
+	GetMulti(ctx context.Context, in []*cachepb.Request) ([]*cachepb.Response, error)
+}
+
+// 可以接收迁移数据的PeerGetter，节点变化后原所属节点通过Push将key推送给新的所属节点
+type PeerPusher interface {
+	PeerGetter
+
+	Push(ctx context.Context, in *cachepb.Request, value *cachepb.Response) error
+}
+
+// 可以将热点key分散到多个节点的PeerPicker
+type ReplicaPicker interface {
+	PeerPicker
+
+	// 在key所属的前n个节点中随机选择一个，选中本节点时ok为false
+	PickReplica(key string, n int) (peer PeerGetter, ok bool)
+}
+
+// 可以通知远程节点移除key的PeerGetter
+type PeerInvalidator interface {
+	PeerGetter
+
+	Invalidate(ctx context.Context, in *cachepb.Request) error
+}
+
+// 可以列出所有远程节点的PeerPicker，用于向整个集群广播
+type PeerLister interface {
+	PeerPicker
+
+	// 获取除本节点以外的所有节点
+	Peers() []PeerGetter
+}
@@ -0,0 +1,85 @@
+package quota
+
+import "sync"
+
+// 多个命名空间共享的容量预算。每个命名空间拥有独享的配额，超出配额的部分从共享的溢出池中借用，
+// 溢出池耗尽后命名空间只能淘汰自己的条目，因此一个命名空间无法占满整个进程的缓存预算。
+// 可安全地被多个goroutine并发使用
+type Pool struct {
+	// 保护溢出池与所有配额的互斥锁
+	mu sync.Mutex
+
+	// 溢出池的大小（单位为字节）
+	shared int64
+
+	// 溢出池中尚未被借用的字节数
+	free int64
+}
+
+// 实例化Pool，shared为所有命名空间共享的溢出池大小（单位为字节）
+func NewPool(shared int64) *Pool {
+	return &Pool{shared: shared, free: shared}
+}
+
+// 创建一个独享reserved字节的配额，超出部分从溢出池中借用
+func (p *Pool) NewQuota(reserved int64) *Quota {
+	return &Quota{pool: p, reserved: reserved}
+}
+
+// 获取溢出池中尚未被借用的字节数
+func (p *Pool) Free() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.free
+}
+
+// 单个命名空间的配额
+type Quota struct {
+	// 所属的Pool
+	pool *Pool
+
+	// 独享的字节数
+	reserved int64
+
+	// 已使用的字节数，超过reserved的部分是从溢出池中借用的
+	used int64
+}
+
+// 申请n个字节，独享的配额与溢出池都不足时不做任何修改并返回false
+func (q *Quota) Acquire(n int64) bool {
+	q.pool.mu.Lock()
+	defer q.pool.mu.Unlock()
+
+	borrow := q.borrowed(q.used+n) - q.borrowed(q.used)
+	if borrow > q.pool.free {
+		return false
+	}
+	q.pool.free -= borrow
+	q.used += n
+
+	return true
+}
+
+// 归还n个字节，优先归还从溢出池中借用的部分
+func (q *Quota) Release(n int64) {
+	q.pool.mu.Lock()
+	defer q.pool.mu.Unlock()
+
+	n = min(n, q.used)
+	q.pool.free += q.borrowed(q.used) - q.borrowed(q.used-n)
+	q.used -= n
+}
+
+// 获取已使用的字节数
+func (q *Quota) Used() int64 {
+	q.pool.mu.Lock()
+	defer q.pool.mu.Unlock()
+
+	return q.used
+}
+
+// 使用used个字节时需要从溢出池中借用的字节数，调用方需持有锁
+func (q *Quota) borrowed(used int64) int64 {
+	return max(used-q.reserved, 0)
+}
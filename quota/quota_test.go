@@ -0,0 +1,34 @@
+package quota
+
+import "testing"
+
+func TestQuota(t *testing.T) {
+	pool := NewPool(10)
+	q1, q2 := pool.NewQuota(10), pool.NewQuota(5)
+
+	// 先使用独享的配额，再从溢出池中借用
+	if !q1.Acquire(10) || pool.Free() != 10 {
+		t.Fatalf("expected q1 to use its reserved bytes")
+	}
+	if !q1.Acquire(8) || pool.Free() != 2 {
+		t.Fatalf("expected q1 to borrow 8 bytes")
+	}
+
+	// 溢出池不足时不影响其他命名空间的独享配额
+	if q1.Acquire(3) || q1.Used() != 18 {
+		t.Fatalf("expected q1 to be rejected")
+	}
+	if !q2.Acquire(7) || q2.Acquire(1) || pool.Free() != 0 {
+		t.Fatalf("expected q2 to use its reserved bytes and the rest of the pool")
+	}
+
+	// 优先归还借用的部分
+	q1.Release(9)
+	if q1.Used() != 9 || pool.Free() != 8 {
+		t.Fatalf("expected borrowed bytes to be returned first but free is %d", pool.Free())
+	}
+	q2.Release(100)
+	if q2.Used() != 0 || pool.Free() != 10 {
+		t.Fatalf("expected all bytes to be returned")
+	}
+}
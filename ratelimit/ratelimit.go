@@ -0,0 +1,186 @@
+package ratelimit
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"cache/timesource"
+)
+
+// 默认最多单独记录的客户端数量
+const DefaultMaxClients = 10000
+
+// 令牌桶的速率限制，Rate为每秒生成的令牌数，Burst为桶的容量，Rate小于等于0时表示不限制
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// 实例化Limiter时的可选配置
+type Option func(l *Limiter)
+
+// 设置最多单独记录的客户端数量，超过时优先丢弃令牌桶已经装满的空闲客户端
+func WithMaxClients(n int) Option {
+	return func(l *Limiter) {
+		l.maxClients = max(n, 1)
+	}
+}
+
+// 使用clock代替系统时间生成令牌，测试中可以传入timesource.Fake推进时间
+func WithClock(clock timesource.Clock) Option {
+	return func(l *Limiter) {
+		l.clock = clock
+	}
+}
+
+// 基于令牌桶的限流器，同时限制每个客户端与所有客户端合计的请求速率，
+// 避免单个客户端耗尽节点的处理能力。可安全地被多个goroutine并发使用
+type Limiter struct {
+	// 每个客户端的限制
+	perClient Limit
+
+	// 最多单独记录的客户端数量
+	maxClients int
+
+	// 获取当前时间的时钟
+	clock timesource.Clock
+
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 所有客户端共享的令牌桶
+	global bucket
+
+	// 存储客户端与令牌桶映射关系的哈希表
+	clients map[string]*bucket
+}
+
+// 实例化Limiter，global限制所有客户端合计的请求速率，perClient限制每个客户端的请求速率
+func New(global, perClient Limit, opts ...Option) *Limiter {
+	l := &Limiter{
+		perClient:  perClient,
+		maxClients: DefaultMaxClients,
+		clock:      timesource.System,
+		clients:    make(map[string]*bucket),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.global = newBucket(global, l.clock.Now())
+
+	return l
+}
+
+// 判断是否允许client的一个请求，允许时从客户端与全局的令牌桶中各取出一个令牌
+func (l *Limiter) Allow(client string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	b, ok := l.clients[client]
+	if !ok && l.perClient.Rate > 0 {
+		if len(l.clients) >= l.maxClients {
+			l.sweep(now)
+		}
+		nb := newBucket(l.perClient, now)
+		b = &nb
+		l.clients[client] = b
+	}
+
+	l.global.refill(now)
+	if b != nil {
+		b.refill(now)
+	}
+	if !l.global.ready() || b != nil && !b.ready() {
+		return false
+	}
+
+	l.global.take()
+	if b != nil {
+		b.take()
+	}
+
+	return true
+}
+
+// 丢弃令牌桶已经装满的客户端，它们与新建的令牌桶等价；仍然超过数量限制时任意丢弃客户端，调用方需持有锁
+func (l *Limiter) sweep(now time.Time) {
+	for client, b := range l.clients {
+		if b.refill(now); b.full() {
+			delete(l.clients, client)
+		}
+	}
+
+	for client := range l.clients {
+		if len(l.clients) < l.maxClients {
+			break
+		}
+		delete(l.clients, client)
+	}
+}
+
+// 令牌桶
+type bucket struct {
+	// 速率限制
+	limit Limit
+
+	// 当前的令牌数
+	tokens float64
+
+	// 上次生成令牌的时间
+	last time.Time
+}
+
+// 实例化装满令牌的令牌桶
+func newBucket(limit Limit, now time.Time) bucket {
+	return bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+}
+
+// 按经过的时间生成令牌
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.tokens+elapsed.Seconds()*b.limit.Rate, float64(b.limit.Burst))
+		b.last = now
+	}
+}
+
+// 判断是否有可用的令牌
+func (b *bucket) ready() bool {
+	return b.limit.Rate <= 0 || b.tokens >= 1
+}
+
+// 取出一个令牌
+func (b *bucket) take() {
+	if b.limit.Rate > 0 {
+		b.tokens--
+	}
+}
+
+// 判断令牌桶是否已经装满
+func (b *bucket) full() bool {
+	return b.tokens >= float64(b.limit.Burst)
+}
+
+// 为next加上限流，以请求的来源IP区分客户端，超过限制的请求返回429 Too Many Requests
+func Handler(l *Limiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.Allow(ClientOf(r.RemoteAddr)) {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// 获取连接地址中的IP，作为区分客户端的标识，同一主机的多个连接共享同一个令牌桶
+func ClientOf(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cache/timesource"
+)
+
+func TestLimiter_PerClient(t *testing.T) {
+	clock := timesource.NewFake(time.Now())
+	l := New(Limit{}, Limit{Rate: 1, Burst: 2}, WithClock(clock))
+
+	// 每个客户端使用独立的令牌桶
+	if !l.Allow("a") || !l.Allow("a") || l.Allow("a") {
+		t.Fatalf("expected a to be limited after 2 requests")
+	}
+	if !l.Allow("b") {
+		t.Fatalf("expected b not to be limited")
+	}
+
+	// 按经过的时间生成令牌
+	clock.Advance(time.Second)
+	if !l.Allow("a") || l.Allow("a") {
+		t.Fatalf("expected a to get 1 token")
+	}
+}
+
+func TestLimiter_Global(t *testing.T) {
+	clock := timesource.NewFake(time.Now())
+	l := New(Limit{Rate: 1, Burst: 2}, Limit{Rate: 1, Burst: 2}, WithClock(clock))
+
+	if !l.Allow("a") || !l.Allow("b") || l.Allow("c") {
+		t.Fatalf("expected c to be limited by the global limit")
+	}
+
+	// 被全局限制拒绝的请求不消耗客户端的令牌
+	clock.Advance(time.Second)
+	if !l.Allow("c") {
+		t.Fatalf("expected c to be allowed")
+	}
+}
+
+func TestLimiter_MaxClients(t *testing.T) {
+	clock := timesource.NewFake(time.Now())
+	l := New(Limit{}, Limit{Rate: 1, Burst: 1}, WithClock(clock), WithMaxClients(2))
+	l.Allow("a")
+	l.Allow("b")
+
+	// 空闲客户端的令牌桶装满后被丢弃
+	clock.Advance(time.Second)
+	l.Allow("c")
+	if len(l.clients) > 2 {
+		t.Fatalf("expected at most 2 clients but got %d", len(l.clients))
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := Handler(New(Limit{}, Limit{Rate: 1, Burst: 1}), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	codes := make([]int, 2)
+	for i := range codes {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		codes[i] = w.Code
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Fatalf("expected 200 and 429 but got %v", codes)
+	}
+}
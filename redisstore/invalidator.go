@@ -0,0 +1,134 @@
+package redisstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"cache"
+)
+
+// 失效消息的默认频道
+const defaultChannel = "cache:invalidate"
+
+// 通过Redis发布订阅传递的失效消息
+type invalidation struct {
+	// 发布消息的Invalidator，用于忽略自己发布的消息
+	Origin string `json:"origin"`
+
+	Group string `json:"group"`
+	Key   string `json:"key"`
+}
+
+// 通过Redis发布订阅在多个进程之间广播key的失效，适用于没有开启节点模式、
+// 每个进程各自缓存全部数据的部署方式。收到其他进程的失效消息时从同名的Group中移除key
+type Invalidator struct {
+	client redis.UniversalClient
+
+	// 发布与订阅的频道
+	channel string
+
+	// 本Invalidator的唯一标识
+	origin string
+
+	// 保护以下字段的互斥锁
+	mu sync.Mutex
+
+	// 调用Start之后的订阅，未订阅时为nil
+	sub *redis.PubSub
+
+	// 后台处理消息的goroutine退出时关闭
+	done chan struct{}
+}
+
+// 使用client实例化Invalidator，channel为空时使用默认的频道，使用同一个频道的进程之间互相广播
+func NewInvalidator(client redis.UniversalClient, channel string) *Invalidator {
+	if channel == "" {
+		channel = defaultChannel
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	return &Invalidator{
+		client:  client,
+		channel: channel,
+		origin:  hex.EncodeToString(id),
+	}
+}
+
+// 从本进程的group中移除key，并通知其他进程移除各自缓存中的key
+func (i *Invalidator) Invalidate(ctx context.Context, group, key string) error {
+	if g := cache.GetGroup(group); g != nil {
+		g.Remove(key)
+	}
+
+	msg, err := json.Marshal(invalidation{Origin: i.origin, Group: group, Key: key})
+	if err != nil {
+		return err
+	}
+
+	return i.client.Publish(ctx, i.channel, msg).Err()
+}
+
+// 订阅频道，确认订阅成功后在后台处理其他进程的失效消息，直到调用Close
+func (i *Invalidator) Start(ctx context.Context) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.sub != nil {
+		panic("Start called more than once")
+	}
+
+	sub := i.client.Subscribe(ctx, i.channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return err
+	}
+
+	i.sub, i.done = sub, make(chan struct{})
+	go i.listen(sub.Channel(), i.done)
+
+	return nil
+}
+
+// 处理收到的失效消息，直到订阅被关闭
+func (i *Invalidator) listen(ch <-chan *redis.Message, done chan struct{}) {
+	defer close(done)
+
+	for msg := range ch {
+		var inv invalidation
+		if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+			log.Println("[Redis] Failed to decode invalidation", err)
+			continue
+		}
+
+		if inv.Origin == i.origin {
+			continue
+		}
+		if g := cache.GetGroup(inv.Group); g != nil {
+			g.Remove(inv.Key)
+		}
+	}
+}
+
+// 取消订阅并等待后台的goroutine退出
+func (i *Invalidator) Close() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.sub == nil {
+		return nil
+	}
+
+	err := i.sub.Close()
+	<-i.done
+	i.sub = nil
+
+	return err
+}
@@ -0,0 +1,63 @@
+package redisstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"cache"
+)
+
+// 连接同一个Redis的Invalidator，模拟另一个进程
+func newInvalidator(t *testing.T, server *miniredis.Miniredis) *Invalidator {
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	inv := NewInvalidator(client, "")
+	if err := inv.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { inv.Close() })
+
+	return inv
+}
+
+func TestInvalidator(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	a := newInvalidator(t, server)
+
+	g := cache.NewGroup("scores-invalidator", 2<<10, cache.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("630"), nil
+	}))
+	g.Get(ctx, "Tom")
+
+	if err := a.Invalidate(ctx, "scores-invalidator", "Tom"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.Peek("Tom"); ok {
+		t.Fatalf("Tom should be removed locally")
+	}
+
+	// 其他进程发布的消息
+	g.Get(ctx, "Tom")
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	if err := client.Publish(ctx, defaultChannel, `{"origin":"other","group":"scores-invalidator","key":"Tom"}`).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := g.Peek("Tom"); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Tom should be removed by the invalidation from another process")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,131 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"cache"
+)
+
+// 实例化Store时的可选配置
+type Option func(s *Store)
+
+// 为所有key加上前缀，多个服务共用一个Redis时避免key冲突
+func WithPrefix(prefix string) Option {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
+
+// 设置Set写入的条目在Redis中的默认存活时间，默认永不过期
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Store) {
+		s.ttl = ttl
+	}
+}
+
+// 基于Redis的持久化存储，多个进程可以共用同一个Redis作为本地缓存之下的共享层。
+// 同时实现了cache.Store与cache.Getter接口，可以直接作为Group的数据源
+type Store struct {
+	client redis.UniversalClient
+
+	// key的前缀
+	prefix string
+
+	// Set写入的条目的默认存活时间，为0时表示永不过期
+	ttl time.Duration
+}
+
+var (
+	_ cache.Store  = (*Store)(nil)
+	_ cache.Getter = (*Store)(nil)
+)
+
+// 使用client实例化Store
+func New(client redis.UniversalClient, opts ...Option) *Store {
+	s := &Store{client: client}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// 获取key对应的数据，不存在时返回cache.ErrNotFound
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, cache.ErrNotFound
+	}
+
+	return value, err
+}
+
+// 在一次往返中获取key对应的数据与剩余的存活时间，永不过期时ttl为0
+func (s *Store) GetWithTTL(ctx context.Context, key string) (value []byte, ttl time.Duration, err error) {
+	var get *redis.StringCmd
+	var pttl *redis.DurationCmd
+	if _, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, s.prefix+key)
+		pttl = pipe.PTTL(ctx, s.prefix+key)
+		return nil
+	}); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, 0, err
+	}
+
+	value, err = get.Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, 0, cache.ErrNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 永不过期时PTTL返回负数
+	return value, max(pttl.Val(), 0), nil
+}
+
+// 通过pipeline在一次往返中获取keys对应的数据，不存在的key不会出现在结果中
+func (s *Store) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	cmds := make([]*redis.StringCmd, len(keys))
+	if _, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, s.prefix+key)
+		}
+		return nil
+	}); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	values := make(map[string][]byte, len(keys))
+	for i, cmd := range cmds {
+		value, err := cmd.Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[keys[i]] = value
+	}
+
+	return values, nil
+}
+
+// 写入key对应的数据，使用默认的存活时间
+func (s *Store) Set(ctx context.Context, key string, value []byte) error {
+	return s.SetWithTTL(ctx, key, value, s.ttl)
+}
+
+// 写入key对应的数据并设置其存活时间，ttl小于等于0时表示永不过期
+func (s *Store) SetWithTTL(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, s.prefix+key, value, max(ttl, 0)).Err()
+}
+
+// 删除key对应的数据，key不存在时不返回错误
+func (s *Store) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key).Err()
+}
@@ -0,0 +1,77 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"cache"
+)
+
+// 启动一个进程内的Redis服务端
+func newStore(t *testing.T, opts ...Option) (*Store, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return New(client, opts...), server
+}
+
+func TestStore_GetSet(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newStore(t, WithPrefix("scores:"))
+
+	if err := store.Set(ctx, "Tom", []byte("630")); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := store.Get(ctx, "Tom"); err != nil || string(value) != "630" {
+		t.Fatalf("failed to get Tom: %s, %v", value, err)
+	}
+
+	if err := store.Delete(ctx, "Tom"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "Tom"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound but got %v", err)
+	}
+}
+
+func TestStore_TTL(t *testing.T) {
+	ctx := context.Background()
+	store, server := newStore(t, WithTTL(time.Minute))
+
+	store.Set(ctx, "Tom", []byte("630"))
+	store.SetWithTTL(ctx, "Jack", []byte("589"), 0)
+
+	if value, ttl, err := store.GetWithTTL(ctx, "Tom"); err != nil || string(value) != "630" || ttl != time.Minute {
+		t.Fatalf("unexpected result %s, %v, %v", value, ttl, err)
+	}
+	if _, ttl, err := store.GetWithTTL(ctx, "Jack"); err != nil || ttl != 0 {
+		t.Fatalf("expected no ttl for Jack but got %v, %v", ttl, err)
+	}
+
+	server.FastForward(2 * time.Minute)
+	if _, _, err := store.GetWithTTL(ctx, "Tom"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected Tom to expire but got %v", err)
+	}
+}
+
+func TestStore_GetMulti(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newStore(t)
+
+	store.Set(ctx, "Tom", []byte("630"))
+	store.Set(ctx, "Jack", []byte("589"))
+
+	values, err := store.GetMulti(ctx, []string{"Tom", "Sam", "Jack"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || string(values["Tom"]) != "630" || string(values["Jack"]) != "589" {
+		t.Fatalf("unexpected values %v", values)
+	}
+}
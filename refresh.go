@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// 开启提前刷新：本地缓存中的条目经过fraction比例的存活时间后，Get仍然返回缓存的数据，
+// 但会在后台通过数据源重新加载，使热点key在过期时不会集中未命中。
+// fraction的取值范围为(0, 1)，需要与WithTTL一起使用
+func WithRefreshAhead(fraction float64) Option {
+	return func(g *Group) {
+		if fraction > 0 && fraction < 1 {
+			g.refreshAhead = fraction
+		}
+	}
+}
+
+// 开启过期数据宽限期：本地缓存中的条目过期后的grace时间内，GetStale仍然返回缓存的数据并将stale置为true，
+// 同时在后台通过数据源重新加载，数据源暂时不可用时调用方仍能获取到数据。需要与WithTTL一起使用
+func WithStaleWhileRevalidate(grace time.Duration) Option {
+	return func(g *Group) {
+		if grace > 0 {
+			g.staleGrace = grace
+		}
+	}
+}
+
+// 在后台从数据源重新加载key，同一个key同一时刻只会有一个刷新
+func (g *Group) refresh(key string) {
+	g.refreshMu.Lock()
+	if _, ok := g.refreshing[key]; ok {
+		g.refreshMu.Unlock()
+		return
+	}
+	g.refreshing[key] = struct{}{}
+	g.refreshMu.Unlock()
+
+	go func() {
+		defer func() {
+			g.refreshMu.Lock()
+			delete(g.refreshing, key)
+			g.refreshMu.Unlock()
+		}()
+
+		if _, err := g.loadLocally(context.Background(), key); err != nil {
+			log.Println("[Cache] Failed to refresh", key, err)
+		}
+	}()
+}
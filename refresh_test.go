@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_RefreshAhead(t *testing.T) {
+	ctx := context.Background()
+	var loads int32
+	g := NewGroup("scores-refresh", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(strconv.Itoa(int(atomic.AddInt32(&loads, 1)))), nil
+	}), WithTTL(200*time.Millisecond), WithRefreshAhead(0.25))
+
+	if view, _ := g.Get(ctx, "Tom"); view.String() != "1" {
+		t.Fatalf("expected first load but got %s", view)
+	}
+
+	// 超过存活时间的四分之一后仍返回缓存的数据，同时在后台刷新
+	time.Sleep(60 * time.Millisecond)
+	if view, _ := g.Get(ctx, "Tom"); view.String() != "1" {
+		t.Fatalf("expected cached value but got %s", view)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&loads) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected background refresh")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// 刷新完成后返回新的数据，并重新计算刷新时间
+	for {
+		view, _ := g.Get(ctx, "Tom")
+		if view.String() == "2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected refreshed value but got %s", view)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&loads); n != 2 {
+		t.Fatalf("expected 2 loads but got %d", n)
+	}
+}
+
+func TestGroup_StaleWhileRevalidate(t *testing.T) {
+	ctx := context.Background()
+	var loads int32
+	var failing atomic.Bool
+	g := NewGroup("scores-stale", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		n := atomic.AddInt32(&loads, 1)
+		if failing.Load() {
+			return nil, errors.New("unavailable")
+		}
+		return []byte(strconv.Itoa(int(n))), nil
+	}), WithTTL(20*time.Millisecond), WithStaleWhileRevalidate(time.Hour))
+
+	if view, stale, err := g.GetStale(ctx, "Tom"); err != nil || stale || view.String() != "1" {
+		t.Fatalf("expected fresh value but got %s, %v, %v", view, stale, err)
+	}
+
+	// 过期后数据源不可用，仍然返回已过期的数据
+	failing.Store(true)
+	time.Sleep(30 * time.Millisecond)
+	if view, stale, err := g.GetStale(ctx, "Tom"); err != nil || !stale || view.String() != "1" {
+		t.Fatalf("expected stale value but got %s, %v, %v", view, stale, err)
+	}
+
+	// 数据源恢复后，后台刷新成功并返回新的数据
+	failing.Store(false)
+	deadline := time.Now().Add(time.Second)
+	for {
+		view, stale, err := g.GetStale(ctx, "Tom")
+		if err == nil && !stale && view.String() != "1" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected revalidated value but got %s, %v, %v", view, stale, err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
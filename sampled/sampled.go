@@ -0,0 +1,168 @@
+package sampled
+
+import (
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+
+	"cache/lru"
+)
+
+// 与lru包共用Value接口，便于在不同淘汰策略之间切换
+type Value = lru.Value
+
+// 每次淘汰时默认随机抽取的条目数量，与Redis的maxmemory-samples默认值一致
+const DefaultSamples = 5
+
+// 缓存条目，只记录最后一次访问的时间戳而不维护链表
+type entry struct {
+	key   string
+	value Value
+
+	// 条目在entries中的下标
+	index int
+
+	// 最后一次访问的逻辑时间戳
+	lastAccess atomic.Int64
+}
+
+// 基于随机采样的近似LRU cache（Redis风格），每个条目只需一个时间戳，可安全地被多个goroutine并发使用
+type Cache struct {
+	// 读写锁，Get只需持有读锁
+	mu sync.RWMutex
+
+	// 缓存的最大容量（单位为字节）
+	capacity int64
+
+	// 已使用的缓存空间（单位为字节）
+	size int64
+
+	// 每次淘汰时随机抽取的条目数量
+	samples int
+
+	// 逻辑时钟，每次访问加一
+	clock atomic.Int64
+
+	// 所有条目，用于随机采样
+	entries []*entry
+
+	// 存储key与条目映射关系的哈希表
+	cache map[string]*entry
+
+	// 可选的回调函数，在发生缓存条目清除时被执行
+	// 回调在持有锁的情况下执行，因此不能在回调中再调用Cache的方法
+	OnEvicted func(key string, value Value)
+}
+
+// 使用默认的采样数量实例化近似LRU cache
+func New(capacity int64, onEvicted func(string, Value)) *Cache {
+	return NewWithSamples(capacity, DefaultSamples, onEvicted)
+}
+
+// 实例化近似LRU cache，samples越大淘汰结果越接近LRU，但淘汰的开销也越大
+func NewWithSamples(capacity int64, samples int, onEvicted func(string, Value)) *Cache {
+	return &Cache{
+		capacity:  capacity,
+		samples:   max(samples, 1),
+		cache:     make(map[string]*entry),
+		OnEvicted: onEvicted,
+	}
+}
+
+// 实现查找功能，命中时只更新时间戳
+func (c *Cache) Get(key string) (value Value, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if keyValue, ok := c.cache[key]; ok {
+		keyValue.lastAccess.Store(c.clock.Add(1))
+		return keyValue.value, true
+	}
+
+	return
+}
+
+// 实现新增与修改功能
+func (c *Cache) Add(key string, value Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if keyValue, ok := c.cache[key]; ok {
+		// 更新缓存大小与键值对
+		c.size = c.size - int64(keyValue.value.Len()) + int64(value.Len())
+		keyValue.value = value
+		keyValue.lastAccess.Store(c.clock.Add(1))
+	} else {
+		keyValue := &entry{key: key, value: value, index: len(c.entries)}
+		keyValue.lastAccess.Store(c.clock.Add(1))
+		c.entries = append(c.entries, keyValue)
+		c.cache[key] = keyValue
+		c.size += int64(len(key)) + int64(value.Len())
+	}
+
+	// 如果缓存大小大于缓存容量，则持续淘汰采样中最久未访问的条目
+	for c.capacity != 0 && c.capacity < c.size {
+		c.evict()
+	}
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if keyValue, ok := c.cache[key]; ok {
+		c.removeEntry(keyValue)
+		return true
+	}
+
+	return false
+}
+
+// 获取缓存的条目数量
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.entries)
+}
+
+// 随机抽取若干条目，淘汰其中最久未访问的条目，调用方需持有锁
+func (c *Cache) evict() {
+	n := len(c.entries)
+	if n == 0 {
+		return
+	}
+
+	// 采样数量不小于条目数量时直接检查所有条目
+	var victim *entry
+	for i := 0; i < c.samples && i < n; i++ {
+		candidate := c.entries[i]
+		if c.samples < n {
+			candidate = c.entries[rand.IntN(n)]
+		}
+		if victim == nil || candidate.lastAccess.Load() < victim.lastAccess.Load() {
+			victim = candidate
+		}
+	}
+
+	c.removeEntry(victim)
+}
+
+// 删除条目并调用回调函数，被删除的位置由最后一个条目填补，调用方需持有锁
+func (c *Cache) removeEntry(keyValue *entry) {
+	last := c.entries[len(c.entries)-1]
+	c.entries[keyValue.index] = last
+	last.index = keyValue.index
+	c.entries[len(c.entries)-1] = nil
+	c.entries = c.entries[:len(c.entries)-1]
+	delete(c.cache, keyValue.key)
+
+	// 更新缓存大小
+	c.size -= int64(len(keyValue.key)) + int64(keyValue.value.Len())
+
+	// 调用回调函数
+	if c.OnEvicted != nil {
+		c.OnEvicted(keyValue.key, keyValue.value)
+	}
+}
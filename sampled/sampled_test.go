@@ -0,0 +1,103 @@
+package sampled
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+func TestCache_Get(t *testing.T) {
+	sampled := New(int64(0), nil)
+	sampled.Add("peng", String("chang"))
+	if v, ok := sampled.Get("peng"); !ok || string(v.(String)) != "chang" {
+		t.Fatalf("cache hit failed")
+	}
+
+	if _, ok := sampled.Get("liu"); ok {
+		t.Fatalf("cache miss failed")
+	}
+}
+
+func TestCache_Evict(t *testing.T) {
+	// 采样数量不小于条目数量时，淘汰结果与LRU一致
+	keys := make([]string, 0)
+	sampled := NewWithSamples(int64(12), 100, func(key string, value Value) {
+		keys = append(keys, key)
+	})
+	sampled.Add("k1", String("vv"))
+	sampled.Add("k2", String("vv"))
+	sampled.Add("k3", String("vv"))
+	sampled.Get("k1")
+	sampled.Add("k4", String("vv"))
+
+	if !reflect.DeepEqual([]string{"k2"}, keys) {
+		t.Fatalf("expected k2 to be evicted but got %s", keys)
+	}
+}
+
+func TestCache_EvictFullScan(t *testing.T) {
+	// 采样数量等于或大于条目数量时检查所有条目，每次都淘汰真正最久未访问的条目
+	for _, samples := range []int{5, 100} {
+		var evicted []string
+		sampled := NewWithSamples(int64(16), samples, func(key string, value Value) {
+			evicted = append(evicted, key)
+		})
+
+		// order按访问顺序记录缓存中的key，order[0]为最久未访问的key
+		var order []string
+		touch := func(key string) {
+			if i := slices.Index(order, key); i >= 0 {
+				order = slices.Delete(order, i, i+1)
+			}
+			order = append(order, key)
+		}
+		r := rand.New(rand.NewPCG(1, 2))
+		for i := 0; i < 200; i++ {
+			if len(order) > 0 {
+				key := order[r.IntN(len(order))]
+				sampled.Get(key)
+				touch(key)
+			}
+
+			key := fmt.Sprintf("%03d", i)
+			sampled.Add(key, String("v"))
+			touch(key)
+			if len(order) > 4 {
+				if evicted[len(evicted)-1] != order[0] {
+					t.Fatalf("samples %d: expected %s to be evicted but got %s", samples, order[0], evicted[len(evicted)-1])
+				}
+				order = order[1:]
+			}
+		}
+	}
+}
+
+func TestCache_Size(t *testing.T) {
+	sampled := New(int64(100), nil)
+	for i := 0; i < 1000; i++ {
+		sampled.Add(strconv.Itoa(i), String("v"))
+	}
+
+	if sampled.size > 100 || len(sampled.cache) != len(sampled.entries) {
+		t.Fatalf("expected size <= 100 but got %d", sampled.size)
+	}
+	for i, keyValue := range sampled.entries {
+		if keyValue.index != i || sampled.cache[keyValue.key] != keyValue {
+			t.Fatalf("entry index %d is inconsistent", i)
+		}
+	}
+
+	if !sampled.Remove(sampled.entries[0].key) || sampled.Remove("missing") {
+		t.Fatalf("Remove failed")
+	}
+}
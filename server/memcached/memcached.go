@@ -0,0 +1,338 @@
+package memcached
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cache/lru"
+)
+
+// 相对过期时间的最大值，超过该值的exptime被视为Unix时间戳，与memcached一致
+const maxRelativeExptime = 60 * 60 * 24 * 30
+
+// key的最大长度，与memcached一致
+const maxKeyLength = 250
+
+// 单个条目默认的最大字节数，与memcached的-I默认值一致
+const defaultMaxItemSize = 1 << 20
+
+// 缓存中存储的value
+type item struct {
+	// 客户端设置的不透明标记
+	flags uint32
+
+	data []byte
+}
+
+// 获取数据的长度
+func (i *item) Len() int {
+	return len(i.data)
+}
+
+// 客户端请求的格式不正确
+type clientError string
+
+func (e clientError) Error() string {
+	return string(e)
+}
+
+// 数据超过单个条目的最大字节数，回复后关闭连接，不再读取客户端随后发送的数据
+var errTooLarge = errors.New("object too large for cache")
+
+// 使用memcached文本协议提供cache中数据的服务端，支持get、gets、set、delete、touch、version与quit命令，
+// 现有的memcached客户端无需修改即可使用
+type Server struct {
+	cache *lru.Cache
+
+	// 单个条目的最大字节数
+	maxItemSize int
+
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 正在监听的listener
+	listeners map[net.Listener]struct{}
+
+	// 已建立的连接
+	conns map[net.Conn]struct{}
+
+	// 是否已关闭
+	closed bool
+}
+
+// 实例化服务端时的可选配置
+type Option func(s *Server)
+
+// 设置单个条目的最大字节数，默认为1MB，set的数据超过该值时返回SERVER_ERROR并关闭连接
+func WithMaxItemSize(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.maxItemSize = n
+		}
+	}
+}
+
+// 实例化服务端
+func New(cache *lru.Cache, opts ...Option) *Server {
+	s := &Server{
+		cache:       cache,
+		maxItemSize: defaultMaxItemSize,
+		listeners:   make(map[net.Listener]struct{}),
+		conns:       make(map[net.Conn]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// 在lis上接受连接，直到调用Close
+func (s *Server) Serve(lis net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return net.ErrClosed
+	}
+	s.listeners[lis] = struct{}{}
+	s.mu.Unlock()
+
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+// 停止接受连接并关闭所有已建立的连接
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for lis := range s.listeners {
+		lis.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+
+	return nil
+}
+
+// 依次处理连接上的命令，直到连接关闭或客户端发送quit
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.Println("[Memcached] Failed to read command", err)
+			}
+			return
+		}
+
+		quit, err := s.handle(strings.Fields(line), r, w)
+		var ce clientError
+		if errors.As(err, &ce) {
+			fmt.Fprintf(w, "CLIENT_ERROR %s\r\n", ce)
+		} else if errors.Is(err, errTooLarge) {
+			fmt.Fprintf(w, "SERVER_ERROR %s\r\n", err)
+			w.Flush()
+			return
+		} else if err != nil {
+			return
+		}
+
+		if err := w.Flush(); err != nil || quit {
+			return
+		}
+	}
+}
+
+// 处理一条命令，返回是否需要关闭连接
+func (s *Server) handle(fields []string, r *bufio.Reader, w *bufio.Writer) (quit bool, err error) {
+	if len(fields) == 0 {
+		w.WriteString("ERROR\r\n")
+		return false, nil
+	}
+
+	switch fields[0] {
+	case "get", "gets":
+		return false, s.handleGet(fields[1:], fields[0] == "gets", w)
+	case "set":
+		return false, s.handleSet(fields[1:], r, w)
+	case "delete":
+		return false, s.handleDelete(fields[1:], w)
+	case "touch":
+		return false, s.handleTouch(fields[1:], w)
+	case "version":
+		w.WriteString("VERSION 1.0\r\n")
+		return false, nil
+	case "quit":
+		return true, nil
+	default:
+		w.WriteString("ERROR\r\n")
+		return false, nil
+	}
+}
+
+// get <key>*
+func (s *Server) handleGet(keys []string, cas bool, w *bufio.Writer) error {
+	if len(keys) == 0 {
+		return clientError("bad command line format")
+	}
+
+	for _, key := range keys {
+		value, ok := s.cache.Get(key)
+		if !ok {
+			continue
+		}
+
+		it := value.(*item)
+		if cas {
+			// 不支持cas命令，cas unique固定为0
+			fmt.Fprintf(w, "VALUE %s %d %d 0\r\n", key, it.flags, len(it.data))
+		} else {
+			fmt.Fprintf(w, "VALUE %s %d %d\r\n", key, it.flags, len(it.data))
+		}
+		w.Write(it.data)
+		w.WriteString("\r\n")
+	}
+	w.WriteString("END\r\n")
+
+	return nil
+}
+
+// set <key> <flags> <exptime> <bytes> [noreply]
+func (s *Server) handleSet(args []string, r *bufio.Reader, w *bufio.Writer) error {
+	if len(args) != 4 && len(args) != 5 {
+		return clientError("bad command line format")
+	}
+
+	key := args[0]
+	flags, err1 := strconv.ParseUint(args[1], 10, 32)
+	exptime, err2 := strconv.ParseInt(args[2], 10, 64)
+	n, err3 := strconv.Atoi(args[3])
+	if err1 != nil || err2 != nil || err3 != nil || n < 0 || len(key) > maxKeyLength {
+		return clientError("bad command line format")
+	}
+	if n > s.maxItemSize {
+		return errTooLarge
+	}
+
+	data := make([]byte, n+2)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	if string(data[n:]) != "\r\n" {
+		return clientError("bad data chunk")
+	}
+
+	if ttl, ok := parseExptime(exptime); ok {
+		s.cache.AddWithTTL(key, &item{flags: uint32(flags), data: data[:n]}, ttl)
+	} else {
+		s.cache.Remove(key)
+	}
+
+	reply(w, args[4:], "STORED")
+
+	return nil
+}
+
+// delete <key> [noreply]
+func (s *Server) handleDelete(args []string, w *bufio.Writer) error {
+	if len(args) != 1 && len(args) != 2 {
+		return clientError("bad command line format")
+	}
+
+	if s.cache.Remove(args[0]) {
+		reply(w, args[1:], "DELETED")
+	} else {
+		reply(w, args[1:], "NOT_FOUND")
+	}
+
+	return nil
+}
+
+// touch <key> <exptime> [noreply]
+func (s *Server) handleTouch(args []string, w *bufio.Writer) error {
+	if len(args) != 2 && len(args) != 3 {
+		return clientError("bad command line format")
+	}
+
+	exptime, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return clientError("bad command line format")
+	}
+
+	ttl, ok := parseExptime(exptime)
+	if !ok {
+		// 已经过期的exptime直接删除条目
+		ok = s.cache.Remove(args[0])
+	} else {
+		ok = s.cache.Touch(args[0], ttl, true)
+	}
+	if !ok {
+		reply(w, args[2:], "NOT_FOUND")
+		return nil
+	}
+	reply(w, args[2:], "TOUCHED")
+
+	return nil
+}
+
+// 将memcached的exptime转换为存活时间：0表示永不过期，不超过30天时为相对秒数，否则为Unix时间戳。
+// 已经过期时ok为false
+func parseExptime(exptime int64) (ttl time.Duration, ok bool) {
+	switch {
+	case exptime == 0:
+		return 0, true
+	case exptime < 0:
+		return 0, false
+	case exptime <= maxRelativeExptime:
+		return time.Duration(exptime) * time.Second, true
+	default:
+		ttl = time.Until(time.Unix(exptime, 0))
+		return ttl, ttl > 0
+	}
+}
+
+// 客户端没有指定noreply时写入响应
+func reply(w *bufio.Writer, opts []string, msg string) {
+	if len(opts) == 1 && opts[0] == "noreply" {
+		return
+	}
+
+	w.WriteString(msg)
+	w.WriteString("\r\n")
+}
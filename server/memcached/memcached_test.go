@@ -0,0 +1,130 @@
+package memcached
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"cache/lru"
+)
+
+// 启动服务端并建立一个连接
+func dial(t *testing.T) (*lru.Cache, net.Conn, *bufio.Reader) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := lru.New(int64(0), nil)
+	server := New(c)
+	go server.Serve(lis)
+	t.Cleanup(func() { server.Close() })
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c, conn, bufio.NewReader(conn)
+}
+
+// 发送请求并读取n行响应
+func roundTrip(t *testing.T, conn net.Conn, r *bufio.Reader, request string, n int) string {
+	if _, err := conn.Write([]byte(request)); err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb.WriteString(line)
+	}
+
+	return sb.String()
+}
+
+func TestServer_Commands(t *testing.T) {
+	c, conn, r := dial(t)
+
+	cases := []struct {
+		request string
+		lines   int
+		expect  string
+	}{
+		{"set Tom 5 0 3\r\n630\r\n", 1, "STORED\r\n"},
+		{"set Jack 0 0 3 noreply\r\n589\r\n", 0, ""},
+		{"get Tom Jack Sam\r\n", 5, "VALUE Tom 5 3\r\n630\r\nVALUE Jack 0 3\r\n589\r\nEND\r\n"},
+		{"gets Tom\r\n", 3, "VALUE Tom 5 3 0\r\n630\r\nEND\r\n"},
+		{"delete Jack\r\n", 1, "DELETED\r\n"},
+		{"delete Jack\r\n", 1, "NOT_FOUND\r\n"},
+		{"touch Tom 100\r\n", 1, "TOUCHED\r\n"},
+		{"touch Sam 100\r\n", 1, "NOT_FOUND\r\n"},
+		{"set Sam 0 0 x\r\n", 1, "CLIENT_ERROR bad command line format\r\n"},
+		{"unknown\r\n", 1, "ERROR\r\n"},
+		{"version\r\n", 1, "VERSION 1.0\r\n"},
+	}
+	for _, tc := range cases {
+		if got := roundTrip(t, conn, r, tc.request, tc.lines); got != tc.expect {
+			t.Fatalf("%q: expected %q but got %q", tc.request, tc.expect, got)
+		}
+	}
+
+	if c.Len() != 1 || !c.Contains("Tom") {
+		t.Fatalf("expected only Tom in cache but got %s", c.Keys())
+	}
+}
+
+func TestServer_Expiration(t *testing.T) {
+	c, conn, r := dial(t)
+
+	roundTrip(t, conn, r, "set Tom 0 1 3\r\n630\r\n", 1)
+	roundTrip(t, conn, r, "set Jack 0 0 3\r\n589\r\n", 1)
+
+	// exptime为负数时立即过期
+	roundTrip(t, conn, r, "touch Jack -1\r\n", 1)
+	if c.Contains("Jack") {
+		t.Fatalf("Jack should be expired")
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+	if got := roundTrip(t, conn, r, "get Tom\r\n", 1); got != "END\r\n" {
+		t.Fatalf("Tom should be expired but got %q", got)
+	}
+}
+
+func TestServer_TooLarge(t *testing.T) {
+	for _, count := range []string{"1048577", "9223372036854775807"} {
+		c, conn, r := dial(t)
+
+		// 数据超过最大字节数时返回SERVER_ERROR并关闭连接
+		if got := roundTrip(t, conn, r, "set Tom 0 0 "+count+"\r\n", 1); got != "SERVER_ERROR object too large for cache\r\n" {
+			t.Fatalf("%s: unexpected response %q", count, got)
+		}
+		if _, err := r.ReadString('\n'); err != io.EOF {
+			t.Fatalf("%s: expected connection closed but got %v", count, err)
+		}
+		if c.Len() != 0 {
+			t.Fatalf("%s: expected empty cache but got %s", count, c.Keys())
+		}
+	}
+}
+
+func TestParseExptime(t *testing.T) {
+	if ttl, ok := parseExptime(10); !ok || ttl != 10*time.Second {
+		t.Fatalf("expected relative exptime")
+	}
+
+	if ttl, ok := parseExptime(time.Now().Add(time.Hour).Unix()); !ok || ttl <= 59*time.Minute {
+		t.Fatalf("expected absolute exptime but got %v", ttl)
+	}
+
+	if _, ok := parseExptime(time.Now().Add(-time.Hour).Unix()); ok {
+		t.Fatalf("expected past timestamp to be expired")
+	}
+}
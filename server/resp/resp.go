@@ -0,0 +1,405 @@
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cache/lru"
+	"cache/ratelimit"
+)
+
+// 单个参数的最大字节数，与Redis的proto-max-bulk-len默认值一致
+const maxBulkLength = 512 << 20
+
+// 缓存中存储的value
+type item struct {
+	data []byte
+
+	// 过期时间，零值表示永不过期
+	expire time.Time
+}
+
+// 获取数据的长度
+func (i *item) Len() int {
+	return len(i.data)
+}
+
+// 请求不符合RESP协议，连接会被关闭
+var errProtocol = errors.New("resp: protocol error")
+
+// 使用RESP（Redis协议）提供cache中数据的服务端，支持GET、SET、DEL、EXPIRE、TTL、FLUSHALL、PING与COMMAND命令，
+// redis-cli与标准的Redis客户端可以直接连接
+type Server struct {
+	cache *lru.Cache
+
+	// 命令的限流器，为nil时表示不限流
+	limiter *ratelimit.Limiter
+
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 正在监听的listener
+	listeners map[net.Listener]struct{}
+
+	// 已建立的连接
+	conns map[net.Conn]struct{}
+
+	// 是否已关闭
+	closed bool
+}
+
+// 实例化服务端时的可选配置
+type Option func(s *Server)
+
+// 使用l限制每个客户端与所有客户端合计的命令速率，以连接的来源IP区分客户端，
+// 超过限制的命令不会执行并返回BUSY错误
+func WithLimiter(l *ratelimit.Limiter) Option {
+	return func(s *Server) {
+		s.limiter = l
+	}
+}
+
+// 实例化服务端
+func New(cache *lru.Cache, opts ...Option) *Server {
+	s := &Server{
+		cache:     cache,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// 在lis上接受连接，直到调用Close
+func (s *Server) Serve(lis net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return net.ErrClosed
+	}
+	s.listeners[lis] = struct{}{}
+	s.mu.Unlock()
+
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+// 停止接受连接并关闭所有已建立的连接
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for lis := range s.listeners {
+		lis.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+
+	return nil
+}
+
+// 依次处理连接上的命令，直到连接关闭或请求不符合协议
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	client := ratelimit.ClientOf(conn.RemoteAddr().String())
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			if errors.Is(err, errProtocol) {
+				writeError(w, "ERR Protocol error")
+				w.Flush()
+			} else if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.Println("[RESP] Failed to read command", err)
+			}
+			return
+		}
+
+		switch {
+		case len(args) == 0:
+		case s.limiter != nil && !s.limiter.Allow(client):
+			writeError(w, "BUSY rate limit exceeded")
+		default:
+			s.handle(args, w)
+		}
+
+		// 没有更多已到达的请求时才写入响应，使pipeline中的多个响应可以合并写入
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// 读取一条命令，支持多条批量字符串组成的数组与以空格分隔的内联命令
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n > 1<<20 {
+		return nil, errProtocol
+	}
+
+	args := make([]string, 0, max(n, 0))
+	for i := 0; i < n; i++ {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(header, "$") {
+			return nil, errProtocol
+		}
+
+		length, err := strconv.Atoi(header[1:])
+		if err != nil || length < 0 || length > maxBulkLength {
+			return nil, errProtocol
+		}
+
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		if string(data[length:]) != "\r\n" {
+			return nil, errProtocol
+		}
+		args = append(args, string(data[:length]))
+	}
+
+	return args, nil
+}
+
+// 读取一行并去掉行尾的\r\n
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// 处理一条命令并写入响应
+func (s *Server) handle(args []string, w *bufio.Writer) {
+	switch cmd := strings.ToUpper(args[0]); cmd {
+	case "PING":
+		if len(args) > 1 {
+			writeBulk(w, []byte(args[1]))
+		} else {
+			w.WriteString("+PONG\r\n")
+		}
+	case "GET":
+		if len(args) != 2 {
+			writeArity(w, cmd)
+			return
+		}
+		if it, ok := s.get(args[1]); ok {
+			writeBulk(w, it.data)
+		} else {
+			w.WriteString("$-1\r\n")
+		}
+	case "SET":
+		s.handleSet(args, w)
+	case "DEL":
+		if len(args) < 2 {
+			writeArity(w, cmd)
+			return
+		}
+		writeInteger(w, int64(s.cache.RemoveMulti(args[1:])))
+	case "EXPIRE":
+		s.handleExpire(args, w)
+	case "TTL":
+		if len(args) != 2 {
+			writeArity(w, cmd)
+			return
+		}
+		it, ok := s.get(args[1])
+		switch {
+		case !ok:
+			writeInteger(w, -2)
+		case it.expire.IsZero():
+			writeInteger(w, -1)
+		default:
+			// 与Redis一致，剩余时间向上取整
+			writeInteger(w, int64((time.Until(it.expire)+time.Second-1)/time.Second))
+		}
+	case "FLUSHALL", "FLUSHDB":
+		s.cache.Clear()
+		w.WriteString("+OK\r\n")
+	case "COMMAND":
+		// redis-cli连接时会查询命令文档，返回空数组即可
+		w.WriteString("*0\r\n")
+	default:
+		writeError(w, fmt.Sprintf("ERR unknown command '%s'", args[0]))
+	}
+}
+
+// 查找key对应的条目
+func (s *Server) get(key string) (*item, bool) {
+	value, ok := s.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	return value.(*item), true
+}
+
+// SET key value [EX seconds|PX milliseconds] [NX|XX]
+func (s *Server) handleSet(args []string, w *bufio.Writer) {
+	if len(args) < 3 {
+		writeArity(w, "SET")
+		return
+	}
+
+	var ttl time.Duration
+	var nx, xx bool
+	for i := 3; i < len(args); i++ {
+		switch opt := strings.ToUpper(args[i]); opt {
+		case "EX", "PX":
+			if i+1 >= len(args) {
+				writeError(w, "ERR syntax error")
+				return
+			}
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || n <= 0 {
+				writeError(w, "ERR invalid expire time in 'set' command")
+				return
+			}
+			if opt == "EX" {
+				ttl = time.Duration(n) * time.Second
+			} else {
+				ttl = time.Duration(n) * time.Millisecond
+			}
+			i++
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		default:
+			writeError(w, "ERR syntax error")
+			return
+		}
+	}
+	if nx && xx {
+		writeError(w, "ERR syntax error")
+		return
+	}
+
+	it := &item{data: []byte(args[2])}
+	if ttl > 0 {
+		it.expire = time.Now().Add(ttl)
+	}
+
+	var ok bool
+	switch key := args[1]; {
+	case nx:
+		ok = s.cache.SetIfAbsent(key, it, ttl)
+	case xx:
+		ok = s.cache.SetIfPresent(key, it, ttl)
+	default:
+		s.cache.AddWithTTL(key, it, ttl)
+		ok = true
+	}
+	if !ok {
+		w.WriteString("$-1\r\n")
+		return
+	}
+	w.WriteString("+OK\r\n")
+}
+
+// EXPIRE key seconds
+func (s *Server) handleExpire(args []string, w *bufio.Writer) {
+	if len(args) != 3 {
+		writeArity(w, "EXPIRE")
+		return
+	}
+
+	seconds, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		writeError(w, "ERR value is not an integer or out of range")
+		return
+	}
+
+	value, ok := s.cache.Peek(args[1])
+	if !ok {
+		writeInteger(w, 0)
+		return
+	}
+
+	// 与Redis一致，存活时间不为正数时立即删除
+	if seconds <= 0 {
+		s.cache.Remove(args[1])
+		writeInteger(w, 1)
+		return
+	}
+
+	ttl := time.Duration(seconds) * time.Second
+	it := &item{data: value.(*item).data, expire: time.Now().Add(ttl)}
+	s.cache.AddWithTTL(args[1], it, ttl)
+	writeInteger(w, 1)
+}
+
+// 写入批量字符串
+func writeBulk(w *bufio.Writer, data []byte) {
+	fmt.Fprintf(w, "$%d\r\n", len(data))
+	w.Write(data)
+	w.WriteString("\r\n")
+}
+
+// 写入整数
+func writeInteger(w *bufio.Writer, n int64) {
+	fmt.Fprintf(w, ":%d\r\n", n)
+}
+
+// 写入错误
+func writeError(w *bufio.Writer, msg string) {
+	fmt.Fprintf(w, "-%s\r\n", msg)
+}
+
+// 写入参数数量错误
+func writeArity(w *bufio.Writer, cmd string) {
+	writeError(w, fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(cmd)))
+}
@@ -0,0 +1,129 @@
+package resp
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"cache/lru"
+	"cache/ratelimit"
+)
+
+// 启动服务端并建立一个连接
+func dial(t *testing.T, opts ...Option) (*lru.Cache, net.Conn, *bufio.Reader) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := lru.New(int64(0), nil)
+	server := New(c, opts...)
+	go server.Serve(lis)
+	t.Cleanup(func() { server.Close() })
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c, conn, bufio.NewReader(conn)
+}
+
+// 将参数编码为RESP数组
+func encode(args ...string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&sb, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	return sb.String()
+}
+
+// 发送请求并读取n行响应
+func roundTrip(t *testing.T, conn net.Conn, r *bufio.Reader, request string, n int) string {
+	if _, err := conn.Write([]byte(request)); err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb.WriteString(line)
+	}
+
+	return sb.String()
+}
+
+func TestServer_Commands(t *testing.T) {
+	c, conn, r := dial(t)
+
+	cases := []struct {
+		request string
+		lines   int
+		expect  string
+	}{
+		{encode("PING"), 1, "+PONG\r\n"},
+		{encode("SET", "Tom", "630"), 1, "+OK\r\n"},
+		{encode("SET", "Tom", "700", "NX"), 1, "$-1\r\n"},
+		{encode("SET", "Jack", "589", "XX"), 1, "$-1\r\n"},
+		{encode("SET", "Jack", "589", "EX", "100"), 1, "+OK\r\n"},
+		{encode("GET", "Tom"), 2, "$3\r\n630\r\n"},
+		{encode("GET", "Sam"), 1, "$-1\r\n"},
+		{encode("TTL", "Tom"), 1, ":-1\r\n"},
+		{encode("TTL", "Jack"), 1, ":100\r\n"},
+		{encode("TTL", "Sam"), 1, ":-2\r\n"},
+		{encode("EXPIRE", "Tom", "10"), 1, ":1\r\n"},
+		{encode("TTL", "Tom"), 1, ":10\r\n"},
+		{encode("EXPIRE", "Sam", "10"), 1, ":0\r\n"},
+		{encode("DEL", "Jack", "Sam"), 1, ":1\r\n"},
+		{encode("GET"), 1, "-ERR wrong number of arguments for 'get' command\r\n"},
+		{encode("UNKNOWN"), 1, "-ERR unknown command 'UNKNOWN'\r\n"},
+		{"GET Tom\r\n", 2, "$3\r\n630\r\n"},
+	}
+	for _, tc := range cases {
+		if got := roundTrip(t, conn, r, tc.request, tc.lines); got != tc.expect {
+			t.Fatalf("%q: expected %q but got %q", tc.request, tc.expect, got)
+		}
+	}
+
+	if got := roundTrip(t, conn, r, encode("FLUSHALL"), 1); got != "+OK\r\n" || c.Len() != 0 {
+		t.Fatalf("expected empty cache after FLUSHALL but got %q, %d", got, c.Len())
+	}
+}
+
+func TestServer_Expiration(t *testing.T) {
+	_, conn, r := dial(t)
+
+	roundTrip(t, conn, r, encode("SET", "Tom", "630", "PX", "10"), 1)
+	time.Sleep(20 * time.Millisecond)
+
+	if got := roundTrip(t, conn, r, encode("GET", "Tom"), 1); got != "$-1\r\n" {
+		t.Fatalf("Tom should be expired but got %q", got)
+	}
+}
+
+func TestServer_Pipeline(t *testing.T) {
+	_, conn, r := dial(t)
+
+	request := encode("SET", "Tom", "630") + encode("GET", "Tom") + encode("DEL", "Tom")
+	if got := roundTrip(t, conn, r, request, 4); got != "+OK\r\n$3\r\n630\r\n:1\r\n" {
+		t.Fatalf("unexpected pipeline response %q", got)
+	}
+}
+
+func TestServer_WithLimiter(t *testing.T) {
+	_, conn, r := dial(t, WithLimiter(ratelimit.New(ratelimit.Limit{}, ratelimit.Limit{Rate: 0.001, Burst: 2})))
+
+	// 超过限制的命令返回BUSY错误，连接仍然可用
+	request := encode("SET", "Tom", "630") + encode("GET", "Tom") + encode("GET", "Tom")
+	if got := roundTrip(t, conn, r, request, 4); got != "+OK\r\n$3\r\n630\r\n-BUSY rate limit exceeded\r\n" {
+		t.Fatalf("unexpected response %q", got)
+	}
+}
@@ -0,0 +1,155 @@
+package sharded
+
+import (
+	"hash/maphash"
+	"time"
+
+	"cache/lru"
+)
+
+// 与lru包共用Value接口
+type Value = lru.Value
+
+// 分片缓存，按key的哈希值将条目分散到多个独立加锁的lru.Cache中，以降低锁竞争。
+// 可安全地被多个goroutine并发使用
+type Cache struct {
+	// 哈希种子
+	seed maphash.Seed
+
+	// 分片
+	shards []*lru.Cache
+}
+
+// 实例化分片缓存，capacity为所有分片的总容量，平均分配给每个分片，每个分片至少为1，shards小于1时按1处理
+func New(shards int, capacity int64, onEvicted func(string, Value)) *Cache {
+	shards = max(shards, 1)
+
+	c := &Cache{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*lru.Cache, shards),
+	}
+	for i := range c.shards {
+		c.shards[i] = lru.New(shardCapacity(capacity, shards, i), onEvicted)
+	}
+
+	return c
+}
+
+// 获取第i个分片的容量，不能整除的部分分给前面的分片，capacity不大于0时表示不限制容量
+func shardCapacity(capacity int64, shards, i int) int64 {
+	if capacity <= 0 {
+		return 0
+	}
+
+	n := capacity / int64(shards)
+	if int64(i) < capacity%int64(shards) {
+		n++
+	}
+
+	return max(n, 1)
+}
+
+// 获取key所在的分片
+func (c *Cache) shard(key string) *lru.Cache {
+	return c.shards[maphash.String(c.seed, key)%uint64(len(c.shards))]
+}
+
+// 实现查找功能
+func (c *Cache) Get(key string) (Value, bool) {
+	return c.shard(key).Get(key)
+}
+
+// 查找key对应的value，不会影响条目的淘汰顺序
+func (c *Cache) Peek(key string) (Value, bool) {
+	return c.shard(key).Peek(key)
+}
+
+// 判断key是否存在于缓存中，不会影响条目的淘汰顺序
+func (c *Cache) Contains(key string) bool {
+	return c.shard(key).Contains(key)
+}
+
+// 实现新增与修改功能
+func (c *Cache) Add(key string, value Value) {
+	c.shard(key).Add(key, value)
+}
+
+// 新增或修改条目，并设置其存活时间
+func (c *Cache) AddWithTTL(key string, value Value, ttl time.Duration) {
+	c.shard(key).AddWithTTL(key, value, ttl)
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) bool {
+	return c.shard(key).Remove(key)
+}
+
+// 清除所有分片中已过期的条目，返回被清除的条目数量
+func (c *Cache) RemoveExpired() int {
+	removed := 0
+	for _, shard := range c.shards {
+		removed += shard.RemoveExpired()
+	}
+
+	return removed
+}
+
+// 清空所有分片，并对每个条目调用回调函数
+func (c *Cache) Purge() {
+	for _, shard := range c.shards {
+		shard.Purge()
+	}
+}
+
+// 获取所有分片的条目数量之和，包括已过期但尚未被清除的条目
+func (c *Cache) Len() int {
+	n := 0
+	for _, shard := range c.shards {
+		n += shard.Len()
+	}
+
+	return n
+}
+
+// 获取所有分片已使用的缓存空间之和（单位为字节）
+func (c *Cache) Size() int64 {
+	return c.Stats().Bytes
+}
+
+// 获取所有分片未过期的条目数量之和
+func (c *Cache) LiveLen() int {
+	n := 0
+	for _, shard := range c.shards {
+		n += shard.LiveLen()
+	}
+
+	return n
+}
+
+// 获取所有分片未过期的条目占用的缓存空间之和（单位为字节）
+func (c *Cache) LiveSize() int64 {
+	var size int64
+	for _, shard := range c.shards {
+		size += shard.LiveSize()
+	}
+
+	return size
+}
+
+// 获取所有分片的统计信息之和
+func (c *Cache) Stats() lru.Stats {
+	var total lru.Stats
+	for _, shard := range c.shards {
+		stats := shard.Stats()
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+		total.Adds += stats.Adds
+		total.Updates += stats.Updates
+		total.Evictions += stats.Evictions
+		total.GhostHits += stats.GhostHits
+		total.Bytes += stats.Bytes
+		total.Entries += stats.Entries
+	}
+
+	return total
+}
@@ -0,0 +1,105 @@
+package sharded
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+func TestCache_Get(t *testing.T) {
+	sharded := New(4, int64(0), nil)
+	sharded.Add("peng", String("chang"))
+	if v, ok := sharded.Get("peng"); !ok || string(v.(String)) != "chang" {
+		t.Fatalf("cache hit failed")
+	}
+
+	if _, ok := sharded.Get("liu"); ok {
+		t.Fatalf("cache miss failed")
+	}
+}
+
+func TestShardCapacity(t *testing.T) {
+	cases := []struct {
+		capacity int64
+		shards   int
+		expect   []int64
+	}{
+		{10, 4, []int64{3, 3, 2, 2}},
+		{2, 4, []int64{1, 1, 1, 1}},
+		{8, 4, []int64{2, 2, 2, 2}},
+		{0, 2, []int64{0, 0}},
+	}
+	for _, tc := range cases {
+		for i, expect := range tc.expect {
+			if got := shardCapacity(tc.capacity, tc.shards, i); got != expect {
+				t.Fatalf("shardCapacity(%d, %d, %d) = %d; want %d", tc.capacity, tc.shards, i, got, expect)
+			}
+		}
+	}
+}
+
+func TestCache_Aggregate(t *testing.T) {
+	sharded := New(8, int64(0), nil)
+	for i := 0; i < 100; i++ {
+		sharded.Add(strconv.Itoa(i), String("v"))
+	}
+	for i := 0; i < 100; i++ {
+		sharded.Get(strconv.Itoa(i))
+	}
+	sharded.Remove("0")
+
+	stats := sharded.Stats()
+	if sharded.Len() != 99 || stats.Entries != 99 || stats.Hits != 100 || stats.Adds != 100 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	size := int64(0)
+	for i := 1; i < 100; i++ {
+		size += int64(len(strconv.Itoa(i)) + 1)
+	}
+	if sharded.Size() != size {
+		t.Fatalf("expected size %d but got %d", size, sharded.Size())
+	}
+}
+
+func TestCache_LiveLen(t *testing.T) {
+	sharded := New(4, int64(0), nil)
+	for i := 0; i < 10; i++ {
+		sharded.AddWithTTL(strconv.Itoa(i), String("v"), time.Nanosecond)
+	}
+	sharded.Add("peng", String("chang"))
+	time.Sleep(time.Millisecond)
+
+	if sharded.Len() != 11 || sharded.LiveLen() != 1 || sharded.LiveSize() != int64(len("pengchang")) {
+		t.Fatalf("expected 11 entries with 1 live but got %d, %d", sharded.Len(), sharded.LiveLen())
+	}
+}
+
+func TestCache_Concurrent(t *testing.T) {
+	sharded := New(16, int64(1600), nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				key := strconv.Itoa(i*1000 + j)
+				sharded.Add(key, String("v"))
+				sharded.Get(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if sharded.Size() > 1600 {
+		t.Fatalf("expected size <= 1600 but got %d", sharded.Size())
+	}
+}
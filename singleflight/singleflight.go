@@ -0,0 +1,148 @@
+package singleflight
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// 正在进行中或已经结束的请求
+type call struct {
+	// 请求结束时关闭
+	done chan struct{}
+
+	// 请求的结果
+	val interface{}
+	err error
+
+	// 仍在等待结果的调用方数量
+	waiters int
+
+	// 取消DoContext传给fn的上下文，由Group的mu保护
+	cancel context.CancelFunc
+}
+
+// 管理不同key的请求，保证同一时刻对同一个key只有一个请求在执行
+type Group struct {
+	// 保护m的互斥锁
+	mu sync.Mutex
+
+	// 存储key与进行中的请求映射关系的哈希表，延迟初始化
+	m map[string]*call
+}
+
+// 执行fn并返回其结果。如果同一个key已经有进行中的请求，则等待该请求结束并返回相同的结果，
+// fn不会被重复调用。fn发生panic时发起请求的调用方重新panic，等待的调用方得到*PanicError
+func (g *Group) Do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	c, leader := g.join(key)
+
+	// 调用fn发起请求，或者等待进行中的请求结束
+	if leader {
+		g.run(key, c, fn)
+		if p, ok := c.err.(*PanicError); ok {
+			panic(p)
+		}
+	} else {
+		<-c.done
+	}
+
+	return c.val, c.err
+}
+
+// 与Do相同，但fn在新的goroutine中执行，传给fn的上下文保留发起请求的ctx中的值与截止时间，
+// 但只有等待同一个请求的调用方全部取消后才会被取消，因此一个调用方取消时不影响其他调用方。
+// ctx被取消时立即返回ctx.Err()。fn发生panic时不会使进程退出，所有调用方都得到*PanicError
+func (g *Group) DoContext(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	c, leader := g.join(key)
+	if leader {
+		detached, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		if deadline, ok := ctx.Deadline(); ok {
+			detached, cancel = context.WithDeadline(context.WithoutCancel(ctx), deadline)
+		}
+		g.mu.Lock()
+		c.cancel = cancel
+		g.mu.Unlock()
+
+		go g.run(key, c, func() (interface{}, error) {
+			defer cancel()
+			return fn(detached)
+		})
+	}
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		g.leave(key, c)
+		return nil, ctx.Err()
+	}
+}
+
+// 获取key进行中的请求，没有时新建一个请求，leader表示调用方需要负责执行新建的请求
+func (g *Group) join(key string) (c *call, leader bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.m == nil {
+		g.m = make(map[string]*call)
+	}
+
+	if c, ok := g.m[key]; ok {
+		c.waiters++
+		return c, false
+	}
+
+	c = &call{done: make(chan struct{}), waiters: 1}
+	g.m[key] = c
+
+	return c, true
+}
+
+// 调用方不再等待请求的结果，所有调用方都离开时取消请求并删除记录，之后的调用会重新发起请求
+func (g *Group) leave(key string, c *call) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	c.waiters--
+	if c.waiters == 0 && c.cancel != nil {
+		c.cancel()
+		if g.m[key] == c {
+			delete(g.m, key)
+		}
+	}
+}
+
+// 执行请求并通知等待的调用方，fn发生panic时将其记录为*PanicError，并且总会删除记录并通知调用方
+func (g *Group) run(key string, c *call, fn func() (interface{}, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.val, c.err = nil, &PanicError{Value: r, Stack: debug.Stack()}
+		}
+
+		// 请求结束后删除记录，之后的调用会重新发起请求
+		g.mu.Lock()
+		if g.m[key] == c {
+			delete(g.m, key)
+		}
+		g.mu.Unlock()
+
+		close(c.done)
+	}()
+
+	c.val, c.err = fn()
+}
+
+// fn发生panic时返回给调用方的错误
+type PanicError struct {
+	// recover得到的值
+	Value interface{}
+
+	// 发生panic时的调用栈
+	Stack []byte
+}
+
+// 实现error接口
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("singleflight: panic: %v\n\n%s", p.Value, p.Stack)
+}
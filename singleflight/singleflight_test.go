@@ -0,0 +1,173 @@
+package singleflight
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDo(t *testing.T) {
+	var g Group
+	v, err := g.Do("key", func() (interface{}, error) {
+		return "bar", nil
+	})
+
+	if v != "bar" || err != nil {
+		t.Errorf("Do v = %v, error = %v", v, err)
+	}
+}
+
+func TestDoErr(t *testing.T) {
+	var g Group
+	someErr := errors.New("some error")
+	v, err := g.Do("key", func() (interface{}, error) {
+		return nil, someErr
+	})
+
+	if err != someErr || v != nil {
+		t.Errorf("Do v = %v, error = %v", v, err)
+	}
+}
+
+func TestDoDupSuppress(t *testing.T) {
+	var g Group
+	var calls int32
+	release := make(chan struct{})
+	fn := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "bar", nil
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := g.Do("key", fn); v != "bar" || err != nil {
+				t.Errorf("Do v = %v, error = %v", v, err)
+			}
+		}()
+	}
+
+	// 等待所有goroutine进入Do后再让请求结束
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("number of calls = %d; want 1", got)
+	}
+}
+
+func TestDoContextCancel(t *testing.T) {
+	var g Group
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (interface{}, error) {
+		<-release
+		return "bar", ctx.Err()
+	}
+
+	// 发起请求的调用方取消后，其他调用方仍然得到请求的结果
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := g.DoContext(ctx, "key", fn)
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	done := make(chan interface{}, 1)
+	go func() {
+		v, err := g.DoContext(context.Background(), "key", fn)
+		if err != nil {
+			t.Errorf("DoContext error = %v", err)
+		}
+		done <- v
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Errorf("DoContext error = %v; want %v", err, context.Canceled)
+	}
+	close(release)
+	if v := <-done; v != "bar" {
+		t.Errorf("DoContext v = %v; want bar", v)
+	}
+}
+
+func TestDoContextDeadline(t *testing.T) {
+	var g Group
+
+	// fn的上下文保留发起请求的调用方的截止时间
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	v, err := g.DoContext(ctx, "key", func(ctx context.Context) (interface{}, error) {
+		deadline, _ := ctx.Deadline()
+		return deadline, nil
+	})
+	if err != nil || v != want {
+		t.Errorf("DoContext deadline = %v, error = %v; want %v", v, err, want)
+	}
+
+	// 所有调用方都取消后fn的上下文被取消
+	ctx, cancel = context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	go g.DoContext(ctx, "key", func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return nil, ctx.Err()
+	})
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-canceled:
+		if err != context.Canceled {
+			t.Errorf("fn ctx error = %v; want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("fn ctx should be canceled after all callers left")
+	}
+}
+
+func TestDoPanic(t *testing.T) {
+	var g Group
+	func() {
+		defer func() {
+			if _, ok := recover().(*PanicError); !ok {
+				t.Errorf("Do should re-panic with *PanicError")
+			}
+		}()
+		g.Do("key", func() (interface{}, error) {
+			panic("boom")
+		})
+	}()
+
+	// panic之后记录已被删除，之后的调用会重新发起请求
+	if v, err := g.Do("key", func() (interface{}, error) { return "bar", nil }); v != "bar" || err != nil {
+		t.Errorf("Do v = %v, error = %v", v, err)
+	}
+}
+
+func TestDoContextPanic(t *testing.T) {
+	var g Group
+	v, err := g.DoContext(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+		panic("boom")
+	})
+	var p *PanicError
+	if v != nil || !errors.As(err, &p) || p.Value != "boom" {
+		t.Errorf("DoContext v = %v, error = %v", v, err)
+	}
+
+	if v, err := g.DoContext(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+		return "bar", nil
+	}); v != "bar" || err != nil {
+		t.Errorf("DoContext v = %v, error = %v", v, err)
+	}
+}
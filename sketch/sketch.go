@@ -0,0 +1,170 @@
+package sketch
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+const (
+	// 默认的行数
+	DefaultDepth = 4
+
+	// 默认每个计数器占用的位数，即CM4
+	DefaultCounterBits = 4
+)
+
+// 实例化Sketch时的可选配置
+type Option func(s *Sketch)
+
+// 设置行数，行数越多估算值越接近实际值，但每次计数与估算的开销越大
+func WithDepth(depth int) Option {
+	return func(s *Sketch) {
+		s.depth = max(depth, 1)
+	}
+}
+
+// 设置每个计数器占用的位数，只能为4、8、16或32，计数器达到最大值后不再增加
+func WithCounterBits(n int) Option {
+	return func(s *Sketch) {
+		switch n {
+		case 4, 8, 16, 32:
+			s.counterBits = n
+		default:
+			panic("sketch: counter bits must be 4, 8, 16 or 32")
+		}
+	}
+}
+
+// 每计数n次将所有计数器减半，使过去的热点逐渐冷却，n小于等于0时表示不自动衰减
+func WithDecay(n int) Option {
+	return func(s *Sketch) {
+		s.sampleSize = n
+	}
+}
+
+// 使用指定的哈希种子，种子相同的Sketch对同一个key使用相同的计数器
+func WithSeed(seed maphash.Seed) Option {
+	return func(s *Sketch) {
+		s.seed = seed
+		s.seeded = true
+	}
+}
+
+// count-min sketch，使用固定大小的空间估算每个key的访问频次，估算值不会小于实际值。
+// 计数器按位压缩存储在uint64中，默认每个计数器占用4位。不能被多个goroutine并发使用
+type Sketch struct {
+	// 行数
+	depth int
+
+	// 每行的计数器数量减一，用于代替取模运算
+	mask uint64
+
+	// 每个计数器占用的位数
+	counterBits int
+
+	// 计数器的最大值
+	maxCount uint64
+
+	// 所有行的计数器，第i行第j个计数器位于下标i*(mask+1)+j
+	words []uint64
+
+	// 哈希种子
+	seed maphash.Seed
+
+	// 是否通过WithSeed指定了哈希种子
+	seeded bool
+
+	// 自上次衰减以来的计数次数
+	additions int
+
+	// 计数次数达到该值时，所有计数器减半，为0时表示不自动衰减
+	sampleSize int
+}
+
+// 实例化Sketch，width为每行的计数器数量，向上取整为2的幂，通常取预期的key数量
+func New(width int, opts ...Option) *Sketch {
+	s := &Sketch{
+		depth:       DefaultDepth,
+		counterBits: DefaultCounterBits,
+		mask:        uint64(1)<<bits.Len(uint(max(width, 1)-1)) - 1,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if !s.seeded {
+		s.seed = maphash.MakeSeed()
+	}
+
+	s.maxCount = 1<<s.counterBits - 1
+	perWord := uint64(64 / s.counterBits)
+	s.words = make([]uint64, (uint64(s.depth)*(s.mask+1)+perWord-1)/perWord)
+
+	return s
+}
+
+// 每行的计数器数量
+func (s *Sketch) Width() int {
+	return int(s.mask + 1)
+}
+
+// 增加key的访问频次，开启衰减后计数次数达到阈值时所有计数器减半
+func (s *Sketch) Increment(key string) {
+	h1, h2 := s.hash(key)
+	for i := 0; i < s.depth; i++ {
+		word, shift := s.position(i, h1+uint64(i)*h2)
+		if (s.words[word]>>shift)&s.maxCount < s.maxCount {
+			s.words[word] += 1 << shift
+		}
+	}
+
+	s.additions++
+	if s.sampleSize > 0 && s.additions >= s.sampleSize {
+		s.Halve()
+	}
+}
+
+// 估算key的访问频次，取各行计数器的最小值
+func (s *Sketch) Estimate(key string) uint32 {
+	h1, h2 := s.hash(key)
+	min := s.maxCount
+	for i := 0; i < s.depth; i++ {
+		word, shift := s.position(i, h1+uint64(i)*h2)
+		if count := (s.words[word] >> shift) & s.maxCount; count < min {
+			min = count
+		}
+	}
+
+	return uint32(min)
+}
+
+// 将所有计数器减半
+func (s *Sketch) Halve() {
+	// 每个计数器右移一位后，清除从相邻计数器移入的最高位
+	mask := ^uint64(0) / s.maxCount * (s.maxCount >> 1)
+	for i := range s.words {
+		s.words[i] = (s.words[i] >> 1) & mask
+	}
+
+	s.additions /= 2
+}
+
+// 将所有计数器清零
+func (s *Sketch) Clear() {
+	clear(s.words)
+	s.additions = 0
+}
+
+// 计算第row行下标为hash&mask的计数器所在的uint64的下标与位移
+func (s *Sketch) position(row int, hash uint64) (int, uint) {
+	index := uint64(row)*(s.mask+1) + hash&s.mask
+	perWord := uint64(64 / s.counterBits)
+
+	return int(index / perWord), uint(index%perWord) * uint(s.counterBits)
+}
+
+// 计算key的两个哈希值，用于双重哈希得到每一行的下标
+func (s *Sketch) hash(key string) (uint64, uint64) {
+	h := maphash.String(s.seed, key)
+
+	return h, h>>32 | 1
+}
@@ -0,0 +1,91 @@
+package sketch
+
+import (
+	"hash/maphash"
+	"strconv"
+	"testing"
+)
+
+func TestSketch_Estimate(t *testing.T) {
+	for _, counterBits := range []int{4, 8, 16, 32} {
+		s := New(1024, WithCounterBits(counterBits))
+		for i := 0; i < 100; i++ {
+			for j := 0; j <= i%10; j++ {
+				s.Increment(strconv.Itoa(i))
+			}
+		}
+
+		// 估算值不会小于实际值，也不会超过计数器的最大值
+		for i := 0; i < 100; i++ {
+			want := uint32(min(i%10+1, 1<<counterBits-1))
+			if got := s.Estimate(strconv.Itoa(i)); got < want || got > uint32(1<<counterBits-1) {
+				t.Fatalf("%d bits: expected estimate of %d to be at least %d but got %d", counterBits, i, want, got)
+			}
+		}
+	}
+}
+
+func TestSketch_Saturate(t *testing.T) {
+	s := New(16)
+	for i := 0; i < 100; i++ {
+		s.Increment("k")
+	}
+
+	// 4位计数器达到15后不再增加，也不会进位到相邻的计数器
+	if got := s.Estimate("k"); got != 15 {
+		t.Fatalf("expected saturated estimate 15 but got %d", got)
+	}
+	for i := 0; i < 16; i++ {
+		if key := strconv.Itoa(i); s.Estimate(key) > 15 {
+			t.Fatalf("counter of %s overflowed", key)
+		}
+	}
+}
+
+func TestSketch_Halve(t *testing.T) {
+	s := New(16, WithCounterBits(8))
+	for i := 0; i < 9; i++ {
+		s.Increment("k")
+	}
+
+	s.Halve()
+	if got := s.Estimate("k"); got != 4 {
+		t.Fatalf("expected estimate 4 after halving but got %d", got)
+	}
+
+	s.Clear()
+	if got := s.Estimate("k"); got != 0 {
+		t.Fatalf("expected estimate 0 after clearing but got %d", got)
+	}
+}
+
+func TestSketch_WithDecay(t *testing.T) {
+	s := New(16, WithDecay(10))
+	for i := 0; i < 9; i++ {
+		s.Increment("k")
+	}
+	if got := s.Estimate("k"); got != 9 {
+		t.Fatalf("expected estimate 9 but got %d", got)
+	}
+
+	// 第10次计数后所有计数器减半
+	s.Increment("k")
+	if got := s.Estimate("k"); got != 5 {
+		t.Fatalf("expected estimate 5 after decay but got %d", got)
+	}
+}
+
+func TestSketch_WithSeed(t *testing.T) {
+	seed := maphash.MakeSeed()
+	a, b := New(16, WithSeed(seed)), New(16, WithSeed(seed))
+	for i := 0; i < 16; i++ {
+		a.Increment("k" + strconv.Itoa(i))
+		b.Increment("k" + strconv.Itoa(i))
+	}
+
+	for i := range a.words {
+		if a.words[i] != b.words[i] {
+			t.Fatalf("sketches with the same seed should use the same counters")
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package slab
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/maphash"
+	"math/bits"
+	"sync"
+)
+
+const (
+	// 默认的分片数量
+	DefaultShards = 16
+
+	// 默认的段大小（单位为字节）
+	DefaultSegmentSize = 1 << 20
+
+	// 条目头部的长度：8字节的哈希值、2字节的key长度与4字节的value长度
+	headerSize = 14
+
+	// key的最大长度
+	maxKeyLen = 1<<16 - 1
+)
+
+// 条目序列化后超过段的大小，或key超过最大长度
+var ErrTooLarge = errors.New("slab: entry too large")
+
+// 分片在段之上使用的淘汰策略
+type Policy int
+
+const (
+	// 按写入顺序淘汰，Get只需持有读锁
+	FIFO Policy = iota
+
+	// 命中不在当前段中的条目时将其重新追加到当前段，近似按最近访问顺序淘汰
+	LRU
+)
+
+// 实例化Cache时的可选配置
+type Option func(c *Cache)
+
+// 设置分片数量，向上取整为2的幂次
+func WithShards(n int) Option {
+	return func(c *Cache) {
+		c.shardCount = 1 << bits.Len(uint(max(n, 1)-1))
+	}
+}
+
+// 设置每个段的大小（单位为字节），单个条目序列化后不能超过该大小
+func WithSegmentSize(bytes int) Option {
+	return func(c *Cache) {
+		c.segmentSize = max(bytes, headerSize)
+	}
+}
+
+// 设置淘汰策略，默认为FIFO
+func WithPolicy(policy Policy) Option {
+	return func(c *Cache) {
+		c.policy = policy
+	}
+}
+
+// 将序列化后的条目存储在每个分片的只追加字节段中的cache，索引为哈希值到位置的映射，
+// 不为每个条目创建Go对象，GC不需要扫描其中的数据，适合缓存数GB的数据。
+// 段写满后切换到下一个段，所有段都写满时整段淘汰最早的段。
+// 哈希值冲突的两个key不能同时存在于缓存中，后写入的key会覆盖先写入的key。
+// 可安全地被多个goroutine并发使用
+type Cache struct {
+	// 分片数量
+	shardCount int
+
+	// 每个段的大小
+	segmentSize int
+
+	// 淘汰策略
+	policy Policy
+
+	// 计算key哈希值的种子
+	seed maphash.Seed
+
+	// 计算key的哈希值，测试中可以替换以构造哈希冲突
+	hash func(key string) uint64
+
+	// 分片
+	shards []shard
+}
+
+// 实例化Cache，capacity为缓存的最大容量（单位为字节），平均分配到每个分片，每个分片至少有两个段
+func New(capacity int64, opts ...Option) *Cache {
+	c := &Cache{
+		shardCount:  DefaultShards,
+		segmentSize: DefaultSegmentSize,
+		seed:        maphash.MakeSeed(),
+	}
+	c.hash = func(key string) uint64 {
+		return maphash.String(c.seed, key)
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	segments := max(int(capacity/int64(c.shardCount)/int64(c.segmentSize)), 2)
+	c.shards = make([]shard, c.shardCount)
+	for i := range c.shards {
+		c.shards[i] = shard{
+			index:    make(map[uint64]uint64),
+			segments: make([][]byte, segments),
+		}
+	}
+
+	return c
+}
+
+// 实现查找功能，返回数据的副本
+func (c *Cache) Get(key string) ([]byte, bool) {
+	hash := c.hash(key)
+	s := c.shardOf(hash)
+
+	if c.policy == FIFO {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	} else {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+
+	location, ok := s.index[hash]
+	if !ok {
+		return nil, false
+	}
+
+	entry := s.entry(location)
+	if string(entryKey(entry)) != key {
+		return nil, false
+	}
+	value := append([]byte(nil), entryValue(entry)...)
+
+	if c.policy == LRU && int(location>>32) != s.current {
+		s.index[hash] = s.append(entry, c.segmentSize)
+	}
+
+	return value, true
+}
+
+// 实现新增与修改功能，分片的段都写满时淘汰最早的段
+func (c *Cache) Add(key string, value []byte) error {
+	size := headerSize + len(key) + len(value)
+	if len(key) > maxKeyLen || size > c.segmentSize {
+		return ErrTooLarge
+	}
+
+	hash := c.hash(key)
+	s := c.shardOf(hash)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 被覆盖的条目仍然留在段中，直到所在的段被淘汰
+	delete(s.index, hash)
+
+	entry := s.reserve(size, c.segmentSize)
+	binary.LittleEndian.PutUint64(entry, hash)
+	binary.LittleEndian.PutUint16(entry[8:], uint16(len(key)))
+	binary.LittleEndian.PutUint32(entry[10:], uint32(len(value)))
+	copy(entry[headerSize:], key)
+	copy(entry[headerSize+len(key):], value)
+	s.index[hash] = s.location(size)
+
+	return nil
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) bool {
+	hash := c.hash(key)
+	s := c.shardOf(hash)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	location, ok := s.index[hash]
+	if !ok || string(entryKey(s.entry(location))) != key {
+		return false
+	}
+	delete(s.index, hash)
+
+	return true
+}
+
+// 获取缓存的条目数量
+func (c *Cache) Len() int {
+	n := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.RLock()
+		n += len(s.index)
+		s.mu.RUnlock()
+	}
+
+	return n
+}
+
+// 获取哈希值所属的分片
+func (c *Cache) shardOf(hash uint64) *shard {
+	return &c.shards[hash&uint64(c.shardCount-1)]
+}
+
+// 分片，由多个循环使用的只追加段组成
+type shard struct {
+	// 保护以下所有字段的读写锁
+	mu sync.RWMutex
+
+	// 存储key哈希值与条目位置映射关系的哈希表，位置的高32位为段的下标，低32位为段内的偏移量
+	index map[uint64]uint64
+
+	// 循环使用的段，尚未使用的段为nil
+	segments [][]byte
+
+	// 正在写入的段的下标
+	current int
+}
+
+// 在当前段的末尾预留size个字节，当前段剩余空间不足时切换到下一个段并淘汰其中的条目，调用方需持有写锁
+func (s *shard) reserve(size, segmentSize int) []byte {
+	segment := s.segments[s.current]
+	if segment == nil {
+		segment = make([]byte, 0, segmentSize)
+	} else if len(segment)+size > segmentSize {
+		s.current = (s.current + 1) % len(s.segments)
+		segment = s.evict(s.current, segmentSize)
+	}
+
+	s.segments[s.current] = segment[:len(segment)+size]
+
+	return s.segments[s.current][len(segment):]
+}
+
+// 最近一次预留的size个字节的位置，调用方需持有写锁
+func (s *shard) location(size int) uint64 {
+	return uint64(s.current)<<32 | uint64(len(s.segments[s.current])-size)
+}
+
+// 将已序列化的条目追加到当前段并返回新的位置。
+// entry可能位于将被淘汰的段中，copy允许源与目标重叠，调用方需持有写锁
+func (s *shard) append(entry []byte, segmentSize int) uint64 {
+	copy(s.reserve(len(entry), segmentSize), entry)
+	return s.location(len(entry))
+}
+
+// 淘汰段中仍被索引引用的条目并清空段，返回可重新写入的段，调用方需持有写锁
+func (s *shard) evict(i, segmentSize int) []byte {
+	segment := s.segments[i]
+	for offset := 0; offset < len(segment); {
+		entry := segment[offset:]
+		hash := binary.LittleEndian.Uint64(entry)
+		if s.index[hash] == uint64(i)<<32|uint64(offset) {
+			delete(s.index, hash)
+		}
+		offset += len(entryKey(entry)) + len(entryValue(entry)) + headerSize
+	}
+
+	if segment == nil {
+		return make([]byte, 0, segmentSize)
+	}
+
+	return segment[:0]
+}
+
+// 获取位置对应的条目，调用方需持有锁
+func (s *shard) entry(location uint64) []byte {
+	entry := s.segments[location>>32][location&(1<<32-1):]
+	return entry[:headerSize+len(entryKey(entry))+len(entryValue(entry))]
+}
+
+// 获取条目中的key
+func entryKey(entry []byte) []byte {
+	keyLen := int(binary.LittleEndian.Uint16(entry[8:]))
+	return entry[headerSize : headerSize+keyLen]
+}
+
+// 获取条目中的value
+func entryValue(entry []byte) []byte {
+	keyLen := int(binary.LittleEndian.Uint16(entry[8:]))
+	valueLen := int(binary.LittleEndian.Uint32(entry[10:]))
+	return entry[headerSize+keyLen : headerSize+keyLen+valueLen]
+}
@@ -0,0 +1,103 @@
+package slab
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestCache_Get(t *testing.T) {
+	c := New(0)
+	if err := c.Add("peng", []byte("chang")); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := c.Get("peng"); !ok || string(v) != "chang" {
+		t.Fatalf("cache hit peng=chang failed")
+	}
+	if _, ok := c.Get("key2"); ok {
+		t.Fatalf("cache miss key2 failed")
+	}
+
+	c.Add("peng", []byte("cheng"))
+	if v, _ := c.Get("peng"); string(v) != "cheng" || c.Len() != 1 {
+		t.Fatalf("expected updated value but got %s", v)
+	}
+
+	if !c.Remove("peng") || c.Remove("peng") || c.Len() != 0 {
+		t.Fatalf("Remove failed")
+	}
+
+	if err := c.Add("large", make([]byte, DefaultSegmentSize)); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge but got %v", err)
+	}
+}
+
+// 向只有一个分片、每段可容纳两个条目的cache中写入条目
+func newTestCache(policy Policy) *Cache {
+	return New(2*64, WithShards(1), WithSegmentSize(64), WithPolicy(policy))
+}
+
+func TestCache_FIFO(t *testing.T) {
+	c := newTestCache(FIFO)
+	value := bytes.Repeat([]byte("v"), 16)
+	for i := 0; i < 4; i++ {
+		c.Add("k"+strconv.Itoa(i), value)
+	}
+	c.Get("k0")
+
+	// 所有段都写满后整段淘汰最早的段
+	c.Add("k4", value)
+	if _, ok := c.Get("k0"); ok || c.Len() != 3 {
+		t.Fatalf("expected the oldest segment to be evicted but got %d entries", c.Len())
+	}
+	if _, ok := c.Get("k1"); ok {
+		t.Fatalf("k1 should be evicted with k0")
+	}
+}
+
+func TestCache_LRU(t *testing.T) {
+	c := newTestCache(LRU)
+	value := bytes.Repeat([]byte("v"), 16)
+	for i := 0; i < 3; i++ {
+		c.Add("k"+strconv.Itoa(i), value)
+	}
+
+	// k0不在当前段中，命中后被追加到当前段，淘汰最早的段时得以保留
+	c.Get("k0")
+	c.Add("k3", value)
+	if v, ok := c.Get("k0"); !ok || !bytes.Equal(v, value) {
+		t.Fatalf("recently used k0 should be kept")
+	}
+	if _, ok := c.Get("k1"); ok {
+		t.Fatalf("k1 should be evicted")
+	}
+}
+
+func TestCache_Collision(t *testing.T) {
+	c := New(0)
+	c.hash = func(key string) uint64 {
+		return 1
+	}
+
+	c.Add("k1", []byte("v1"))
+	c.Add("k2", []byte("v2"))
+	if _, ok := c.Get("k1"); ok || c.Remove("k1") {
+		t.Fatalf("k1 should be overwritten by k2 with the same hash")
+	}
+	if v, ok := c.Get("k2"); !ok || string(v) != "v2" {
+		t.Fatalf("expected k2 but got %s", v)
+	}
+}
+
+func BenchmarkCache_Get(b *testing.B) {
+	c := New(1 << 24)
+	for i := 0; i < 1024; i++ {
+		c.Add("key"+strconv.Itoa(i), make([]byte, 100))
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		c.Get("key1")
+	}
+}
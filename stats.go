@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"cache/hotkeys"
+	"cache/lru"
+)
+
+// 命名空间的统计信息
+type Stats struct {
+	// 本地缓存的统计信息
+	Main lru.Stats
+
+	// 热点缓存的统计信息
+	Hot lru.Stats
+}
+
+// 获取统计信息
+func (g *Group) Stats() Stats {
+	return Stats{
+		Main: g.mainCache.Stats(),
+		Hot:  g.hotCache.Stats(),
+	}
+}
+
+// 按请求次数从多到少获取最多n个热点key，未开启热点统计时返回nil
+func (g *Group) HottestKeys(n int) []hotkeys.KeyCount {
+	if g.hotKeys == nil {
+		return nil
+	}
+
+	return g.hotKeys.HottestKeys(n)
+}
+
+// 查找本地缓存与热点缓存中key对应的数据，未命中时不会加载，也不会影响条目的淘汰顺序
+func (g *Group) Peek(key string) (ByteView, bool) {
+	if cached, ok := g.mainCache.Peek(key); ok {
+		return g.viewOf(cached.(*cacheValue))
+	}
+
+	if cached, ok := g.hotCache.Peek(key); ok {
+		return g.viewOf(cached.(*cacheValue))
+	}
+
+	return ByteView{}, false
+}
+
+// 从所有缓存中移除key，不会修改Store中的数据，返回key是否存在于本地缓存或热点缓存中
+func (g *Group) Remove(key string) bool {
+	inMain := g.mainCache.Remove(key)
+	inHot := g.hotCache.Remove(key)
+	if g.negCache != nil {
+		g.negCache.Remove(key)
+	}
+
+	return inMain || inHot
+}
+
+// 清空所有缓存，不会修改Store中的数据
+func (g *Group) Clear() {
+	g.mainCache.Clear()
+	g.hotCache.Clear()
+	if g.negCache != nil {
+		g.negCache.Clear()
+	}
+}
+
+// 调整缓存的容量（单位为字节），其中八分之一用于热点缓存，返回被淘汰的条目数量
+func (g *Group) Resize(cacheBytes int64) int {
+	evicted := g.mainCache.Resize(cacheBytes - cacheBytes/8)
+	evicted += g.hotCache.Resize(cacheBytes / 8)
+	if g.negCache != nil {
+		evicted += g.negCache.Resize(cacheBytes / 8)
+	}
+
+	return evicted
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGroupNames(t *testing.T) {
+	NewGroup("scores-names-b", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return nil, nil
+	}))
+	NewGroup("scores-names-a", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return nil, nil
+	}))
+
+	names := make([]string, 0)
+	for _, name := range GroupNames() {
+		if name == "scores-names-a" || name == "scores-names-b" {
+			names = append(names, name)
+		}
+	}
+	if expect := []string{"scores-names-a", "scores-names-b"}; !reflect.DeepEqual(expect, names) {
+		t.Fatalf("expect names equals to %s but got %s", expect, names)
+	}
+}
+
+func TestGroup_Admin(t *testing.T) {
+	ctx := context.Background()
+	g := NewGroup("scores-admin", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(db[key]), nil
+	}))
+
+	if _, ok := g.Peek("Tom"); ok {
+		t.Fatalf("Peek should not load Tom")
+	}
+
+	g.Get(ctx, "Tom")
+	g.Get(ctx, "Tom")
+	g.Get(ctx, "Jack")
+	if view, ok := g.Peek("Tom"); !ok || view.String() != "630" {
+		t.Fatalf("failed to peek Tom")
+	}
+
+	if stats := g.Stats(); stats.Main.Hits != 1 || stats.Main.Entries != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	if !g.Remove("Tom") || g.Remove("Tom") {
+		t.Fatalf("unexpected result of Remove")
+	}
+
+	// 容量只能容纳一个条目时淘汰多余的条目
+	g.Get(ctx, "Tom")
+	if evicted := g.Resize(int64(len("Tom630") + 1)); evicted != 1 {
+		t.Fatalf("expected 1 evicted entry but got %d", evicted)
+	}
+
+	g.Clear()
+	if stats := g.Stats(); stats.Main.Entries != 0 {
+		t.Fatalf("expected empty cache after Clear but got %+v", stats)
+	}
+}
+
+func TestGroup_HottestKeys(t *testing.T) {
+	ctx := context.Background()
+	g := NewGroup("scores-hot-keys", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(db[key]), nil
+	}), WithHotKeys(2, time.Hour))
+
+	for i := 0; i < 3; i++ {
+		g.Get(ctx, "Tom")
+	}
+	g.GetMulti(ctx, []string{"Tom", "Jack", "Sam", "Jack"})
+
+	keys := g.HottestKeys(2)
+	if len(keys) != 2 || keys[0].Key != "Tom" || keys[0].Count != 4 || keys[1].Key != "Jack" {
+		t.Fatalf("expected Tom and Jack but got %v", keys)
+	}
+
+	if keys := GetGroup("scores-admin").HottestKeys(2); keys != nil {
+		t.Fatalf("expected nil without WithHotKeys but got %v", keys)
+	}
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+)
+
+// 缓存背后的持久化存储，例如数据库或Redis
+type Store interface {
+	// 获取key对应的数据，不存在时返回ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// 写入key对应的数据
+	Set(ctx context.Context, key string, value []byte) error
+
+	// 删除key对应的数据
+	Delete(ctx context.Context, key string) error
+}
+
+// 可以在一次请求中写入多个key的Store，开启写回时按批写入
+type BatchStore interface {
+	Store
+
+	// 写入sets中的数据并删除deletes中的key，两者不会包含相同的key
+	WriteBatch(ctx context.Context, sets map[string][]byte, deletes []string) error
+}
+
+// 开启写穿透：Set与Delete先同步写入store，成功后才更新缓存，保证写入后立即可以读到新的数据
+func WithWriteThrough(store Store) Option {
+	return func(g *Group) {
+		g.store = store
+	}
+}
+
+// 写入key对应的数据并放入本地缓存，开启写穿透时先写入Store，写入失败时不修改缓存；
+// 开启写回时只将写入放入队列，调用Close之后返回ErrWriteBehindClosed
+func (g *Group) Set(ctx context.Context, key string, value []byte) error {
+	if key == "" {
+		return fmt.Errorf("key is required")
+	}
+
+	view := NewByteView(value)
+	if g.writeBehind != nil {
+		if err := g.writeBehind.enqueue(key, writeOp{value: view.b}); err != nil {
+			return err
+		}
+	} else if g.store != nil {
+		if err := g.store.Set(ctx, key, view.b); err != nil {
+			return err
+		}
+	}
+
+	g.forget(key)
+	g.populateMain(key, view)
+
+	return nil
+}
+
+// 删除key对应的数据并从所有缓存中移除，开启写穿透时先从Store删除，删除失败时不修改缓存；
+// 开启写回时只将删除放入队列，调用Close之后返回ErrWriteBehindClosed
+func (g *Group) Delete(ctx context.Context, key string) error {
+	if key == "" {
+		return fmt.Errorf("key is required")
+	}
+
+	if g.writeBehind != nil {
+		if err := g.writeBehind.enqueue(key, writeOp{delete: true}); err != nil {
+			return err
+		}
+	} else if g.store != nil {
+		if err := g.store.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	g.forget(key)
+	g.mainCache.Remove(key)
+
+	return nil
+}
+
+// 从热点缓存与负缓存中移除key
+func (g *Group) forget(key string) {
+	g.hotCache.Remove(key)
+	if g.negCache != nil {
+		g.negCache.Remove(key)
+	}
+}
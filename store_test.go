@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// 使用哈希表实现的Store，用于测试
+type mapStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	err  error
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{data: make(map[string][]byte)}
+}
+
+func (s *mapStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if value, ok := s.data[key]; ok {
+		return value, nil
+	}
+
+	return nil, ErrNotFound
+}
+
+func (s *mapStore) Set(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.data[key] = value
+
+	return nil
+}
+
+func (s *mapStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	delete(s.data, key)
+
+	return nil
+}
+
+func TestGroup_WriteThrough(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore()
+	g := NewGroup("scores-write-through", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return store.Get(ctx, key)
+	}), WithWriteThrough(store))
+
+	if err := g.Set(ctx, "Tom", []byte("630")); err != nil {
+		t.Fatal(err)
+	}
+	if string(store.data["Tom"]) != "630" {
+		t.Fatalf("expected Tom to be persisted")
+	}
+	if view, err := g.Get(ctx, "Tom"); err != nil || view.String() != "630" {
+		t.Fatalf("expected to read own write but got %s, %v", view, err)
+	}
+
+	// 写入存储失败时不修改缓存
+	store.err = errors.New("unavailable")
+	if err := g.Set(ctx, "Tom", []byte("700")); err == nil {
+		t.Fatalf("expected an error from store")
+	}
+	if view, _ := g.Get(ctx, "Tom"); view.String() != "630" {
+		t.Fatalf("cache should not be updated when store fails, got %s", view)
+	}
+
+	store.err = nil
+	if err := g.Delete(ctx, "Tom"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Get(ctx, "Tom"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete but got %v", err)
+	}
+}
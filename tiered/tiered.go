@@ -0,0 +1,140 @@
+package tiered
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"cache/lru"
+)
+
+// 第二级缓存，可以是另一个Cache、磁盘或远程存储
+type Tier interface {
+	Get(key string) (lru.Value, bool)
+	Add(key string, value lru.Value)
+	Remove(key string) bool
+}
+
+// 两级缓存的统计信息
+type Stats struct {
+	// 第一级缓存命中次数
+	L1Hits int64
+
+	// 第二级缓存命中次数
+	L2Hits int64
+
+	// 两级缓存都未命中的次数
+	Misses int64
+
+	// 从第二级缓存提升到第一级缓存的次数
+	Promotions int64
+
+	// 从第一级缓存淘汰并降级到第二级缓存的次数
+	Demotions int64
+}
+
+// 由较小的进程内缓存（L1）与较大、较慢的缓存（L2）组成的两级缓存。
+// 同一个key只存在于其中一级：L2命中时提升到L1，L1淘汰的条目降级到L2
+type Cache struct {
+	l1 *lru.Cache
+	l2 Tier
+
+	// 保证提升、删除与新增在两级之间移动条目时不会交错，L1命中时不需要加锁
+	mu sync.Mutex
+
+	l1Hits     atomic.Int64
+	l2Hits     atomic.Int64
+	misses     atomic.Int64
+	promotions atomic.Int64
+	demotions  atomic.Int64
+}
+
+// 组合l1与l2，会替换l1使用WithEvictionReason设置的回调函数，原有的回调函数在降级之后被调用。
+// 只有因容量不足或调整容量被淘汰的条目会降级到l2，删除与过期的条目不会。之后不应再直接修改l1
+func New(l1 *lru.Cache, l2 Tier) *Cache {
+	c := &Cache{l1: l1, l2: l2}
+
+	var onRemoved func(key string, value lru.Value, reason lru.EvictionReason)
+	onRemoved = l1.SwapEvictionReason(func(key string, value lru.Value, reason lru.EvictionReason) {
+		if reason == lru.EvictedCapacity || reason == lru.EvictedResized {
+			c.l2.Add(key, value)
+			c.demotions.Add(1)
+		}
+
+		if onRemoved != nil {
+			onRemoved(key, value, reason)
+		}
+	})
+
+	return c
+}
+
+// 实现查找功能，依次查找L1与L2，L2命中时提升到L1
+func (c *Cache) Get(key string) (lru.Value, bool) {
+	if value, ok := c.l1.Get(key); ok {
+		c.l1Hits.Add(1)
+		return value, true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 等待锁期间其他调用方可能已经将key提升到L1
+	if value, ok := c.l1.Get(key); ok {
+		c.l1Hits.Add(1)
+		return value, true
+	}
+
+	value, ok := c.l2.Get(key)
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.l2Hits.Add(1)
+
+	c.l2.Remove(key)
+	c.l1.Add(key, value)
+	c.promotions.Add(1)
+
+	return value, true
+}
+
+// 实现新增与修改功能，新的条目加入L1
+func (c *Cache) Add(key string, value lru.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.l2.Remove(key)
+	c.l1.Add(key, value)
+}
+
+// 实现删除功能，返回key是否存在于任意一级中
+func (c *Cache) Remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	inL2 := c.l2.Remove(key)
+	inL1 := c.l1.Remove(key)
+
+	return inL1 || inL2
+}
+
+// 获取第一级缓存
+func (c *Cache) L1() *lru.Cache {
+	return c.l1
+}
+
+// 获取第二级缓存
+func (c *Cache) L2() Tier {
+	return c.l2
+}
+
+// 获取统计信息
+func (c *Cache) Stats() Stats {
+	return Stats{
+		L1Hits:     c.l1Hits.Load(),
+		L2Hits:     c.l2Hits.Load(),
+		Misses:     c.misses.Load(),
+		Promotions: c.promotions.Load(),
+		Demotions:  c.demotions.Load(),
+	}
+}
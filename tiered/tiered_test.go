@@ -0,0 +1,90 @@
+package tiered
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"cache/lru"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+func TestCache_Tiers(t *testing.T) {
+	l1 := lru.New(int64(8), nil)
+	l2 := lru.New(int64(0), nil)
+	c := New(l1, l2)
+
+	// L1只能容纳两个条目，k1被降级到L2
+	c.Add("k1", String("v1"))
+	c.Add("k2", String("v2"))
+	c.Add("k3", String("v3"))
+	if l1.Contains("k1") || !l2.Contains("k1") {
+		t.Fatalf("expected k1 demoted to L2")
+	}
+
+	// L2命中时提升到L1，L1中最久未访问的k2被降级
+	if v, ok := c.Get("k1"); !ok || v.(String) != "v1" {
+		t.Fatalf("cache hit k1 failed")
+	}
+	if !l1.Contains("k1") || l2.Contains("k1") || !l2.Contains("k2") {
+		t.Fatalf("expected k1 promoted and k2 demoted")
+	}
+
+	c.Get("k3")
+	c.Get("k4")
+
+	expect := Stats{L1Hits: 1, L2Hits: 1, Misses: 1, Promotions: 1, Demotions: 2}
+	if stats := c.Stats(); !reflect.DeepEqual(expect, stats) {
+		t.Fatalf("expect stats equals to %+v but got %+v", expect, stats)
+	}
+}
+
+func TestCache_Remove(t *testing.T) {
+	evicted := make([]string, 0)
+	l1 := lru.New(int64(4), func(key string, value lru.Value) {
+		evicted = append(evicted, key)
+	})
+	l2 := lru.New(int64(0), nil)
+	c := New(l1, l2)
+
+	c.Add("k1", String("v1"))
+	c.Add("k2", String("v2"))
+
+	// 删除时不会将条目降级到L2
+	if !c.Remove("k2") || !c.Remove("k1") || c.Remove("k3") {
+		t.Fatalf("unexpected result of Remove")
+	}
+	if l1.Len() != 0 || l2.Len() != 0 {
+		t.Fatalf("expected both tiers empty but got %d, %d", l1.Len(), l2.Len())
+	}
+	if c.Stats().Demotions != 1 {
+		t.Fatalf("expected 1 demotion but got %d", c.Stats().Demotions)
+	}
+
+	// 原有的回调函数仍然被调用
+	if expect := []string{"k1", "k2"}; !reflect.DeepEqual(expect, evicted) {
+		t.Fatalf("expect evicted keys equals to %s but got %s", expect, evicted)
+	}
+}
+
+func TestCache_ExpiredNotDemoted(t *testing.T) {
+	l1 := lru.New(int64(8), nil)
+	l2 := lru.New(int64(0), nil)
+	c := New(l1, l2)
+
+	// 过期的条目直接丢弃，不会降级到L2
+	l1.AddWithTTL("k1", String("v1"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok := c.Get("k1"); ok {
+		t.Fatalf("k1 should be expired")
+	}
+	if l2.Contains("k1") || c.Stats().Demotions != 0 {
+		t.Fatalf("expired k1 should not be demoted")
+	}
+}
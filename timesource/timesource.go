@@ -0,0 +1,56 @@
+// timesource提供可替换的时钟，测试中使用Fake推进时间即可验证过期、统计窗口等与时间相关的行为，无需等待
+package timesource
+
+import (
+	"sync"
+	"time"
+)
+
+// 获取当前时间的时钟
+type Clock interface {
+	Now() time.Time
+}
+
+// 使用系统时间的时钟
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// 只在调用Advance或Set时才会改变的时钟，可安全地被多个goroutine并发使用
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// 实例化当前时间为now的Fake
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// 获取当前时间
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// 将当前时间推进d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+}
+
+// 将当前时间设置为now
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = now
+}
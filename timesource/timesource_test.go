@@ -0,0 +1,28 @@
+package timesource
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFake(start)
+	if !clock.Now().Equal(start) {
+		t.Fatalf("expected %v but got %v", start, clock.Now())
+	}
+
+	clock.Advance(time.Minute)
+	if got := clock.Now().Sub(start); got != time.Minute {
+		t.Fatalf("expected 1m after Advance but got %v", got)
+	}
+
+	clock.Set(start)
+	if !clock.Now().Equal(start) {
+		t.Fatalf("expected %v after Set but got %v", start, clock.Now())
+	}
+
+	if d := time.Since(System.Now()); d < 0 || d > time.Second {
+		t.Fatalf("System clock is off by %v", d)
+	}
+}
@@ -0,0 +1,253 @@
+package tinylfu
+
+import (
+	"sync"
+
+	"cache/linkedlist"
+	"cache/lru"
+	"cache/sketch"
+)
+
+// 与lru包共用Value接口，便于在不同淘汰策略之间切换
+type Value = lru.Value
+
+const (
+	// 窗口LRU占缓存容量的比例
+	windowRatio = 0.01
+
+	// 受保护段占主缓存容量的比例
+	protectedRatio = 0.8
+
+	// 根据缓存容量估算条目数量时假定的平均条目大小（单位为字节）
+	averageEntryBytes = 64
+
+	// count-min sketch每行计数器数量的下限
+	minCounters = 1024
+
+	// 计数次数达到计数器数量的该倍数时，count-min sketch的所有计数器减半
+	sampleRatio = 10
+)
+
+// 条目所在的段
+type segment int
+
+const (
+	// 新条目首先进入窗口LRU
+	window segment = iota
+	// 通过准入过滤进入主缓存，但只被访问过一次的条目
+	probation
+	// 在主缓存中被再次访问过的条目
+	protected
+)
+
+// 链表节点
+type entry struct {
+	key   string
+	value Value
+
+	// 条目占用的字节数
+	cost int64
+
+	// 条目所在的段
+	segment segment
+}
+
+// W-TinyLFU cache：窗口LRU + count-min sketch准入过滤 + SLRU主缓存，可安全地被多个goroutine并发使用
+type Cache struct {
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 缓存的最大容量（单位为字节）
+	capacity int64
+
+	// 三个段各自的容量（单位为字节），主缓存的容量为probation与protected之和
+	capacities [3]int64
+
+	// 三个段，越靠前越是最近访问的
+	segments [3]*linkedlist.List[entry]
+
+	// 三个段各自占用的字节数
+	sizes [3]int64
+
+	// 访问频次估算
+	sketch *sketch.Sketch
+
+	// 存储key与链表节点映射关系的哈希表
+	cache map[string]*linkedlist.Element[entry]
+
+	// 可选的回调函数，在发生缓存条目清除时被执行
+	// 回调在持有锁的情况下执行，因此不能在回调中再调用Cache的方法
+	OnEvicted func(key string, value Value)
+}
+
+// 实例化W-TinyLFU cache，根据缓存容量估算count-min sketch的大小
+func New(capacity int64, onEvicted func(string, Value)) *Cache {
+	return NewWithCounters(capacity, max(int(capacity/averageEntryBytes), minCounters), onEvicted)
+}
+
+// 实例化W-TinyLFU cache，counters为count-min sketch每行的计数器数量，通常取预期条目数量
+func NewWithCounters(capacity int64, counters int, onEvicted func(string, Value)) *Cache {
+	windowCapacity := int64(float64(capacity) * windowRatio)
+	mainCapacity := capacity - windowCapacity
+	protectedCapacity := int64(float64(mainCapacity) * protectedRatio)
+
+	c := &Cache{
+		capacity:   capacity,
+		capacities: [3]int64{windowCapacity, mainCapacity - protectedCapacity, protectedCapacity},
+		sketch:     sketch.New(counters, sketch.WithDecay(sampleRatio*counters)),
+		cache:      make(map[string]*linkedlist.Element[entry]),
+		OnEvicted:  onEvicted,
+	}
+	for i := range c.segments {
+		c.segments[i] = linkedlist.New[entry]()
+	}
+
+	return c
+}
+
+// 实现查找功能
+func (c *Cache) Get(key string) (value Value, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 无论是否命中都记录一次访问，使准入过滤能识别反复出现的key
+	c.sketch.Increment(key)
+
+	if element, ok := c.cache[key]; ok {
+		c.touch(element)
+		return element.Value.value, true
+	}
+
+	return
+}
+
+// 实现新增与修改功能
+func (c *Cache) Add(key string, value Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sketch.Increment(key)
+	cost := int64(len(key)) + int64(value.Len())
+
+	if element, ok := c.cache[key]; ok {
+		keyValue := &element.Value
+		c.sizes[keyValue.segment] += cost - keyValue.cost
+		keyValue.value = value
+		keyValue.cost = cost
+		c.touch(element)
+	} else {
+		c.cache[key] = c.push(&linkedlist.Element[entry]{Value: entry{key: key, value: value, cost: cost}}, window)
+	}
+
+	if c.capacity == 0 {
+		return
+	}
+
+	// 窗口LRU超过容量时，其淘汰的条目作为候选者尝试进入主缓存
+	for c.segments[window].Len() > 0 && c.sizes[window] > c.capacities[window] {
+		c.admit(c.segments[window].Back())
+	}
+
+	// 修改可能使主缓存超过容量，此时直接淘汰主缓存中的条目
+	for c.segments[probation].Len()+c.segments[protected].Len() > 0 && c.sizes[probation]+c.sizes[protected] > c.capacities[probation]+c.capacities[protected] {
+		c.removeElement(c.mainVictim())
+	}
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.cache[key]; ok {
+		c.removeElement(element)
+		return true
+	}
+
+	return false
+}
+
+// 获取缓存的条目数量
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.cache)
+}
+
+// 处理命中的条目：probation中的条目晋升到protected，其余条目移动到所在段的最前面，调用方需持有锁
+func (c *Cache) touch(element *linkedlist.Element[entry]) {
+	keyValue := &element.Value
+	if keyValue.segment != probation {
+		c.segments[keyValue.segment].MoveToFront(element)
+		return
+	}
+
+	c.move(element, protected)
+
+	// protected超过容量时，将其最久未访问的条目降级回probation
+	for c.segments[protected].Len() > 1 && c.sizes[protected] > c.capacities[protected] {
+		c.move(c.segments[protected].Back(), probation)
+	}
+}
+
+// 比较候选者与主缓存淘汰者的访问频次，决定由谁留在缓存中，调用方需持有锁
+func (c *Cache) admit(candidate *linkedlist.Element[entry]) {
+	keyValue := &candidate.Value
+	mainCapacity := c.capacities[probation] + c.capacities[protected]
+
+	for c.sizes[probation]+c.sizes[protected]+keyValue.cost > mainCapacity {
+		victim := c.mainVictim()
+		if victim == nil || keyValue.cost > mainCapacity {
+			c.removeElement(candidate)
+			return
+		}
+
+		// 候选者的访问频次更高时才淘汰主缓存中的条目，否则淘汰候选者
+		if c.sketch.Estimate(keyValue.key) <= c.sketch.Estimate(victim.Value.key) {
+			c.removeElement(candidate)
+			return
+		}
+
+		c.removeElement(victim)
+	}
+
+	c.move(candidate, probation)
+}
+
+// 获取主缓存的淘汰者，优先从probation中选择，调用方需持有锁
+func (c *Cache) mainVictim() *linkedlist.Element[entry] {
+	if victim := c.segments[probation].Back(); victim != nil {
+		return victim
+	}
+
+	return c.segments[protected].Back()
+}
+
+// 将条目插入指定段的最前面，调用方需持有锁
+func (c *Cache) push(element *linkedlist.Element[entry], segment segment) *linkedlist.Element[entry] {
+	element.Value.segment = segment
+	c.sizes[segment] += element.Value.cost
+
+	return c.segments[segment].PushFrontElement(element)
+}
+
+// 将条目移动到指定段的最前面，调用方需持有锁
+func (c *Cache) move(element *linkedlist.Element[entry], segment segment) {
+	keyValue := &element.Value
+	c.segments[keyValue.segment].Remove(element)
+	c.sizes[keyValue.segment] -= keyValue.cost
+	c.push(element, segment)
+}
+
+// 从所在段与哈希表中删除条目并调用回调函数，调用方需持有锁
+func (c *Cache) removeElement(element *linkedlist.Element[entry]) {
+	keyValue := &element.Value
+	c.segments[keyValue.segment].Remove(element)
+	c.sizes[keyValue.segment] -= keyValue.cost
+	delete(c.cache, keyValue.key)
+
+	if c.OnEvicted != nil {
+		c.OnEvicted(keyValue.key, keyValue.value)
+	}
+}
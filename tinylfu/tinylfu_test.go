@@ -0,0 +1,71 @@
+package tinylfu
+
+import (
+	"strconv"
+	"testing"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+func TestCache_Get(t *testing.T) {
+	tinylfu := New(int64(0), nil)
+	tinylfu.Add("peng", String("chang"))
+	if v, ok := tinylfu.Get("peng"); !ok || string(v.(String)) != "chang" {
+		t.Fatalf("cache hit failed")
+	}
+
+	if _, ok := tinylfu.Get("liu"); ok {
+		t.Fatalf("cache miss failed")
+	}
+}
+
+func TestCache_Admission(t *testing.T) {
+	evicted := 0
+	tinylfu := New(int64(400), func(key string, value Value) {
+		evicted++
+	})
+
+	// 热点key被反复访问
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			key := "hot" + strconv.Itoa(j)
+			if _, ok := tinylfu.Get(key); !ok {
+				tinylfu.Add(key, String("v"))
+			}
+		}
+	}
+
+	// 只访问一次的扫描key无法通过准入过滤把热点挤出主缓存
+	for i := 0; i < 1000; i++ {
+		tinylfu.Add("scan"+strconv.Itoa(i), String("v"))
+	}
+
+	for j := 0; j < 10; j++ {
+		if _, ok := tinylfu.Get("hot" + strconv.Itoa(j)); !ok {
+			t.Fatalf("hot key hot%d should survive the scan", j)
+		}
+	}
+
+	size := tinylfu.sizes[window] + tinylfu.sizes[probation] + tinylfu.sizes[protected]
+	if size > tinylfu.capacity || evicted == 0 {
+		t.Fatalf("expected size <= %d with evictions but got %d", tinylfu.capacity, size)
+	}
+}
+
+func TestCache_Remove(t *testing.T) {
+	tinylfu := New(int64(0), nil)
+	tinylfu.Add("key1", String("value1"))
+	tinylfu.Add("key2", String("value2"))
+
+	if !tinylfu.Remove("key1") || tinylfu.Remove("key1") {
+		t.Fatalf("Remove key1 failed")
+	}
+	if tinylfu.Len() != 1 || tinylfu.sizes[window] != int64(len("key2")+len("value2")) {
+		t.Fatalf("expected 1 entry of 10 bytes")
+	}
+}
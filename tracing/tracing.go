@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"cache/lru"
+)
+
+// span属性名
+const (
+	KeyAttribute       = attribute.Key("cache.key")
+	HitAttribute       = attribute.Key("cache.hit")
+	ValueSizeAttribute = attribute.Key("cache.value_size")
+	PeerAttribute      = attribute.Key("cache.peer")
+)
+
+// 为lru.Cache的操作创建OpenTelemetry span的包装器
+type Cache struct {
+	cache  *lru.Cache
+	tracer trace.Tracer
+}
+
+// 使用tracer包装cache
+func Wrap(cache *lru.Cache, tracer trace.Tracer) *Cache {
+	return &Cache{cache: cache, tracer: tracer}
+}
+
+// 获取被包装的cache
+func (c *Cache) Unwrap() *lru.Cache {
+	return c.cache
+}
+
+// 实现查找功能，span中记录是否命中以及value的大小
+func (c *Cache) Get(ctx context.Context, key string) (lru.Value, bool) {
+	_, span := c.tracer.Start(ctx, "cache.Get", trace.WithAttributes(KeyAttribute.String(key)))
+	defer span.End()
+
+	value, ok := c.cache.Get(key)
+	span.SetAttributes(HitAttribute.Bool(ok))
+	if ok {
+		span.SetAttributes(ValueSizeAttribute.Int(value.Len()))
+	}
+
+	return value, ok
+}
+
+// 实现新增与修改功能
+func (c *Cache) Add(ctx context.Context, key string, value lru.Value) {
+	_, span := c.tracer.Start(ctx, "cache.Add", trace.WithAttributes(KeyAttribute.String(key), ValueSizeAttribute.Int(value.Len())))
+	defer span.End()
+
+	c.cache.Add(key, value)
+}
+
+// 查找key对应的value，未命中时在子span中调用loader加载并加入缓存。
+// 从远程节点加载时，loader可以调用StartPeerFetch记录节点地址
+func (c *Cache) Load(ctx context.Context, key string, loader func(ctx context.Context) (lru.Value, error)) (lru.Value, error) {
+	ctx, span := c.tracer.Start(ctx, "cache.Load", trace.WithAttributes(KeyAttribute.String(key)))
+	defer span.End()
+
+	if value, ok := c.cache.Get(key); ok {
+		span.SetAttributes(HitAttribute.Bool(true), ValueSizeAttribute.Int(value.Len()))
+		return value, nil
+	}
+	span.SetAttributes(HitAttribute.Bool(false))
+
+	value, err := c.startLoad(ctx, key, loader)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(ValueSizeAttribute.Int(value.Len()))
+	c.cache.Add(key, value)
+
+	return value, nil
+}
+
+// 在子span中调用loader
+func (c *Cache) startLoad(ctx context.Context, key string, loader func(ctx context.Context) (lru.Value, error)) (lru.Value, error) {
+	ctx, span := c.tracer.Start(ctx, "cache.Loader", trace.WithAttributes(KeyAttribute.String(key)))
+	defer span.End()
+
+	value, err := loader(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return value, err
+}
+
+// 为从远程节点获取数据创建span，记录节点地址，调用方需在获取结束后调用span.End
+func StartPeerFetch(ctx context.Context, tracer trace.Tracer, peer, key string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "cache.PeerFetch",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(KeyAttribute.String(key), PeerAttribute.String(peer)))
+}
@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"cache/lru"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+// 获取span中指定属性的值
+func attributeOf(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func TestCache_Load(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	c := Wrap(lru.New(int64(0), nil), provider.Tracer("test"))
+
+	loads := 0
+	loader := func(ctx context.Context) (lru.Value, error) {
+		loads++
+		return String("chang"), nil
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if v, err := c.Load(ctx, "peng", loader); err != nil || string(v.(String)) != "chang" {
+			t.Fatalf("Load failed")
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("loader should be called once but got %d", loads)
+	}
+
+	// 第一次Load未命中并调用loader，第二次Load命中
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans but got %d", len(spans))
+	}
+	if hit, _ := attributeOf(spans[1], HitAttribute); spans[1].Name() != "cache.Load" || hit.AsBool() {
+		t.Fatalf("first Load should be a miss")
+	}
+	if hit, _ := attributeOf(spans[2], HitAttribute); !hit.AsBool() {
+		t.Fatalf("second Load should be a hit")
+	}
+	if size, _ := attributeOf(spans[2], ValueSizeAttribute); size.AsInt64() != 5 {
+		t.Fatalf("expected value size 5 but got %d", size.AsInt64())
+	}
+}
+
+func TestCache_LoadError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	c := Wrap(lru.New(int64(0), nil), provider.Tracer("test"))
+
+	_, err := c.Load(context.Background(), "peng", func(ctx context.Context) (lru.Value, error) {
+		return nil, errors.New("not found")
+	})
+	if err == nil || c.Unwrap().Len() != 0 {
+		t.Fatalf("Load should return the loader error")
+	}
+
+	for _, span := range recorder.Ended() {
+		if len(span.Events()) == 0 {
+			t.Fatalf("span %s should record the error", span.Name())
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package twoq
+
+import (
+	"sync"
+
+	"cache/linkedlist"
+	"cache/lru"
+)
+
+// 与lru包共用Value接口，便于在不同淘汰策略之间切换
+type Value = lru.Value
+
+const (
+	// A1in队列默认占缓存容量的比例
+	DefaultInRatio = 0.25
+
+	// A1out队列默认可记录的字节数占缓存容量的比例
+	DefaultOutRatio = 0.5
+)
+
+// 条目所在的队列
+type queue int
+
+const (
+	// 首次进入缓存的条目，先进先出
+	a1in queue = iota
+	// 最近从A1in中淘汰的key（只保留key，不保留value），先进先出
+	a1out
+	// 被再次访问过的热点条目，按LRU淘汰
+	am
+)
+
+// 链表节点
+type entry struct {
+	key   string
+	value Value
+
+	// 条目占用的字节数，幽灵条目也保留该值以便限制A1out的大小
+	cost int64
+
+	// 条目所在的队列
+	queue queue
+}
+
+// 2Q cache，扫描操作只会经过A1in队列而不会污染热点条目，可安全地被多个goroutine并发使用
+type Cache struct {
+	// 保护以下所有字段的互斥锁
+	mu sync.Mutex
+
+	// 缓存的最大容量（单位为字节）
+	capacity int64
+
+	// A1in队列的目标大小（单位为字节）
+	inCapacity int64
+
+	// A1out队列最多记录的字节数
+	outCapacity int64
+
+	// 三个队列，越靠前越是最近加入或访问的
+	queues [3]*linkedlist.List[entry]
+
+	// 三个队列各自占用的字节数
+	sizes [3]int64
+
+	// 存储key与链表节点映射关系的哈希表，包含幽灵条目
+	cache map[string]*linkedlist.Element[entry]
+
+	// 可选的回调函数，在发生缓存条目清除时被执行
+	// 回调在持有锁的情况下执行，因此不能在回调中再调用Cache的方法
+	OnEvicted func(key string, value Value)
+}
+
+// 使用默认的队列比例实例化2Q cache
+func New(capacity int64, onEvicted func(string, Value)) *Cache {
+	return NewWithRatios(capacity, DefaultInRatio, DefaultOutRatio, onEvicted)
+}
+
+// 实例化2Q cache，inRatio与outRatio分别为A1in与A1out队列占缓存容量的比例
+func NewWithRatios(capacity int64, inRatio, outRatio float64, onEvicted func(string, Value)) *Cache {
+	c := &Cache{
+		capacity:    capacity,
+		inCapacity:  int64(float64(capacity) * inRatio),
+		outCapacity: int64(float64(capacity) * outRatio),
+		cache:       make(map[string]*linkedlist.Element[entry]),
+		OnEvicted:   onEvicted,
+	}
+	for i := range c.queues {
+		c.queues[i] = linkedlist.New[entry]()
+	}
+
+	return c
+}
+
+// 实现查找功能
+func (c *Cache) Get(key string) (value Value, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.cache[key]
+	if !ok {
+		return nil, false
+	}
+
+	keyValue := &element.Value
+	switch keyValue.queue {
+	case am:
+		c.queues[am].MoveToFront(element)
+	case a1out:
+		// 幽灵条目不保存value，视为未命中
+		return nil, false
+	}
+
+	// A1in中的条目被命中时不调整位置，避免短时间内的重复访问被误认为热点
+	return keyValue.value, true
+}
+
+// 实现新增与修改功能
+func (c *Cache) Add(key string, value Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cost := int64(len(key)) + int64(value.Len())
+
+	if element, ok := c.cache[key]; ok {
+		keyValue := &element.Value
+		c.sizes[keyValue.queue] += cost - keyValue.cost
+		keyValue.value = value
+		keyValue.cost = cost
+
+		switch keyValue.queue {
+		case am:
+			c.queues[am].MoveToFront(element)
+		case a1out:
+			// 最近被淘汰过又再次加入的key是热点，直接放入Am
+			c.move(element, am)
+		}
+	} else {
+		c.cache[key] = c.push(&linkedlist.Element[entry]{Value: entry{key: key, value: value, cost: cost}}, a1in)
+	}
+
+	c.reclaim()
+}
+
+// 实现删除功能，返回key是否存在于缓存中
+func (c *Cache) Remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.cache[key]
+	if !ok {
+		return false
+	}
+
+	keyValue := &element.Value
+	c.queues[keyValue.queue].Remove(element)
+	c.sizes[keyValue.queue] -= keyValue.cost
+	delete(c.cache, key)
+
+	// 幽灵条目不属于缓存内容，删除时不调用回调函数
+	if keyValue.queue == a1out {
+		return false
+	}
+
+	if c.OnEvicted != nil {
+		c.OnEvicted(key, keyValue.value)
+	}
+
+	return true
+}
+
+// 获取缓存的条目数量，不包含幽灵条目
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.queues[a1in].Len() + c.queues[am].Len()
+}
+
+// 将条目插入指定队列的最前面，调用方需持有锁
+func (c *Cache) push(element *linkedlist.Element[entry], queue queue) *linkedlist.Element[entry] {
+	element.Value.queue = queue
+	c.sizes[queue] += element.Value.cost
+
+	return c.queues[queue].PushFrontElement(element)
+}
+
+// 将条目移动到指定队列的最前面，调用方需持有锁
+func (c *Cache) move(element *linkedlist.Element[entry], queue queue) {
+	keyValue := &element.Value
+	c.queues[keyValue.queue].Remove(element)
+	c.sizes[keyValue.queue] -= keyValue.cost
+	c.push(element, queue)
+}
+
+// 当缓存大小超过容量时淘汰条目，A1in超过目标大小时优先淘汰A1in，调用方需持有锁
+func (c *Cache) reclaim() {
+	for c.capacity != 0 && c.capacity < c.sizes[a1in]+c.sizes[am] {
+		from := am
+		if c.queues[a1in].Len() > 0 && (c.sizes[a1in] > c.inCapacity || c.queues[am].Len() == 0) {
+			from = a1in
+		}
+
+		element := c.queues[from].Back()
+		keyValue := &element.Value
+		value := keyValue.value
+
+		if from == a1in {
+			// 从A1in中淘汰的key记录到A1out中
+			keyValue.value = nil
+			c.move(element, a1out)
+			c.trimOut()
+		} else {
+			c.queues[am].Remove(element)
+			c.sizes[am] -= keyValue.cost
+			delete(c.cache, keyValue.key)
+		}
+
+		if c.OnEvicted != nil {
+			c.OnEvicted(keyValue.key, value)
+		}
+	}
+}
+
+// 限制A1out队列的大小，调用方需持有锁
+func (c *Cache) trimOut() {
+	for c.queues[a1out].Len() > 0 && c.sizes[a1out] > c.outCapacity {
+		element := c.queues[a1out].Back()
+		keyValue := &element.Value
+		c.queues[a1out].Remove(element)
+		c.sizes[a1out] -= keyValue.cost
+		delete(c.cache, keyValue.key)
+	}
+}
@@ -0,0 +1,86 @@
+package twoq
+
+import (
+	"reflect"
+	"testing"
+)
+
+type String string
+
+// 获取字符串的长度
+func (s String) Len() int {
+	return len(s)
+}
+
+func TestCache_Get(t *testing.T) {
+	twoq := New(int64(0), nil)
+	twoq.Add("peng", String("chang"))
+	if v, ok := twoq.Get("peng"); !ok || string(v.(String)) != "chang" {
+		t.Fatalf("cache hit failed")
+	}
+
+	if _, ok := twoq.Get("liu"); ok {
+		t.Fatalf("cache miss failed")
+	}
+}
+
+func TestOnEvicted(t *testing.T) {
+	keys := make([]string, 0)
+	callback := func(key string, value Value) {
+		keys = append(keys, key)
+	}
+	twoq := New(int64(10), callback)
+	twoq.Add("key1", String("123456"))
+	twoq.Add("k2", String("k2"))
+	twoq.Add("k3", String("k3"))
+	twoq.Add("k4", String("k4"))
+
+	expect := []string{"key1", "k2"}
+
+	if !reflect.DeepEqual(expect, keys) {
+		t.Fatalf("Call OnEvicted failed, expect keys equals to %s but got %s", expect, keys)
+	}
+}
+
+func TestCache_HotKeysSurviveScan(t *testing.T) {
+	twoq := New(int64(40), nil)
+
+	// k1与k2被淘汰后再次加入，进入Am
+	twoq.Add("k1", String("vv"))
+	twoq.Add("k2", String("vv"))
+	for _, key := range []string{"s1", "s2", "s3", "s4", "s5", "s6", "s7", "s8", "s9"} {
+		twoq.Add(key, String("vv"))
+	}
+	twoq.Add("k1", String("vv"))
+	twoq.Add("k2", String("vv"))
+	if twoq.cache["k1"].Value.queue != am || twoq.cache["k2"].Value.queue != am {
+		t.Fatalf("k1 and k2 should be promoted to Am")
+	}
+
+	// 扫描key只会在A1in中相互淘汰
+	for _, key := range []string{"t1", "t2", "t3", "t4", "t5", "t6", "t7", "t8", "t9", "t10"} {
+		twoq.Add(key, String("vv"))
+	}
+
+	for _, key := range []string{"k1", "k2"} {
+		if _, ok := twoq.Get(key); !ok {
+			t.Fatalf("hot key %s should survive the scan", key)
+		}
+	}
+	if twoq.sizes[a1out] > twoq.outCapacity {
+		t.Fatalf("A1out size %d exceeds %d", twoq.sizes[a1out], twoq.outCapacity)
+	}
+}
+
+func TestCache_Remove(t *testing.T) {
+	twoq := New(int64(0), nil)
+	twoq.Add("key1", String("value1"))
+	twoq.Add("key2", String("value2"))
+
+	if !twoq.Remove("key1") || twoq.Remove("key1") {
+		t.Fatalf("Remove key1 failed")
+	}
+	if twoq.Len() != 1 || twoq.sizes[a1in] != int64(len("key2")+len("value2")) {
+		t.Fatalf("expected 1 entry of 10 bytes")
+	}
+}
@@ -0,0 +1,261 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// 调用Close之后不再接受写回的写入
+var ErrWriteBehindClosed = errors.New("cache: write-behind closed")
+
+// 写回模式的配置，字段为零值时使用默认值
+type WriteBehind struct {
+	// 两次定期写入之间的间隔，默认为1秒
+	Interval time.Duration
+
+	// 每批写入的最大条目数量，队列中的条目达到该数量时立即写入，默认为100
+	BatchSize int
+
+	// 写入失败时的最大重试次数，默认为3，小于0时表示不重试
+	MaxRetries int
+
+	// 第一次重试前的等待时间，之后每次重试翻倍，默认为100毫秒
+	RetryBackoff time.Duration
+}
+
+// 开启写回：Set与Delete只更新缓存并将写入放入队列，由后台goroutine定期写入store。
+// store实现BatchStore时每批在一次WriteBatch中写入，整批一起重试；否则逐个key写入并各自重试，
+// 一个key写入失败时其后的key需要等待其重试结束。同一个key在写入前的多次修改只会写入最后一次。
+// 开启后需调用Close将队列写完并停止后台goroutine
+func WithWriteBehind(store Store, config WriteBehind) Option {
+	return func(g *Group) {
+		if config.Interval <= 0 {
+			config.Interval = time.Second
+		}
+		if config.BatchSize <= 0 {
+			config.BatchSize = 100
+		}
+		if config.MaxRetries == 0 {
+			config.MaxRetries = 3
+		}
+		if config.RetryBackoff <= 0 {
+			config.RetryBackoff = 100 * time.Millisecond
+		}
+
+		g.writeBehind = &writeBehind{
+			store:   store,
+			config:  config,
+			pending: make(map[string]writeOp),
+			kick:    make(chan struct{}, 1),
+			stop:    make(chan struct{}),
+			done:    make(chan struct{}),
+		}
+	}
+}
+
+// 将写回队列中的所有写入立即写入Store，返回写入失败的错误。未开启写回时不做任何事
+func (g *Group) Flush(ctx context.Context) error {
+	if g.writeBehind == nil {
+		return nil
+	}
+
+	return g.writeBehind.flush(ctx)
+}
+
+// 停止写回的后台goroutine，并将队列中剩余的写入全部写入Store。未开启写回时不做任何事
+func (g *Group) Close(ctx context.Context) error {
+	if g.writeBehind == nil {
+		return nil
+	}
+
+	g.writeBehind.once.Do(func() {
+		g.writeBehind.mu.Lock()
+		g.writeBehind.closed = true
+		g.writeBehind.mu.Unlock()
+
+		close(g.writeBehind.stop)
+	})
+	<-g.writeBehind.done
+
+	return g.writeBehind.flush(ctx)
+}
+
+// 等待写入Store的操作
+type writeOp struct {
+	value []byte
+
+	// 为true时表示删除
+	delete bool
+}
+
+// 写回队列与后台写入的goroutine
+type writeBehind struct {
+	store  Store
+	config WriteBehind
+
+	// 保护pending、order与closed的互斥锁
+	mu sync.Mutex
+
+	// 是否已调用Close
+	closed bool
+
+	// 存储key与等待写入的操作映射关系的哈希表
+	pending map[string]writeOp
+
+	// 按加入队列的顺序排列的key
+	order []string
+
+	// 保证同一时刻只有一个写入过程
+	flushMu sync.Mutex
+
+	// 队列中的条目达到BatchSize时通知后台goroutine
+	kick chan struct{}
+
+	// 关闭时通知后台goroutine退出
+	stop chan struct{}
+
+	// 后台goroutine退出后被关闭
+	done chan struct{}
+
+	// 保证stop只被关闭一次
+	once sync.Once
+}
+
+// 将操作放入队列，覆盖同一个key尚未写入的操作，调用Close之后返回ErrWriteBehindClosed
+func (w *writeBehind) enqueue(key string, op writeOp) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriteBehindClosed
+	}
+	if _, ok := w.pending[key]; !ok {
+		w.order = append(w.order, key)
+	}
+	w.pending[key] = op
+	full := len(w.order) >= w.config.BatchSize
+	w.mu.Unlock()
+
+	if full {
+		select {
+		case w.kick <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// 定期或在队列写满一批时写入Store，直到stop被关闭
+func (w *writeBehind) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.kick:
+		case <-w.stop:
+			return
+		}
+
+		if err := w.flush(context.Background()); err != nil {
+			log.Println("[Cache] Failed to write behind", err)
+		}
+	}
+}
+
+// 按批写入队列中的所有操作，写入失败的操作在重试之后被丢弃，返回所有写入失败的错误
+func (w *writeBehind) flush(ctx context.Context) error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	var errs []error
+	for {
+		keys, ops := w.take(w.config.BatchSize)
+		if len(keys) == 0 {
+			return errors.Join(errs...)
+		}
+
+		if batch, ok := w.store.(BatchStore); ok {
+			if err := w.writeBatch(ctx, batch, keys, ops); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		for i, key := range keys {
+			if err := w.write(ctx, key, ops[i]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+}
+
+// 从队列中取出最多n个操作
+func (w *writeBehind) take(n int) ([]string, []writeOp) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n = min(n, len(w.order))
+	keys := w.order[:n:n]
+	w.order = w.order[n:]
+
+	ops := make([]writeOp, n)
+	for i, key := range keys {
+		ops[i] = w.pending[key]
+		delete(w.pending, key)
+	}
+
+	return keys, ops
+}
+
+// 将一个操作写入Store，失败时按配置重试
+func (w *writeBehind) write(ctx context.Context, key string, op writeOp) error {
+	return w.retry(ctx, func() error {
+		if op.delete {
+			return w.store.Delete(ctx, key)
+		}
+		return w.store.Set(ctx, key, op.value)
+	})
+}
+
+// 将一批操作在一次WriteBatch中写入，失败时按配置重试整批
+func (w *writeBehind) writeBatch(ctx context.Context, store BatchStore, keys []string, ops []writeOp) error {
+	sets := make(map[string][]byte, len(keys))
+	var deletes []string
+	for i, key := range keys {
+		if ops[i].delete {
+			deletes = append(deletes, key)
+		} else {
+			sets[key] = ops[i].value
+		}
+	}
+
+	return w.retry(ctx, func() error {
+		return store.WriteBatch(ctx, sets, deletes)
+	})
+}
+
+// 调用write，失败时按配置重试
+func (w *writeBehind) retry(ctx context.Context, write func() error) error {
+	backoff := w.config.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := write()
+		if err == nil || attempt >= w.config.MaxRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
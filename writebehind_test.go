@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroup_WriteBehind(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore()
+	g := NewGroup("scores-write-behind", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return store.Get(ctx, key)
+	}), WithWriteBehind(store, WriteBehind{Interval: time.Hour, BatchSize: 2}))
+
+	// 写入立即可以从缓存读到，但尚未写入存储
+	g.Set(ctx, "Tom", []byte("600"))
+	g.Set(ctx, "Tom", []byte("630"))
+	if view, _ := g.Get(ctx, "Tom"); view.String() != "630" {
+		t.Fatalf("expected to read own write but got %s", view)
+	}
+	store.mu.Lock()
+	if _, ok := store.data["Tom"]; ok {
+		t.Fatalf("Tom should not be persisted yet")
+	}
+	store.mu.Unlock()
+
+	// 队列中的条目达到BatchSize时立即写入
+	g.Set(ctx, "Jack", []byte("589"))
+	deadline := time.Now().Add(time.Second)
+	for {
+		store.mu.Lock()
+		n := len(store.data)
+		store.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a batch flush")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// 关闭时写完队列中剩余的写入
+	g.Set(ctx, "Sam", []byte("567"))
+	g.Delete(ctx, "Jack")
+	if err := g.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if string(store.data["Tom"]) != "630" || string(store.data["Sam"]) != "567" || store.data["Jack"] != nil {
+		t.Fatalf("unexpected store contents %v", store.data)
+	}
+}
+
+func TestGroup_WriteBehindRetry(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore()
+	store.err = errors.New("unavailable")
+	g := NewGroup("scores-write-behind-retry", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return store.Get(ctx, key)
+	}), WithWriteBehind(store, WriteBehind{Interval: time.Hour, MaxRetries: 2, RetryBackoff: 10 * time.Millisecond}))
+	defer g.Close(ctx)
+
+	g.Set(ctx, "Tom", []byte("630"))
+	if err := g.Flush(ctx); err == nil {
+		t.Fatalf("expected an error after retries")
+	}
+
+	// 重试期间存储恢复时写入成功
+	g.Set(ctx, "Jack", []byte("589"))
+	go func() {
+		time.Sleep(time.Millisecond)
+		store.mu.Lock()
+		store.err = nil
+		store.mu.Unlock()
+	}()
+	if err := g.Flush(ctx); err != nil {
+		t.Fatalf("expected retry to succeed but got %v", err)
+	}
+}
+
+// 记录WriteBatch调用次数的存储
+type batchStore struct {
+	*mapStore
+	batches int
+}
+
+func (s *batchStore) WriteBatch(ctx context.Context, sets map[string][]byte, deletes []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.batches++
+	if s.err != nil {
+		return s.err
+	}
+	for key, value := range sets {
+		s.data[key] = value
+	}
+	for _, key := range deletes {
+		delete(s.data, key)
+	}
+
+	return nil
+}
+
+func TestGroup_WriteBehindBatch(t *testing.T) {
+	ctx := context.Background()
+	store := &batchStore{mapStore: newMapStore()}
+	store.data["Jack"] = []byte("589")
+	g := NewGroup("scores-write-behind-batch", 2<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return store.Get(ctx, key)
+	}), WithWriteBehind(store, WriteBehind{Interval: time.Hour, BatchSize: 10}))
+
+	// 实现BatchStore时一批写入只调用一次WriteBatch
+	g.Set(ctx, "Tom", []byte("630"))
+	g.Set(ctx, "Sam", []byte("567"))
+	g.Delete(ctx, "Jack")
+	if err := g.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if store.batches != 1 || len(store.data) != 2 || store.data["Jack"] != nil {
+		t.Fatalf("expected 1 batch but got %d, %v", store.batches, store.data)
+	}
+
+	// 关闭之后不再接受写入，也不修改缓存
+	if err := g.Set(ctx, "Bob", []byte("1")); !errors.Is(err, ErrWriteBehindClosed) {
+		t.Fatalf("expected ErrWriteBehindClosed but got %v", err)
+	}
+	if err := g.Delete(ctx, "Tom"); !errors.Is(err, ErrWriteBehindClosed) {
+		t.Fatalf("expected ErrWriteBehindClosed but got %v", err)
+	}
+	if _, ok := g.Peek("Tom"); !ok {
+		t.Fatalf("Tom should stay in cache after rejected delete")
+	}
+}